@@ -0,0 +1,135 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"yail/ast"
+	"yail/token"
+)
+
+const (
+	UNUSED_VARIABLE      = "unused-variable"
+	VAL_REASSIGNMENT     = "val-reassignment"
+	UNREACHABLE_CODE     = "unreachable-code"
+	SHADOWED_NAME        = "shadowed-name"
+	UNDEFINED_IDENTIFIER = "undefined-identifier"
+	BUILTIN_ARITY        = "builtin-arity"
+
+	// IGNORE_DIRECTIVE is the comment suppressing the given rules, or all of them, on its line and the next one.
+	IGNORE_DIRECTIVE = "lint:ignore"
+	// FILE_IGNORE_DIRECTIVE is the comment suppressing the given rules, or all of them, in the whole program.
+	FILE_IGNORE_DIRECTIVE = "lint:file-ignore"
+)
+
+// Rule is a check run by Analyze.
+type Rule struct {
+	Name        string
+	Description string
+}
+
+// Rules are all the rules, which Analyze runs by default.
+var Rules = []Rule{
+	{UNUSED_VARIABLE, "variables declared with val or var that are never read"},
+	{VAL_REASSIGNMENT, "reassignments of variables declared with val, which fail at runtime"},
+	{UNREACHABLE_CODE, "statements following a return or a throw in the same block"},
+	{SHADOWED_NAME, "declarations hiding a variable of an outer scope or a builtin function"},
+	{UNDEFINED_IDENTIFIER, "identifiers and calls referring to nothing declared"},
+	{BUILTIN_ARITY, "calls of builtin functions with a wrong number of arguments"},
+}
+
+// Lookup returns the rule with the given name.
+func Lookup(name string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Diagnostic is a problem found in a program by a rule.
+type Diagnostic struct {
+	Rule     string
+	Position token.Position
+	Message  string
+}
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s (%s) [%s]", d.Message, d.Position, d.Rule)
+}
+
+// Analyze runs the rules with the given names over the program, or all of them when no name is given,
+// and returns the problems found from the first one in the source code.
+// The problems suppressed by the comments of the program are left out.
+func Analyze(program *ast.Program, rules ...string) ([]*Diagnostic, error) {
+	enabled := map[string]bool{}
+	for _, name := range rules {
+		if _, ok := Lookup(name); !ok {
+			return nil, fmt.Errorf("unknown rule: %s", name)
+		}
+		enabled[name] = true
+	}
+	if len(rules) == 0 {
+		for _, rule := range Rules {
+			enabled[rule.Name] = true
+		}
+	}
+
+	c := newChecker()
+	c.checkProgram(program)
+	suppressions := newSuppressions(program.Comments)
+	var diagnostics []*Diagnostic
+	for _, diagnostic := range c.diagnostics {
+		if enabled[diagnostic.Rule] && !suppressions.suppress(diagnostic) {
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i].Position, diagnostics[j].Position
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return diagnostics, nil
+}
+
+// suppressions are the rules disabled by the comments of a program, where an empty rule name stands for all rules.
+type suppressions struct {
+	lines map[int][]string
+	file  []string
+}
+
+func newSuppressions(comments []token.Comment) *suppressions {
+	s := &suppressions{lines: map[int][]string{}}
+	for _, comment := range comments {
+		fields := strings.Fields(strings.ReplaceAll(comment.Text, ",", " "))
+		if len(fields) == 0 {
+			continue
+		}
+		rules := fields[1:]
+		if len(rules) == 0 {
+			rules = []string{""}
+		}
+		switch fields[0] {
+		case IGNORE_DIRECTIVE:
+			line := comment.Position.Line
+			s.lines[line] = append(s.lines[line], rules...)
+			s.lines[line+1] = append(s.lines[line+1], rules...)
+		case FILE_IGNORE_DIRECTIVE:
+			s.file = append(s.file, rules...)
+		}
+	}
+	return s
+}
+
+func (s *suppressions) suppress(diagnostic *Diagnostic) bool {
+	return matches(s.lines[diagnostic.Position.Line], diagnostic.Rule) || matches(s.file, diagnostic.Rule)
+}
+
+func matches(rules []string, name string) bool {
+	for _, rule := range rules {
+		if rule == "" || rule == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package analysis
+
+import (
+	"testing"
+	"yail/ast"
+	"yail/lexer"
+	"yail/parser"
+	"yail/utils"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"val a = 1; var b = 2; b = 3; a", []string{"'b' is declared but never used (line 1, column 16) [unused-variable]"}},
+		{"val f = func(x, y) { 1 }; f(1, 2)", nil},
+		{"try { 1 } catch (e) { 2 }", nil},
+		{"val a = 1; a = 2; a", []string{"'a' can not be reassigned, as it is declared with 'val' (line 1, column 12) [val-reassignment]"}},
+		{"var a = 1; a = 2; a", nil},
+		{"try { 1 } catch (e) { e = 2 }", []string{"'e' can not be reassigned, as it is declared with 'val' (line 1, column 23) [val-reassignment]"}},
+		{"val f = func() { return 1; 2; 3 }; f()", []string{"unreachable code after return (line 1, column 28) [unreachable-code]"}},
+		{"val f = func() { if (true) { throw 1 }; 2 }; f()", nil},
+		{"val x = 1; val f = func(x) { x }; f(x)", []string{"'x' shadows a variable of an outer scope (line 1, column 25) [shadowed-name]"}},
+		{"val f = func(x) { try { x } catch (x) { x } }; f(1)", []string{"'x' shadows a parameter of an outer scope (line 1, column 36) [shadowed-name]"}},
+		{"val len = 1; len", []string{"'len' shadows the builtin function len (line 1, column 5) [shadowed-name]"}},
+		{"val f = func() { g() }; val g = func() { 1 }; f()", nil},
+		{"val x = 1; val f = func() { val y = x; val x = 2; y }; f()", []string{
+			"'x' shadows a variable of an outer scope (line 1, column 44) [shadowed-name]",
+			"'x' is declared but never used (line 1, column 44) [unused-variable]",
+		}},
+		{"missing(1)", []string{"call to undefined function 'missing' (line 1, column 1) [undefined-identifier]"}},
+		{"1 + x", []string{"undefined identifier 'x' (line 1, column 5) [undefined-identifier]"}},
+		{"val f = func() { a = 1 }; var a = 0; f(); a", []string{"assignment to undeclared variable 'a' (line 1, column 18) [undefined-identifier]"}},
+		{"push([1]); len([], [])", []string{
+			"push expects 2 arguments, but is called with 1 (line 1, column 1) [builtin-arity]",
+			"len expects 1 arguments, but is called with 2 (line 1, column 12) [builtin-arity]",
+		}},
+		{"error()", []string{"error expects 1 to 2 arguments, but is called with 0 (line 1, column 1) [builtin-arity]"}},
+		{"val push = func(x) { x }; push(1)", []string{"'push' shadows the builtin function push (line 1, column 5) [shadowed-name]"}},
+		{`import "m.yail" as m; export val a = 1`, []string{"'m' is imported but never used (line 1, column 20) [unused-variable]"}},
+		{`import "m.yail" as m; val f = func(m) { m }; f(m)`, []string{"'m' shadows a module of an outer scope (line 1, column 36) [shadowed-name]"}},
+	}
+
+	for _, tt := range tests {
+		diagnostics, err := Analyze(parse(t, tt.input))
+		utils.ValidateValue(err == nil, true, t)
+		validateDiagnostics(diagnostics, tt.expected, t)
+	}
+}
+
+func TestEnabledRules(t *testing.T) {
+	program := parse(t, "val a = 1; a = 2; missing()")
+	diagnostics, err := Analyze(program, UNDEFINED_IDENTIFIER)
+	utils.ValidateValue(err == nil, true, t)
+	validateDiagnostics(diagnostics, []string{"call to undefined function 'missing' (line 1, column 19) [undefined-identifier]"}, t)
+
+	_, err = Analyze(program, "unknown")
+	utils.ValidateValue(err.Error(), "unknown rule: unknown", t)
+}
+
+func TestSuppressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"val a = 1 // lint:ignore unused-variable", nil},
+		{"// lint:ignore\nval a = 1\nval b = 2", []string{"'b' is declared but never used (line 3, column 5) [unused-variable]"}},
+		{"// lint:ignore shadowed-name\nval a = 1", []string{"'a' is declared but never used (line 2, column 5) [unused-variable]"}},
+		{"// lint:ignore shadowed-name, unused-variable\nval len = 1", nil},
+		{"// lint:file-ignore unused-variable\nval a = 1\nval b = 2\nb = 3", []string{"'b' can not be reassigned, as it is declared with 'val' (line 4, column 1) [val-reassignment]"}},
+		{"// an ordinary comment\nval a = 1", []string{"'a' is declared but never used (line 2, column 5) [unused-variable]"}},
+	}
+
+	for _, tt := range tests {
+		diagnostics, err := Analyze(parse(t, tt.input))
+		utils.ValidateValue(err == nil, true, t)
+		validateDiagnostics(diagnostics, tt.expected, t)
+	}
+}
+
+func validateDiagnostics(diagnostics []*Diagnostic, expected []string, t *testing.T) {
+	utils.ValidateValue(len(diagnostics), len(expected), t)
+	for i := 0; i < len(diagnostics) && i < len(expected); i++ {
+		utils.ValidateValue(diagnostics[i].String(), expected[i], t)
+	}
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	utils.ValidateValue(len(p.Errors()), 0, t)
+	return program
+}
@@ -0,0 +1,256 @@
+package analysis
+
+import (
+	"fmt"
+	"yail/ast"
+	"yail/evaluator"
+	"yail/object"
+	"yail/resolver"
+	"yail/token"
+)
+
+// checker walks a program once, following the scopes of the resolver, and reports the problems of all the rules.
+type checker struct {
+	scope       *resolver.Scope[*variable]
+	diagnostics []*Diagnostic
+}
+
+type variable struct {
+	name string
+	kind token.TokenType
+	// position is where the variable is first declared, which is the zero position until the declaration is met.
+	position token.Position
+	used     bool
+}
+
+const (
+	PARAMETER       = "parameter"
+	CATCH_PARAMETER = "catch parameter"
+)
+
+func newChecker() *checker {
+	return &checker{}
+}
+
+func (c *checker) checkProgram(program *ast.Program) {
+	c.enterScope(resolver.DeclaredNames(program), false)
+	c.checkStatements(program.Statements)
+	c.leaveScope()
+}
+
+// enterScope enters a scope holding the variables with the given names, in the order they are declared
+// in the source code.
+func (c *checker) enterScope(names []string, isCatch bool) {
+	c.scope = resolver.NewScope(c.scope, isCatch)
+	for _, name := range names {
+		c.add(name)
+	}
+}
+
+func (c *checker) leaveScope() {
+	for _, name := range c.scope.Names() {
+		v, _ := c.scope.Get(name)
+		switch {
+		case v.used:
+		case v.kind == token.VAL || v.kind == token.VAR:
+			c.report(UNUSED_VARIABLE, v.position, "'%s' is declared but never used", v.name)
+		case v.kind == token.IMPORT:
+			c.report(UNUSED_VARIABLE, v.position, "'%s' is imported but never used", v.name)
+		}
+	}
+	c.scope = c.scope.Outer
+}
+
+func (c *checker) checkStatements(statements []ast.Statement) {
+	var jump ast.Statement
+	for _, statement := range statements {
+		if jump != nil {
+			c.report(UNREACHABLE_CODE, ast.PositionOf(statement), "unreachable code after %s", jump.TokenLiteral())
+			jump = nil // the following statements are part of the same unreachable code
+		} else if isJump(statement) {
+			jump = statement
+		}
+		c.checkStatement(statement)
+	}
+}
+
+func (c *checker) checkStatement(node ast.Statement) {
+	switch node := node.(type) {
+	case *ast.ExpressionStatement:
+		c.checkExpression(node.Expression)
+	case *ast.VariableBindingStatement:
+		c.checkExpression(node.Value)
+		c.declare(node.Name, node.Token.Type)
+		if node.Exported {
+			if v, ok := c.scope.Get(node.Name.Value); ok {
+				v.used = true // by the importers of the module
+			}
+		}
+	case *ast.ReassignmentStatement:
+		c.checkExpression(node.Value)
+		c.checkReassignment(node)
+	case *ast.ReturnStatement:
+		c.checkExpression(node.ReturnValue)
+	case *ast.ThrowStatement:
+		c.checkExpression(node.Value)
+	case *ast.BlockStatement:
+		c.checkBlock(node)
+	case *ast.ImportStatement:
+		c.declare(node.Alias, token.IMPORT)
+	}
+}
+
+func (c *checker) checkBlock(block *ast.BlockStatement) {
+	if block != nil {
+		c.checkStatements(block.Statements)
+	}
+}
+
+func (c *checker) checkExpression(node ast.Expression) {
+	switch node := node.(type) {
+	case *ast.IdentifierExpression:
+		c.use(node, "undefined identifier '%s'")
+	case *ast.TemplateLiteral:
+		c.checkExpressions(node.Parts)
+	case *ast.PrefixExpression:
+		c.checkExpression(node.RightNode)
+	case *ast.InfixExpression:
+		c.checkExpression(node.LeftNode)
+		c.checkExpression(node.RightNode)
+	case *ast.IfExpression:
+		c.checkExpression(node.Condition)
+		c.checkBlock(node.Consequence)
+		c.checkBlock(node.Alternative)
+	case *ast.TryExpression:
+		c.checkBlock(node.Block)
+		if node.Catch != nil {
+			c.enterScope(append([]string{node.CatchParameter.Value}, resolver.DeclaredNames(node.Catch)...), true)
+			c.declare(node.CatchParameter, CATCH_PARAMETER)
+			c.checkBlock(node.Catch)
+			c.leaveScope()
+		}
+		c.checkBlock(node.Finally)
+	case *ast.FunctionLiteral:
+		c.enterScope(resolver.DeclaredNames(node.Body), false)
+		for _, parameter := range node.Parameters {
+			c.add(parameter.Value)
+			c.declare(parameter, PARAMETER)
+		}
+		c.checkBlock(node.Body)
+		c.leaveScope()
+	case *ast.CallExpression:
+		if identifier, ok := node.Function.(*ast.IdentifierExpression); ok {
+			c.use(identifier, "call to undefined function '%s'")
+			c.checkBuiltinArity(identifier, len(node.Arguments))
+		} else {
+			c.checkExpression(node.Function)
+		}
+		c.checkExpressions(node.Arguments)
+	case *ast.ArrayLiteral:
+		c.checkExpressions(node.Elements)
+	case *ast.HashMapLiteral:
+		for _, pair := range node.Pairs {
+			c.checkExpression(pair.Key)
+			c.checkExpression(pair.Value)
+		}
+	case *ast.CollectionAccessExpression:
+		c.checkExpression(node.Left)
+		c.checkExpression(node.Index)
+	}
+}
+
+func (c *checker) checkExpressions(expressions []ast.Expression) {
+	for _, expression := range expressions {
+		c.checkExpression(expression)
+	}
+}
+
+// declare records the declaration of a variable of the current scope, reporting the variables it hides.
+func (c *checker) declare(name *ast.IdentifierExpression, kind token.TokenType) {
+	v, ok := c.scope.Get(name.Value)
+	if !ok || v.kind != "" {
+		return // a duplicate declaration, which the resolver reports
+	}
+	v.kind, v.position = kind, name.Token.Position
+
+	if hidden, ok := c.scope.Outer.Lookup(name.Value); ok {
+		c.report(SHADOWED_NAME, name.Token.Position, "'%s' shadows a %s of an outer scope", name.Value, describe(hidden))
+	} else if isBuiltin(name.Value) {
+		c.report(SHADOWED_NAME, name.Token.Position, "'%s' shadows the builtin function %s", name.Value, name.Value)
+	}
+}
+
+// use marks the variable of an identifier as read, reporting the identifier when it refers to nothing.
+func (c *checker) use(node *ast.IdentifierExpression, undefinedFormat string) {
+	if v, ok := c.scope.LookupDeclared(node.Value, isDeclared); ok {
+		v.used = true
+		return
+	}
+	if !isBuiltin(node.Value) {
+		c.report(UNDEFINED_IDENTIFIER, node.Token.Position, undefinedFormat, node.Value)
+	}
+}
+
+func (c *checker) checkReassignment(node *ast.ReassignmentStatement) {
+	if v, ok := c.scope.LookupInFunction(node.Name.Value); ok {
+		if v.kind == token.VAL || v.kind == CATCH_PARAMETER {
+			c.report(VAL_REASSIGNMENT, node.Name.Token.Position, "'%s' can not be reassigned, as it is declared with '%s'", node.Name.Value, token.VAL)
+		}
+		return
+	}
+	c.report(UNDEFINED_IDENTIFIER, node.Name.Token.Position, "assignment to undeclared variable '%s'", node.Name.Value)
+}
+
+func (c *checker) checkBuiltinArity(node *ast.IdentifierExpression, argc int) {
+	if _, ok := c.scope.Lookup(node.Value); ok {
+		return
+	}
+	builtin, ok := evaluator.LookupBuiltin(node.Value).(*object.Builtin)
+	if !ok || builtin.Accepts(argc) {
+		return
+	}
+	expected := fmt.Sprintf("%d", builtin.MinArgs)
+	if builtin.MaxArgs == object.VARIADIC {
+		expected = fmt.Sprintf("at least %d", builtin.MinArgs)
+	} else if builtin.MaxArgs != builtin.MinArgs {
+		expected = fmt.Sprintf("%d to %d", builtin.MinArgs, builtin.MaxArgs)
+	}
+	c.report(BUILTIN_ARITY, node.Token.Position, "%s expects %s arguments, but is called with %d", node.Value, expected, argc)
+}
+
+func (c *checker) report(rule string, position token.Position, format string, a ...interface{}) {
+	c.diagnostics = append(c.diagnostics, &Diagnostic{Rule: rule, Position: position, Message: fmt.Sprintf(format, a...)})
+}
+
+// add adds a variable that is not declared yet to the current scope, unless it already has a variable with the name.
+func (c *checker) add(name string) {
+	c.scope.Add(name, &variable{name: name})
+}
+
+// isDeclared reports whether the declaration of the variable was met.
+func isDeclared(v *variable) bool {
+	return v.kind != ""
+}
+
+func describe(v *variable) string {
+	if v.kind == PARAMETER || v.kind == CATCH_PARAMETER {
+		return string(v.kind)
+	}
+	if v.kind == token.IMPORT {
+		return "module"
+	}
+	return "variable"
+}
+
+func isBuiltin(name string) bool {
+	return evaluator.IsBuiltin(name)
+}
+
+// isJump reports whether the statement leaves the block, so that the statements following it never run.
+func isJump(statement ast.Statement) bool {
+	switch statement.(type) {
+	case *ast.ReturnStatement, *ast.ThrowStatement:
+		return true
+	}
+	return false
+}
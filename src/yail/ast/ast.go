@@ -3,6 +3,7 @@ package ast
 import (
 	"bytes"
 	"strings"
+	"yail/token"
 )
 
 type Node interface {
@@ -22,6 +23,11 @@ type Statement interface {
 
 type Program struct {
 	Statements []Statement
+	// Comments are the comments of the source code, in order.
+	Comments []token.Comment
+	// File is the file the program was read from, next to which its imports are searched.
+	// It is empty for the programs that are not read from a file, such as the inputs of the REPL.
+	File string
 }
 
 func (p *Program) TokenLiteral() string {
@@ -35,3 +41,33 @@ func (p *Program) String() string {
 	}
 	return strings.TrimSpace(out.String())
 }
+
+// PositionOf returns where the node is in the source code,
+// or the zero position for nodes that do not keep the position of their token.
+func PositionOf(node Node) token.Position {
+	switch node := node.(type) {
+	case *IdentifierExpression:
+		return node.Token.Position
+	case *InfixExpression:
+		return node.Token.Position
+	case *PrefixExpression:
+		return node.Token.Position
+	case *CallExpression:
+		return node.Token.Position
+	case *CollectionAccessExpression:
+		return node.Token.Position
+	case *VariableBindingStatement:
+		return node.Token.Position
+	case *ReassignmentStatement:
+		return node.Token.Position
+	case *ThrowStatement:
+		return node.Token.Position
+	case *ImportStatement:
+		return node.Token.Position
+	case *ReturnStatement:
+		return node.Token.Position
+	case *ExpressionStatement:
+		return node.Token.Position
+	}
+	return token.Position{}
+}
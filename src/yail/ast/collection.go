@@ -34,18 +34,28 @@ func (al *ArrayLiteral) String() string {
 	return out.String()
 }
 
+// HashMapLiteral holds its pairs in the order of the source, in which they are evaluated.
 type HashMapLiteral struct {
 	Token token.Token
-	Pairs map[Expression]Expression
+	Pairs []*HashMapPair
 }
 
-func NewHashMapLiteral(pairs map[Expression]Expression) *HashMapLiteral {
+type HashMapPair struct {
+	Key   Expression
+	Value Expression
+}
+
+func NewHashMapLiteral(pairs []*HashMapPair) *HashMapLiteral {
 	return &HashMapLiteral{
 		Token: token.LEFT_BRACE_TOKEN,
 		Pairs: pairs,
 	}
 }
 
+func NewHashMapPair(key, value Expression) *HashMapPair {
+	return &HashMapPair{Key: key, Value: value}
+}
+
 func (hl *HashMapLiteral) expressionNode() {}
 func (hl *HashMapLiteral) TokenLiteral() string {
 	return hl.Token.Literal
@@ -53,8 +63,8 @@ func (hl *HashMapLiteral) TokenLiteral() string {
 func (hl *HashMapLiteral) String() string {
 	var out bytes.Buffer
 	var pairs []string
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
+	for _, pair := range hl.Pairs {
+		pairs = append(pairs, pair.Key.String()+":"+pair.Value.String())
 	}
 	out.WriteString("{")
 	out.WriteString(strings.Join(pairs, ", "))
@@ -68,9 +78,9 @@ type CollectionAccessExpression struct {
 	Index Expression
 }
 
-func NewCollectionAccess(left, index Expression) *CollectionAccessExpression {
+func NewCollectionAccess(tok token.Token, left, index Expression) *CollectionAccessExpression {
 	return &CollectionAccessExpression{
-		Token: token.LEFT_BRACKET_TOKEN,
+		Token: tok,
 		Left:  left,
 		Index: index,
 	}
@@ -47,6 +47,47 @@ func (i *IfExpression) String() string {
 	return out.String()
 }
 
+// TryExpression evaluates Block, then Catch when Block fails with an error, and finally Finally.
+// Either Catch or Finally may be nil, but not both.
+type TryExpression struct {
+	Token          token.Token
+	Block          *BlockStatement
+	CatchParameter *IdentifierExpression
+	Catch          *BlockStatement
+	Finally        *BlockStatement
+	// NumSlots is the number of variables of the catch block, including its parameter, as computed by the resolver.
+	NumSlots int
+}
+
+func NewTry(block *BlockStatement, catchParameter *IdentifierExpression, catch, finally *BlockStatement) *TryExpression {
+	return &TryExpression{
+		Token:          token.TRY_TOKEN,
+		Block:          block,
+		CatchParameter: catchParameter,
+		Catch:          catch,
+		Finally:        finally,
+	}
+}
+
+func (t *TryExpression) expressionNode() {}
+func (t *TryExpression) TokenLiteral() string {
+	return t.Token.Literal
+}
+func (t *TryExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("try ")
+	out.WriteString(t.Block.String())
+	if t.Catch != nil {
+		out.WriteString("catch(" + t.CatchParameter.String() + ") ")
+		out.WriteString(t.Catch.String())
+	}
+	if t.Finally != nil {
+		out.WriteString("finally ")
+		out.WriteString(t.Finally.String())
+	}
+	return out.String()
+}
+
 type PrefixExpression struct {
 	Token     token.Token
 	Operator  string
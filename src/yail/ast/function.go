@@ -9,10 +9,18 @@ import (
 type FunctionLiteral struct {
 	Token      token.Token
 	Parameters []*IdentifierExpression
+	// ParameterTypes are the annotated types of the parameters, with nil for the parameters without annotation.
+	// It is nil when no parameter is annotated.
+	ParameterTypes []*TypeAnnotation
+	// ReturnType is the annotated type of the result, which is nil when there is no annotation.
+	ReturnType *TypeAnnotation
 	Body       *BlockStatement
+	// NumSlots is the number of variables of the function, including its parameters, as computed by the resolver.
+	NumSlots int
 }
 
 func NewFunctionLiteral(parameters []*IdentifierExpression, body *BlockStatement) *FunctionLiteral {
+	markTailCalls(body, true)
 	return &FunctionLiteral{
 		Token:      token.FUNCTION_TOKEN,
 		Parameters: parameters,
@@ -20,6 +28,14 @@ func NewFunctionLiteral(parameters []*IdentifierExpression, body *BlockStatement
 	}
 }
 
+// ParameterType returns the annotated type of the i-th parameter, or nil when it has no annotation.
+func (fl *FunctionLiteral) ParameterType(i int) *TypeAnnotation {
+	if i >= len(fl.ParameterTypes) {
+		return nil
+	}
+	return fl.ParameterTypes[i]
+}
+
 func (fl *FunctionLiteral) expressionNode() {}
 func (fl *FunctionLiteral) TokenLiteral() string {
 	return fl.Token.Literal
@@ -27,13 +43,15 @@ func (fl *FunctionLiteral) TokenLiteral() string {
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 	var params []string
-	for _, p := range fl.Parameters {
-		params = append(params, p.String())
+	for i, p := range fl.Parameters {
+		params = append(params, p.String()+annotate(fl.ParameterType(i)))
 	}
 	out.WriteString(fl.TokenLiteral())
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
-	out.WriteString(") ")
+	out.WriteString(")")
+	out.WriteString(annotate(fl.ReturnType))
+	out.WriteString(" ")
 	out.WriteString("{ ")
 	out.WriteString(fl.Body.String())
 	out.WriteString(" }")
@@ -42,14 +60,17 @@ func (fl *FunctionLiteral) String() string {
 
 type CallExpression struct {
 	Token     token.Token
-	Function  *IdentifierExpression
+	Function  Expression
 	Arguments []Expression
+	// IsTailCall is true when the result of the call is returned by the enclosing function as it is,
+	// so the call can reuse the stack frame of the function.
+	IsTailCall bool
 }
 
-func NewFunctionCall(functionIdentifier *IdentifierExpression, arguments []Expression) *CallExpression {
+func NewFunctionCall(tok token.Token, function Expression, arguments []Expression) *CallExpression {
 	return &CallExpression{
-		Token:     token.LEFT_PARENTHESIS_TOKEN,
-		Function:  functionIdentifier,
+		Token:     tok,
+		Function:  function,
 		Arguments: arguments,
 	}
 }
@@ -70,3 +91,30 @@ func (ce *CallExpression) String() string {
 	out.WriteString(")")
 	return out.String()
 }
+
+// markTailCalls marks the calls whose results are returned from a function body:
+// the values of return statements, and the last expression of the body, looking into if expressions.
+// Calls inside try expressions are never marked, as their errors and finally blocks must be handled after the call.
+func markTailCalls(block *BlockStatement, isTail bool) {
+	if block == nil {
+		return
+	}
+	for i, statement := range block.Statements {
+		switch statement := statement.(type) {
+		case *ReturnStatement:
+			markTailCallExpression(statement.ReturnValue, true)
+		case *ExpressionStatement:
+			markTailCallExpression(statement.Expression, isTail && i == len(block.Statements)-1)
+		}
+	}
+}
+
+func markTailCallExpression(expression Expression, isTail bool) {
+	switch expression := expression.(type) {
+	case *CallExpression:
+		expression.IsTailCall = isTail
+	case *IfExpression:
+		markTailCalls(expression.Consequence, isTail)
+		markTailCalls(expression.Alternative, isTail)
+	}
+}
@@ -7,6 +7,16 @@ import (
 type IdentifierExpression struct {
 	Token token.Token
 	Value string
+	// Slot is where the variable is stored, as computed by the resolver.
+	// It is nil for global variables, which are looked up by name.
+	Slot *Slot
+}
+
+// Slot locates a variable: it is the Index-th variable of the function scope Depth levels above the identifier,
+// where the program itself is the outermost scope.
+type Slot struct {
+	Depth int
+	Index int
 }
 
 func NewIdentifierFrom(value string) *IdentifierExpression {
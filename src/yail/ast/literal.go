@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"bytes"
 	"yail/token"
 )
 
@@ -75,3 +76,30 @@ func (n *NullExpression) TokenLiteral() string {
 func (n *NullExpression) String() string {
 	return n.Token.Literal
 }
+
+// TemplateLiteral is a string embedding expressions, such as "Hello ${name}".
+// Its parts are string literals for the text between the embedded expressions.
+type TemplateLiteral struct {
+	Token token.Token
+	Parts []Expression
+}
+
+func NewTemplateLiteral(tok token.Token, parts []Expression) *TemplateLiteral {
+	return &TemplateLiteral{Token: tok, Parts: parts}
+}
+
+func (tl *TemplateLiteral) expressionNode() {}
+func (tl *TemplateLiteral) TokenLiteral() string {
+	return tl.Token.Literal
+}
+func (tl *TemplateLiteral) String() string {
+	var out bytes.Buffer
+	for _, part := range tl.Parts {
+		if text, ok := part.(*StringLiteralExpression); ok {
+			out.WriteString(text.Value)
+			continue
+		}
+		out.WriteString("${" + part.String() + "}")
+	}
+	return out.String()
+}
@@ -2,13 +2,19 @@ package ast
 
 import (
 	"bytes"
+	"strconv"
 	"yail/token"
 )
 
 type VariableBindingStatement struct {
 	Token token.Token
 	Name  *IdentifierExpression
+	// Type is the annotated type of the variable, which is nil when there is no annotation.
+	Type  *TypeAnnotation
 	Value Expression
+	// Exported is true when the declaration starts with `export`, so that the modules importing the program
+	// can read the variable.
+	Exported bool
 }
 
 func NewVariableBinding(keyword token.Token, name *IdentifierExpression, value Expression) *VariableBindingStatement {
@@ -28,12 +34,16 @@ func (statement *VariableBindingStatement) TokenLiteral() string {
 }
 func (statement *VariableBindingStatement) String() string {
 	var out bytes.Buffer
+	if statement.Exported {
+		out.WriteString(token.EXPORT + " ")
+	}
 	out.WriteString(statement.TokenLiteral() + " ") // var
 	out.WriteString(statement.Name.String())        // a
+	out.WriteString(annotate(statement.Type))       // : Int
 	out.WriteString(" = ")
 	out.WriteString(statement.Value.String()) // 10
 	out.WriteString(";")
-	return out.String() // var a = 10;
+	return out.String() // var a: Int = 10;
 }
 
 type ReassignmentStatement struct {
@@ -71,9 +81,9 @@ type ReturnStatement struct {
 	ReturnValue Expression
 }
 
-func NewReturn(value Expression) *ReturnStatement {
+func NewReturn(tok token.Token, value Expression) *ReturnStatement {
 	return &ReturnStatement{
-		Token:       token.RETURN_TOKEN,
+		Token:       tok,
 		ReturnValue: value,
 	}
 }
@@ -129,3 +139,47 @@ func (statement *ExpressionStatement) TokenLiteral() string {
 func (statement *ExpressionStatement) String() string {
 	return statement.Expression.String() + ";"
 }
+
+type ThrowStatement struct {
+	Token token.Token
+	Value Expression
+}
+
+func NewThrow(tok token.Token, value Expression) *ThrowStatement {
+	return &ThrowStatement{
+		Token: tok,
+		Value: value,
+	}
+}
+
+func (statement *ThrowStatement) statementNode() {}
+func (statement *ThrowStatement) TokenLiteral() string {
+	return statement.Token.Literal
+}
+func (statement *ThrowStatement) String() string {
+	return "throw " + statement.Value.String() + ";"
+}
+
+// ImportStatement binds the module read from a file to a variable, as in `import "lib/math.yail" as m;`.
+type ImportStatement struct {
+	Token token.Token
+	// Path is the path of the file, relative to the importing file or to a directory of the search path.
+	Path  string
+	Alias *IdentifierExpression
+}
+
+func NewImport(tok token.Token, path string, alias *IdentifierExpression) *ImportStatement {
+	return &ImportStatement{
+		Token: tok,
+		Path:  path,
+		Alias: alias,
+	}
+}
+
+func (statement *ImportStatement) statementNode() {}
+func (statement *ImportStatement) TokenLiteral() string {
+	return statement.Token.Literal
+}
+func (statement *ImportStatement) String() string {
+	return token.IMPORT + " " + strconv.Quote(statement.Path) + " " + token.AS + " " + statement.Alias.String() + ";"
+}
@@ -0,0 +1,37 @@
+package ast
+
+import "yail/token"
+
+// TypeAnnotation is the optional type of a variable, a parameter or the result of a function, such as `Int` or `Int?`.
+type TypeAnnotation struct {
+	Token token.Token // the name of the type
+	Name  string
+	// Nullable is true when the type is followed by `?`, so that null is allowed as well.
+	Nullable bool
+}
+
+func NewTypeAnnotation(tok token.Token, nullable bool) *TypeAnnotation {
+	return &TypeAnnotation{
+		Token:    tok,
+		Name:     tok.Literal,
+		Nullable: nullable,
+	}
+}
+
+func (ta *TypeAnnotation) TokenLiteral() string {
+	return ta.Token.Literal
+}
+func (ta *TypeAnnotation) String() string {
+	if ta.Nullable {
+		return ta.Name + token.QUESTION
+	}
+	return ta.Name
+}
+
+// annotate returns the text of an optional type annotation, as written after a name.
+func annotate(annotation *TypeAnnotation) string {
+	if annotation == nil {
+		return ""
+	}
+	return ": " + annotation.String()
+}
@@ -0,0 +1,192 @@
+package compiler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type Instructions []byte
+
+type Opcode byte
+
+const (
+	// OpConstant pushes a constant. Strings are copied, as every evaluation of a literal creates a new string.
+	OpConstant Opcode = iota
+	OpNull
+	OpTrue
+	OpFalse
+	OpPop
+
+	// OpInfix and OpPrefix apply the operator at the given index of Operators.
+	OpInfix
+	OpPrefix
+	OpIndex
+	OpArray
+	OpHash
+	OpTemplate
+
+	// OpJump, OpBranch and OpPushHandler take addresses, which are 4 bytes wide so that large functions can be compiled.
+	OpJump
+	// OpBranch pops the condition of an if expression. It continues with true, jumps to its first operand with false,
+	// and pushes null and jumps to its second operand with any other value.
+	OpBranch
+
+	// OpGetVariable, OpDefineVariable and OpAssignVariable refer to the variable at the given index
+	// of the Variables of the function.
+	OpGetVariable
+	OpDefineVariable
+	OpAssignVariable
+	// OpBindCatch starts a catch block with a scope of the given number of slots, and pops an error
+	// bound to the parameter of the block, kept in the first slot.
+	OpBindCatch
+	// OpUnbindCatch ends the innermost catch block, going back to the scope enclosing it.
+	OpUnbindCatch
+
+	OpClosure
+	OpCall
+	// OpTailCall calls a function in tail position, reusing the frame of the caller for user-defined functions.
+	OpTailCall
+	OpReturnValue
+	// OpReturnNothing ends a program whose last statement does not produce a value.
+	OpReturnNothing
+
+	OpThrow
+	// OpRethrow pops an error caught by a handler and raises it again.
+	OpRethrow
+	// OpPushHandler makes errors raised before the matching OpPopHandler jump to the given address,
+	// where the error is pushed on the stack.
+	OpPushHandler
+	OpPopHandler
+
+	// OpImport pushes the module read from the path held by the given constant, which the evaluator runs
+	// the first time it is imported.
+	OpImport
+)
+
+type Definition struct {
+	Name          string
+	OperandWidths []int
+}
+
+var definitions = map[Opcode]*Definition{
+	OpConstant:       {"OpConstant", []int{2}},
+	OpNull:           {"OpNull", []int{}},
+	OpTrue:           {"OpTrue", []int{}},
+	OpFalse:          {"OpFalse", []int{}},
+	OpPop:            {"OpPop", []int{}},
+	OpInfix:          {"OpInfix", []int{1}},
+	OpPrefix:         {"OpPrefix", []int{1}},
+	OpIndex:          {"OpIndex", []int{}},
+	OpArray:          {"OpArray", []int{2}},
+	OpHash:           {"OpHash", []int{2}},
+	OpTemplate:       {"OpTemplate", []int{2}},
+	OpJump:           {"OpJump", []int{4}},
+	OpBranch:         {"OpBranch", []int{4, 4}},
+	OpGetVariable:    {"OpGetVariable", []int{2}},
+	OpDefineVariable: {"OpDefineVariable", []int{2}},
+	OpAssignVariable: {"OpAssignVariable", []int{2}},
+	OpBindCatch:      {"OpBindCatch", []int{2}},
+	OpUnbindCatch:    {"OpUnbindCatch", []int{}},
+	OpClosure:        {"OpClosure", []int{2}},
+	OpCall:           {"OpCall", []int{1}},
+	OpTailCall:       {"OpTailCall", []int{1}},
+	OpReturnValue:    {"OpReturnValue", []int{}},
+	OpReturnNothing:  {"OpReturnNothing", []int{}},
+	OpThrow:          {"OpThrow", []int{}},
+	OpRethrow:        {"OpRethrow", []int{}},
+	OpPushHandler:    {"OpPushHandler", []int{4}},
+	OpPopHandler:     {"OpPopHandler", []int{}},
+	OpImport:         {"OpImport", []int{2}},
+}
+
+func Lookup(op byte) (*Definition, error) {
+	def, ok := definitions[Opcode(op)]
+	if !ok {
+		return nil, fmt.Errorf("opcode %d undefined", op)
+	}
+	return def, nil
+}
+
+// Make encodes an instruction, writing operands in big-endian order.
+func Make(op Opcode, operands ...int) []byte {
+	def, ok := definitions[op]
+	if !ok {
+		return []byte{}
+	}
+	length := 1
+	for _, width := range def.OperandWidths {
+		length += width
+	}
+	instruction := make([]byte, length)
+	instruction[0] = byte(op)
+	offset := 1
+	for i, operand := range operands {
+		width := def.OperandWidths[i]
+		switch width {
+		case 4:
+			binary.BigEndian.PutUint32(instruction[offset:], uint32(operand))
+		case 2:
+			binary.BigEndian.PutUint16(instruction[offset:], uint16(operand))
+		case 1:
+			instruction[offset] = byte(operand)
+		}
+		offset += width
+	}
+	return instruction
+}
+
+// ReadOperands decodes the operands of an instruction, and returns them with the number of bytes read.
+func ReadOperands(def *Definition, ins Instructions) ([]int, int) {
+	operands := make([]int, len(def.OperandWidths))
+	offset := 0
+	for i, width := range def.OperandWidths {
+		switch width {
+		case 4:
+			operands[i] = int(ReadUint32(ins[offset:]))
+		case 2:
+			operands[i] = int(ReadUint16(ins[offset:]))
+		case 1:
+			operands[i] = int(ins[offset])
+		}
+		offset += width
+	}
+	return operands, offset
+}
+
+func ReadUint16(ins Instructions) uint16 {
+	return binary.BigEndian.Uint16(ins)
+}
+
+func ReadUint32(ins Instructions) uint32 {
+	return binary.BigEndian.Uint32(ins)
+}
+
+func (ins Instructions) String() string {
+	var out bytes.Buffer
+	i := 0
+	for i < len(ins) {
+		def, err := Lookup(ins[i])
+		if err != nil {
+			fmt.Fprintf(&out, "ERROR: %s\n", err)
+			i += 1
+			continue
+		}
+		operands, read := ReadOperands(def, ins[i+1:])
+		fmt.Fprintf(&out, "%04d %s\n", i, formatInstruction(def, operands))
+		i += 1 + read
+	}
+	return out.String()
+}
+
+func formatInstruction(def *Definition, operands []int) string {
+	switch len(operands) {
+	case 0:
+		return def.Name
+	case 1:
+		return fmt.Sprintf("%s %d", def.Name, operands[0])
+	case 2:
+		return fmt.Sprintf("%s %d %d", def.Name, operands[0], operands[1])
+	}
+	return fmt.Sprintf("ERROR: unhandled operand count for %s", def.Name)
+}
@@ -0,0 +1,505 @@
+package compiler
+
+import (
+	"fmt"
+	"math"
+	"yail/ast"
+	"yail/object"
+	"yail/token"
+)
+
+// PLACEHOLDER_ADDRESS is the operand of jumps whose target is not compiled yet.
+const PLACEHOLDER_ADDRESS = math.MaxUint32
+
+// Operators are the operators of infix and prefix expressions, referred to by index in the instructions.
+var Operators = []token.Token{
+	token.New(token.PLUS),
+	token.New(token.MINUS),
+	token.New(token.MULTIPLY),
+	token.New(token.DIVIDE),
+	token.New(token.MODULO),
+	token.New(token.POWER),
+	token.New(token.LESS_THAN),
+	token.New(token.GREATER_THAN),
+	token.New(token.LESS_OR_EQUAL),
+	token.New(token.GREATER_OR_EQUAL),
+	token.New(token.EQUAL),
+	token.New(token.NOT_EQUAL),
+	token.New(token.BIT_AND),
+	token.New(token.BIT_OR),
+	token.New(token.BIT_XOR),
+	token.New(token.BIT_NOT),
+	token.New(token.SHIFT_LEFT),
+	token.New(token.SHIFT_RIGHT),
+	token.New(token.NOT),
+}
+
+// Bytecode is a compiled program, ready to be run by the virtual machine.
+type Bytecode struct {
+	Main      *CompiledFunction
+	Constants []object.Object
+	// File is the file the program was read from, next to which its imports are searched.
+	File string
+}
+
+// Compiler lowers programs to bytecode. A compiler can compile several programs sharing the same variables,
+// such as the inputs of the REPL, as the constants of the previous programs are kept.
+type Compiler struct {
+	constants []object.Object
+	scope     *scope
+}
+
+// scope is the state of the function being compiled.
+type scope struct {
+	function *CompiledFunction
+	// variables are the indexes of the variables of the function, so that each variable is added once.
+	variables map[Variable]int
+	// tries are the try expressions being compiled, from the outermost.
+	tries []*tryBlock
+	// positions are the positions of the nodes being compiled, from the outermost.
+	positions []token.Position
+	outer     *scope
+}
+
+// tryBlock keeps what a return statement inside a try expression must do before leaving the function:
+// removing the error handler of the expression, ending its catch block and running its finally block.
+type tryBlock struct {
+	// inCatch is true while the catch block is compiled.
+	inCatch bool
+	finally *ast.BlockStatement
+}
+
+func New() *Compiler {
+	return &Compiler{}
+}
+
+// Compile lowers a program to bytecode. The program returns the value of its last statement,
+// or nothing when the last statement does not produce a value.
+// The program must have been resolved, as the variables are compiled to the slots computed by the resolver.
+func (c *Compiler) Compile(program *ast.Program) (*Bytecode, error) {
+	main := &CompiledFunction{Positions: map[int]token.Position{}}
+	c.scope = &scope{function: main, variables: map[Variable]int{}}
+	defer func() { c.scope = nil }()
+
+	for i, statement := range program.Statements {
+		if expression, ok := statement.(*ast.ExpressionStatement); ok && i == len(program.Statements)-1 {
+			if err := c.compileValue(expression); err != nil {
+				return nil, err
+			}
+			c.emit(OpReturnValue)
+			return &Bytecode{Main: main, Constants: c.constants, File: program.File}, nil
+		}
+		if err := c.compileStatement(statement); err != nil {
+			return nil, err
+		}
+	}
+	c.emit(OpReturnNothing)
+	return &Bytecode{Main: main, Constants: c.constants, File: program.File}, nil
+}
+
+func (c *Compiler) compileStatement(node ast.Statement) error {
+	if position := ast.PositionOf(node); position != (token.Position{}) {
+		c.pushPosition(position)
+		defer c.popPosition()
+	}
+	switch node := node.(type) {
+	case *ast.ExpressionStatement:
+		if err := c.compileExpression(node.Expression); err != nil {
+			return err
+		}
+		c.emit(OpPop)
+	case *ast.VariableBindingStatement:
+		if err := c.compileExpression(node.Value); err != nil {
+			return err
+		}
+		return c.emitVariable(OpDefineVariable, node.Name, node.Token.Type == token.VAR)
+	case *ast.ReassignmentStatement:
+		if err := c.compileExpression(node.Value); err != nil {
+			return err
+		}
+		return c.emitVariable(OpAssignVariable, node.Name, true)
+	case *ast.ReturnStatement:
+		if err := c.compileExpression(node.ReturnValue); err != nil {
+			return err
+		}
+		if err := c.compilePendingFinallyBlocks(); err != nil {
+			return err
+		}
+		c.emit(OpReturnValue)
+	case *ast.ThrowStatement:
+		if err := c.compileExpression(node.Value); err != nil {
+			return err
+		}
+		c.emit(OpThrow)
+	case *ast.BlockStatement:
+		if err := c.compileBlock(node); err != nil {
+			return err
+		}
+		c.emit(OpPop)
+	case *ast.ImportStatement:
+		index, err := c.addConstant(object.NewString(node.Path))
+		if err != nil {
+			return err
+		}
+		c.emit(OpImport, index)
+		return c.emitVariable(OpDefineVariable, node.Alias, false)
+	default:
+		return fmt.Errorf("unsupported statement: %T", node)
+	}
+	return nil
+}
+
+// compileBlock compiles a block leaving the value of its last statement on the stack,
+// or null when the block is empty or ends with a statement that is not an expression.
+func (c *Compiler) compileBlock(block *ast.BlockStatement) error {
+	if block == nil || len(block.Statements) == 0 {
+		c.emit(OpNull)
+		return nil
+	}
+	for i, statement := range block.Statements {
+		if i < len(block.Statements)-1 {
+			if err := c.compileStatement(statement); err != nil {
+				return err
+			}
+			continue
+		}
+		if expression, ok := statement.(*ast.ExpressionStatement); ok {
+			return c.compileValue(expression)
+		}
+		if err := c.compileStatement(statement); err != nil {
+			return err
+		}
+		c.emit(OpNull)
+	}
+	return nil
+}
+
+// compileValue compiles an expression statement whose value is left on the stack, at the position of the statement
+// for the errors raised by expressions without a position of their own.
+func (c *Compiler) compileValue(statement *ast.ExpressionStatement) error {
+	if position := ast.PositionOf(statement); position != (token.Position{}) {
+		c.pushPosition(position)
+		defer c.popPosition()
+	}
+	return c.compileExpression(statement.Expression)
+}
+
+func (c *Compiler) compileExpression(node ast.Expression) error {
+	if position := ast.PositionOf(node); position != (token.Position{}) {
+		c.pushPosition(position)
+		defer c.popPosition()
+	}
+	switch node := node.(type) {
+	case *ast.IdentifierExpression:
+		return c.emitVariable(OpGetVariable, node, false)
+	case *ast.IntegerLiteralExpression:
+		return c.emitConstant(object.NewInteger(node.Value))
+	case *ast.StringLiteralExpression:
+		return c.emitConstant(object.NewString(node.Value))
+	case *ast.TemplateLiteral:
+		if err := c.compileExpressions(node.Parts); err != nil {
+			return err
+		}
+		c.emit(OpTemplate, len(node.Parts))
+	case *ast.BooleanExpression:
+		if node.Value {
+			c.emit(OpTrue)
+		} else {
+			c.emit(OpFalse)
+		}
+	case *ast.NullExpression:
+		c.emit(OpNull)
+	case *ast.PrefixExpression:
+		if err := c.compileExpression(node.RightNode); err != nil {
+			return err
+		}
+		return c.emitOperator(OpPrefix, node.Token)
+	case *ast.InfixExpression:
+		if err := c.compileExpression(node.LeftNode); err != nil {
+			return err
+		}
+		if err := c.compileExpression(node.RightNode); err != nil {
+			return err
+		}
+		return c.emitOperator(OpInfix, node.Token)
+	case *ast.IfExpression:
+		return c.compileIf(node)
+	case *ast.TryExpression:
+		return c.compileTry(node)
+	case *ast.FunctionLiteral:
+		function, err := c.compileFunction(node)
+		if err != nil {
+			return err
+		}
+		index, err := c.addConstant(function)
+		if err != nil {
+			return err
+		}
+		c.emit(OpClosure, index)
+	case *ast.CallExpression:
+		return c.compileCall(node)
+	case *ast.ArrayLiteral:
+		if err := c.compileExpressions(node.Elements); err != nil {
+			return err
+		}
+		c.emit(OpArray, len(node.Elements))
+	case *ast.HashMapLiteral:
+		if len(node.Pairs) > math.MaxUint16 {
+			return fmt.Errorf("too many elements: %d", len(node.Pairs))
+		}
+		for _, pair := range node.Pairs {
+			if err := c.compileExpression(pair.Key); err != nil {
+				return err
+			}
+			if err := c.compileExpression(pair.Value); err != nil {
+				return err
+			}
+		}
+		c.emit(OpHash, len(node.Pairs))
+	case *ast.CollectionAccessExpression:
+		if err := c.compileExpression(node.Left); err != nil {
+			return err
+		}
+		if err := c.compileExpression(node.Index); err != nil {
+			return err
+		}
+		c.emit(OpIndex)
+	default:
+		return fmt.Errorf("unsupported expression: %T", node)
+	}
+	return nil
+}
+
+func (c *Compiler) compileExpressions(expressions []ast.Expression) error {
+	if len(expressions) > math.MaxUint16 {
+		return fmt.Errorf("too many elements: %d", len(expressions))
+	}
+	for _, expression := range expressions {
+		if err := c.compileExpression(expression); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Compiler) compileIf(node *ast.IfExpression) error {
+	if err := c.compileExpression(node.Condition); err != nil {
+		return err
+	}
+	branch := c.emit(OpBranch, PLACEHOLDER_ADDRESS, PLACEHOLDER_ADDRESS)
+	if err := c.compileBlock(node.Consequence); err != nil {
+		return err
+	}
+	jump := c.emit(OpJump, PLACEHOLDER_ADDRESS)
+	alternative := c.currentAddress()
+	if node.Alternative != nil {
+		if err := c.compileBlock(node.Alternative); err != nil {
+			return err
+		}
+	} else {
+		c.emit(OpNull)
+	}
+	end := c.currentAddress()
+	c.changeOperands(branch, alternative, end)
+	c.changeOperands(jump, end)
+	return nil
+}
+
+// compileTry lays out a try expression as follows, where the error raised in the try or catch block is
+// pushed on the stack before jumping to the handler:
+//
+//	try block, then jump to the end
+//	handler of the try block: catch block, then jump to the end
+//	handler of the catch block: end of the catch block and finally block, then raise the error again
+//	end: finally block
+func (c *Compiler) compileTry(node *ast.TryExpression) error {
+	try := &tryBlock{finally: node.Finally}
+	c.scope.tries = append(c.scope.tries, try)
+	handler := c.emit(OpPushHandler, PLACEHOLDER_ADDRESS)
+	if err := c.compileBlock(node.Block); err != nil {
+		return err
+	}
+	c.emit(OpPopHandler)
+	jumps := []int{c.emit(OpJump, PLACEHOLDER_ADDRESS)}
+	c.changeOperands(handler, c.currentAddress())
+
+	if node.Catch != nil {
+		if node.NumSlots > math.MaxUint16 {
+			return fmt.Errorf("too many variables: %d", node.NumSlots)
+		}
+		try.inCatch = true
+		c.emit(OpBindCatch, node.NumSlots)
+		handler = c.emit(OpPushHandler, PLACEHOLDER_ADDRESS)
+		if err := c.compileBlock(node.Catch); err != nil {
+			return err
+		}
+		c.emit(OpPopHandler)
+		c.emit(OpUnbindCatch)
+		jumps = append(jumps, c.emit(OpJump, PLACEHOLDER_ADDRESS))
+		c.changeOperands(handler, c.currentAddress())
+		c.emit(OpUnbindCatch)
+	}
+	c.scope.tries = c.scope.tries[:len(c.scope.tries)-1]
+
+	if node.Finally != nil {
+		if err := c.compileBlock(node.Finally); err != nil {
+			return err
+		}
+		c.emit(OpPop)
+	}
+	c.emit(OpRethrow)
+	for _, jump := range jumps {
+		c.changeOperands(jump, c.currentAddress())
+	}
+	if node.Finally != nil {
+		if err := c.compileBlock(node.Finally); err != nil {
+			return err
+		}
+		c.emit(OpPop)
+	}
+	return nil
+}
+
+// compilePendingFinallyBlocks leaves the try expressions around a return statement from the innermost one,
+// ending their catch blocks and running their finally blocks.
+func (c *Compiler) compilePendingFinallyBlocks() error {
+	tries := c.scope.tries
+	defer func() {
+		c.scope.tries = tries
+	}()
+	for i := len(tries) - 1; i >= 0; i-- {
+		c.emit(OpPopHandler)
+		if tries[i].inCatch {
+			c.emit(OpUnbindCatch)
+		}
+		c.scope.tries = tries[:i]
+		if tries[i].finally == nil {
+			continue
+		}
+		if err := c.compileBlock(tries[i].finally); err != nil {
+			return err
+		}
+		c.emit(OpPop)
+	}
+	return nil
+}
+
+func (c *Compiler) compileCall(node *ast.CallExpression) error {
+	if len(node.Arguments) > math.MaxUint8 {
+		return fmt.Errorf("too many arguments: %d", len(node.Arguments))
+	}
+	if err := c.compileExpression(node.Function); err != nil {
+		return err
+	}
+	if err := c.compileExpressions(node.Arguments); err != nil {
+		return err
+	}
+	if node.IsTailCall {
+		c.emit(OpTailCall, len(node.Arguments))
+	} else {
+		c.emit(OpCall, len(node.Arguments))
+	}
+	return nil
+}
+
+func (c *Compiler) compileFunction(node *ast.FunctionLiteral) (*CompiledFunction, error) {
+	function := &CompiledFunction{Literal: node, NumSlots: node.NumSlots, Positions: map[int]token.Position{}}
+	for _, parameter := range node.Parameters {
+		if parameter.Slot == nil {
+			function.Parameters = append(function.Parameters, -1)
+			continue
+		}
+		function.Parameters = append(function.Parameters, parameter.Slot.Index)
+	}
+
+	functionScope := &scope{function: function, variables: map[Variable]int{}, outer: c.scope}
+	c.scope = functionScope
+	defer func() { c.scope = functionScope.outer }()
+	if err := c.compileBlock(node.Body); err != nil {
+		return nil, err
+	}
+	c.emit(OpReturnValue)
+	return function, nil
+}
+
+// addVariable adds the variable of an identifier to the current function, where identifiers without a slot
+// refer to global variables. The identifiers referring to the same variable share the same index.
+func (c *Compiler) addVariable(node *ast.IdentifierExpression, mutable bool) (int, error) {
+	variable := Variable{Name: node.Value, Mutable: mutable, Global: node.Slot == nil}
+	if node.Slot != nil {
+		variable.Depth, variable.Index = node.Slot.Depth, node.Slot.Index
+	}
+	function := c.scope.function
+	if index, ok := c.scope.variables[variable]; ok {
+		return index, nil
+	}
+	if len(function.Variables) > math.MaxUint16 {
+		return 0, fmt.Errorf("too many variables: %d", len(function.Variables))
+	}
+	function.Variables = append(function.Variables, &variable)
+	c.scope.variables[variable] = len(function.Variables) - 1
+	return len(function.Variables) - 1, nil
+}
+
+func (c *Compiler) emitVariable(op Opcode, node *ast.IdentifierExpression, mutable bool) error {
+	index, err := c.addVariable(node, mutable)
+	if err != nil {
+		return err
+	}
+	c.emit(op, index)
+	return nil
+}
+
+func (c *Compiler) addConstant(constant object.Object) (int, error) {
+	if len(c.constants) > math.MaxUint16 {
+		return 0, fmt.Errorf("too many constants: %d", len(c.constants))
+	}
+	c.constants = append(c.constants, constant)
+	return len(c.constants) - 1, nil
+}
+
+func (c *Compiler) emitConstant(constant object.Object) error {
+	index, err := c.addConstant(constant)
+	if err != nil {
+		return err
+	}
+	c.emit(OpConstant, index)
+	return nil
+}
+
+func (c *Compiler) emitOperator(op Opcode, operator token.Token) error {
+	for i, candidate := range Operators {
+		if candidate.Literal == operator.Literal {
+			c.emit(op, i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported operator: %s", operator.Literal)
+}
+
+func (c *Compiler) emit(op Opcode, operands ...int) int {
+	function := c.scope.function
+	address := len(function.Instructions)
+	function.Instructions = append(function.Instructions, Make(op, operands...)...)
+	if len(c.scope.positions) > 0 {
+		function.Positions[address] = c.scope.positions[len(c.scope.positions)-1]
+	}
+	return address
+}
+
+func (c *Compiler) changeOperands(address int, operands ...int) {
+	instructions := c.scope.function.Instructions
+	copy(instructions[address:], Make(Opcode(instructions[address]), operands...))
+}
+
+func (c *Compiler) currentAddress() int {
+	return len(c.scope.function.Instructions)
+}
+
+func (c *Compiler) pushPosition(position token.Position) {
+	c.scope.positions = append(c.scope.positions, position)
+}
+
+func (c *Compiler) popPosition() {
+	c.scope.positions = c.scope.positions[:len(c.scope.positions)-1]
+}
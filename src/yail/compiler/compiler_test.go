@@ -0,0 +1,192 @@
+package compiler
+
+import (
+	"testing"
+	"yail/ast"
+	"yail/evaluator"
+	"yail/lexer"
+	"yail/object"
+	"yail/parser"
+	"yail/resolver"
+	"yail/utils"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		op       Opcode
+		operands []int
+		expected []byte
+	}{
+		{OpConstant, []int{65534}, []byte{byte(OpConstant), 255, 254}},
+		{OpBranch, []int{1, 258}, []byte{byte(OpBranch), 0, 0, 0, 1, 0, 0, 1, 2}},
+		{OpJump, []int{70000}, []byte{byte(OpJump), 0, 1, 17, 112}},
+		{OpCall, []int{3}, []byte{byte(OpCall), 3}},
+		{OpPop, []int{}, []byte{byte(OpPop)}},
+	}
+
+	for _, tt := range tests {
+		instruction := Make(tt.op, tt.operands...)
+		utils.ValidateValue(string(instruction), string(tt.expected), t)
+
+		def, err := Lookup(byte(tt.op))
+		utils.ValidateValue(err == nil, true, t)
+		operands, read := ReadOperands(def, instruction[1:])
+		utils.ValidateValue(read, len(tt.expected)-1, t)
+		for i, operand := range operands {
+			utils.ValidateValue(operand, tt.operands[i], t)
+		}
+	}
+}
+
+func TestInstructionsString(t *testing.T) {
+	var instructions Instructions
+	instructions = append(instructions, Make(OpConstant, 1)...)
+	instructions = append(instructions, Make(OpInfix, 0)...)
+	instructions = append(instructions, Make(OpBranch, 9, 12)...)
+	instructions = append(instructions, Make(OpReturnValue)...)
+
+	expected := `0000 OpConstant 1
+0003 OpInfix 0
+0005 OpBranch 9 12
+0014 OpReturnValue
+`
+	utils.ValidateValue(instructions.String(), expected, t)
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		input        string
+		expected     []Instructions
+		numConstants int
+	}{
+		{"1 + 2", []Instructions{Make(OpConstant, 0), Make(OpConstant, 1), Make(OpInfix, 0), Make(OpReturnValue)}, 2},
+		{"val x = -1", []Instructions{Make(OpConstant, 0), Make(OpPrefix, 1), Make(OpDefineVariable, 0), Make(OpReturnNothing)}, 1},
+		{"if (true) { 1 }", []Instructions{
+			Make(OpTrue),
+			Make(OpBranch, 18, 19),
+			Make(OpConstant, 0),
+			Make(OpJump, 19),
+			Make(OpNull),
+			Make(OpReturnValue),
+		}, 1},
+		{`"a${len}"`, []Instructions{Make(OpConstant, 0), Make(OpGetVariable, 0), Make(OpTemplate, 2), Make(OpReturnValue)}, 1},
+		{"func(x) { x }(1)", []Instructions{Make(OpClosure, 0), Make(OpConstant, 1), Make(OpCall, 1), Make(OpReturnValue)}, 2},
+		{"try { 1 } finally { 2 }", []Instructions{
+			Make(OpPushHandler, 14),
+			Make(OpConstant, 0),
+			Make(OpPopHandler),
+			Make(OpJump, 19),
+			Make(OpConstant, 1),
+			Make(OpPop),
+			Make(OpRethrow),
+			Make(OpConstant, 2),
+			Make(OpPop),
+			Make(OpReturnValue),
+		}, 3},
+	}
+
+	for _, tt := range tests {
+		bytecode := compile(t, tt.input)
+		var expected Instructions
+		for _, instruction := range tt.expected {
+			expected = append(expected, instruction...)
+		}
+		utils.ValidateValue(bytecode.Main.Instructions.String(), expected.String(), t)
+		utils.ValidateValue(len(bytecode.Constants), tt.numConstants, t)
+	}
+}
+
+func TestCompileFunction(t *testing.T) {
+	bytecode := compile(t, "val f = func(a, a, b) { if (b) { val c = a }; var d = c; d = 1 }")
+	function := compiledFunctions(bytecode)[0]
+	utils.ValidateValue(len(function.Parameters), 3, t)
+	utils.ValidateValue(function.Parameters[0], 0, t)
+	utils.ValidateValue(function.Parameters[1], -1, t)
+	utils.ValidateValue(function.Parameters[2], 1, t)
+	utils.ValidateValue(function.NumSlots, 4, t)
+	utils.ValidateValue(function.Instructions[len(function.Instructions)-1], byte(OpReturnValue), t)
+}
+
+func TestResolvedVariables(t *testing.T) {
+	input := `val f = func(x) {
+  val g = func(y) {
+    if (y) { val x = 1 }
+    x + y + f
+  }
+  try { 1 } catch (x) { x }
+}`
+	bytecode := compile(t, input)
+	g, f := compiledFunctions(bytecode)[0], compiledFunctions(bytecode)[1]
+	variables := map[string]*Variable{}
+	for _, variable := range g.Variables {
+		variables[variable.Name] = variable
+	}
+	utils.ValidateValue(*variables["x"], Variable{Name: "x", Depth: 0, Index: 1}, t)
+	utils.ValidateValue(*variables["y"], Variable{Name: "y", Depth: 0, Index: 0}, t)
+	utils.ValidateValue(variables["f"].Global, true, t)
+
+	// the catch block has its own scope, holding its parameter
+	catchParameter := f.Variables[len(f.Variables)-1]
+	utils.ValidateValue(*catchParameter, Variable{Name: "x", Depth: 0, Index: 0}, t)
+	utils.ValidateValue(f.NumSlots, 2, t)
+
+	// the identifiers referring to the same variable share one entry
+	utils.ValidateValue(len(g.Variables), 3, t)
+
+	global := bytecode.Main.Variables[0]
+	utils.ValidateValue(global.Name, "f", t)
+	utils.ValidateValue(global.Global, true, t)
+}
+
+func TestCompilerKeepsConstants(t *testing.T) {
+	c := New()
+	first, err := c.Compile(parse(t, "val f = func() { 1 }", globals{}))
+	utils.ValidateValue(err == nil, true, t)
+	second, err := c.Compile(parse(t, "f() + 2", globals{"f": true}))
+	utils.ValidateValue(err == nil, true, t)
+	utils.ValidateValue(len(first.Constants), 2, t)
+	utils.ValidateValue(len(second.Constants), 3, t)
+	utils.ValidateValue(second.Constants[1] == first.Constants[1], true, t)
+	utils.ValidateValue(len(compiledFunctions(second)), 1, t)
+	utils.ValidateObject(second.Constants[2], object.NewInteger(2), t)
+}
+
+// globals are the variables declared by previous programs, for the resolver.
+type globals map[string]bool
+
+func (g globals) IsDeclared(name string) bool {
+	return g[name]
+}
+
+func (g globals) IsBuiltin(name string) bool {
+	return evaluator.IsBuiltin(name)
+}
+
+func parse(t *testing.T, input string, declared globals) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	utils.ValidateValue(len(p.Errors()), 0, t)
+	if errors := resolver.Resolve(program, declared); len(errors) > 0 {
+		t.Fatalf("failed to resolve %q: %s", input, errors[0].Message)
+	}
+	return program
+}
+
+func compile(t *testing.T, input string) *Bytecode {
+	bytecode, err := New().Compile(parse(t, input, globals{}))
+	if err != nil {
+		t.Fatalf("failed to compile %q: %s", input, err)
+	}
+	return bytecode
+}
+
+// compiledFunctions returns the functions of the bytecode, where inner functions come before the outer ones.
+func compiledFunctions(bytecode *Bytecode) []*CompiledFunction {
+	var functions []*CompiledFunction
+	for _, constant := range bytecode.Constants {
+		if function, ok := constant.(*CompiledFunction); ok {
+			functions = append(functions, function)
+		}
+	}
+	return functions
+}
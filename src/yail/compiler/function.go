@@ -0,0 +1,68 @@
+package compiler
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"yail/ast"
+	"yail/object"
+	"yail/token"
+)
+
+const COMPILED_FUNCTION_OBJ = "COMPILED_FUNCTION"
+
+// CompiledFunction is the bytecode of a function literal, or of a whole program.
+type CompiledFunction struct {
+	Instructions Instructions
+	// Parameters are the slots receiving the arguments, or -1 for parameters repeating the name of a previous one.
+	Parameters []int
+	// NumSlots is the number of variables the function declares, including its parameters.
+	NumSlots  int
+	Variables []*Variable
+	// Positions maps the offset of instructions to the position of the innermost node they were compiled from
+	// that keeps one, which is where the errors raised by the instructions are reported.
+	Positions map[int]token.Position
+	// Literal is the source of the function, or nil for a program.
+	Literal *ast.FunctionLiteral
+}
+
+// Variable describes where an identifier may be bound when an instruction runs.
+// As blocks do not create scopes, a declaration inside an if expression may be skipped,
+// so a variable is looked up in each scope declaring its name, from the innermost one.
+type Variable struct {
+	Name string
+	// Depth and Index locate the slot of the variable, Depth scopes above the current one.
+	Depth int
+	Index int
+	// Global is true when the variable is not in a slot, but kept by name outside of any function
+	// so that later programs can use it.
+	Global bool
+	// Mutable is true for variables declared with var.
+	Mutable bool
+}
+
+func (cf *CompiledFunction) Type() object.ObjectType {
+	return COMPILED_FUNCTION_OBJ
+}
+
+func (cf *CompiledFunction) Inspect() string {
+	if cf.Literal == nil {
+		return "program"
+	}
+	var out bytes.Buffer
+	params := []string{}
+	for _, p := range cf.Literal.Parameters {
+		params = append(params, p.String())
+	}
+	out.WriteString("fn")
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") {\n")
+	out.WriteString(cf.Literal.Body.String())
+	out.WriteString("\n}")
+	return out.String()
+}
+
+func (cf *CompiledFunction) String() string {
+	return fmt.Sprintf("%s\n%s", cf.Inspect(), cf.Instructions)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"yail/stdlib"
+)
+
+// doc prints the modules of the standard library, or the functions of the given modules, and returns the exit code
+// of the command: 0 when the modules exist, and 2 otherwise.
+func doc(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		for _, name := range stdlib.Names() {
+			module, _ := stdlib.Lookup(name)
+			fmt.Fprintf(out, "%s: %s\n", name, module.Doc)
+		}
+		return 0
+	}
+	for _, name := range args {
+		module, ok := stdlib.Lookup(name)
+		if !ok {
+			fmt.Fprintf(out, "unknown module: %s\n", name)
+			return 2
+		}
+		fmt.Fprintf(out, "%s: %s\n", name, module.Doc)
+		for _, function := range module.Functions {
+			fmt.Fprintf(out, "  %s\n", function.Doc)
+		}
+	}
+	return 0
+}
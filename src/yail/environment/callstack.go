@@ -0,0 +1,51 @@
+package environment
+
+import "yail/object"
+
+// DEFAULT_MAX_CALL_DEPTH keeps deep recursion far from exhausting the stack of the Go runtime.
+const DEFAULT_MAX_CALL_DEPTH = 10000
+
+// CallStack keeps the function calls in progress, and is shared by all the environments of a program.
+type CallStack struct {
+	frames   []object.StackFrame
+	maxDepth int
+}
+
+func NewCallStack() *CallStack {
+	return &CallStack{maxDepth: DEFAULT_MAX_CALL_DEPTH}
+}
+
+// SetMaxDepth changes the number of nested calls allowed before a stack overflow error is raised.
+func (s *CallStack) SetMaxDepth(depth int) {
+	s.maxDepth = depth
+}
+
+func (s *CallStack) MaxDepth() int {
+	return s.maxDepth
+}
+
+func (s *CallStack) Push(frame object.StackFrame) {
+	s.frames = append(s.frames, frame)
+}
+
+// Rename changes the function of the innermost call, which is how a tail call reuses the frame of its caller.
+func (s *CallStack) Rename(function string) {
+	s.frames[len(s.frames)-1].Function = function
+}
+
+func (s *CallStack) Pop() {
+	s.frames = s.frames[:len(s.frames)-1]
+}
+
+func (s *CallStack) Depth() int {
+	return len(s.frames)
+}
+
+// Frames returns a copy of the calls in progress, from the innermost.
+func (s *CallStack) Frames() []object.StackFrame {
+	frames := make([]object.StackFrame, len(s.frames))
+	for i, frame := range s.frames {
+		frames[len(s.frames)-1-i] = frame
+	}
+	return frames
+}
@@ -6,18 +6,109 @@ import (
 )
 
 type Environment struct {
+	// dataStorage holds the variables looked up by name, which are the global variables.
 	dataStorage map[string]value
-	outerScope  *Environment
+	// slots holds the variables resolved ahead of time, by index. A slot is undeclared while its data is nil.
+	slots      []value
+	outerScope *Environment
+	runtime    *runtime
+	// file is the file of the program or the module the environment belongs to, which is only set for the
+	// environment of the program itself.
+	file string
+}
+
+// runtime is the state of the running program, shared by all of its environments and the modules it imports.
+type runtime struct {
+	callStack *CallStack
+	budget    Budget
+	modules   *Modules
+	caller    ForeignCaller
+}
+
+// ForeignCaller calls the functions the evaluator did not create, such as the closures of the virtual machine
+// given to the functions of a module.
+type ForeignCaller func(fn object.Object, args []object.Object, callSite token.Position) object.Object
+
+// Budget is consulted by the evaluator to bound the work done by a program.
+// Each method returns an error once the program must be stopped.
+type Budget interface {
+	// Step is called for every node evaluated.
+	Step() *object.Error
+	// Allocate is called for every object created.
+	Allocate() *object.Error
 }
 
 func NewEnvironment() *Environment {
 	s := make(map[string]value)
-	return &Environment{dataStorage: s, outerScope: nil}
+	return &Environment{dataStorage: s, outerScope: nil, runtime: &runtime{callStack: NewCallStack(), modules: NewModules(SearchPathFromEnv())}}
 }
 
-func NewInnerEnvironment(outer *Environment) *Environment {
-	s := make(map[string]value)
-	return &Environment{dataStorage: s, outerScope: outer}
+// NewModuleEnvironment creates the environment of a module read from the given file,
+// which shares the state of the program importing it.
+func NewModuleEnvironment(importer *Environment, file string) *Environment {
+	return &Environment{dataStorage: make(map[string]value), runtime: importer.runtime, file: file}
+}
+
+// NewInnerEnvironment creates the environment of a function call or of a catch block, with size slots for its
+// variables.
+func NewInnerEnvironment(outer *Environment, size int) *Environment {
+	return &Environment{slots: make([]value, size), outerScope: outer, runtime: outer.runtime}
+}
+
+// CallStack returns the function calls in progress in the program the environment belongs to.
+func (e *Environment) CallStack() *CallStack {
+	return e.runtime.callStack
+}
+
+// Modules returns the modules loaded by the program the environment belongs to.
+func (e *Environment) Modules() *Modules {
+	return e.runtime.modules
+}
+
+// File returns the file of the program or the module the environment belongs to, or an empty string if the program
+// was not read from a file.
+func (e *Environment) File() string {
+	env := e
+	for env.outerScope != nil {
+		env = env.outerScope
+	}
+	return env.file
+}
+
+// SetFile changes the file of the program the environment belongs to.
+func (e *Environment) SetFile(file string) {
+	e.file = file
+}
+
+// Budget returns the budget of the program the environment belongs to, or nil if it is unlimited.
+func (e *Environment) Budget() Budget {
+	return e.runtime.budget
+}
+
+// ForeignCaller returns the caller of the functions the evaluator did not create, or nil if there is none.
+func (e *Environment) ForeignCaller() ForeignCaller {
+	return e.runtime.caller
+}
+
+// SetForeignCaller changes the caller of the functions the evaluator did not create.
+func (e *Environment) SetForeignCaller(caller ForeignCaller) {
+	e.runtime.caller = caller
+}
+
+// SetBudget changes the budget of the program the environment belongs to,
+// and returns a function restoring the previous budget.
+func (e *Environment) SetBudget(budget Budget) (restore func()) {
+	previous := e.runtime.budget
+	e.runtime.budget = budget
+	return func() {
+		e.runtime.budget = previous
+	}
+}
+
+// Has reports whether the environment itself declares a variable by name.
+func (e *Environment) Has(name string) bool {
+	_, ok := e.dataStorage[name]
+	return ok
 }
 
 func (e *Environment) Get(name string) (object.Object, bool) {
@@ -33,24 +124,29 @@ func (e *Environment) Get(name string) (object.Object, bool) {
 
 func (e *Environment) ImmutableAssign(name string, val object.Object) (bool, *object.Error) {
 	if _, ok := e.dataStorage[name]; ok {
-		return false, object.NewError("given identifier '%s' is already declared", name)
+		return false, alreadyDeclared(name)
 	}
-	e.dataStorage[name] = newValue(val, false)
+	e.store(name, newValue(val, false))
 	return true, nil
 }
 
 func (e *Environment) MutableAssign(name string, val object.Object) (bool, *object.Error) {
 	if _, ok := e.dataStorage[name]; ok {
-		return false, object.NewError("given identifier '%s' is already declared", name)
+		return false, alreadyDeclared(name)
 	}
-	e.dataStorage[name] = newValue(val, true)
+	e.store(name, newValue(val, true))
 	return true, nil
 }
 
+// Reassign changes the global variable with the given name, which may be in an outer environment
+// when it is reassigned inside a catch block.
 func (e *Environment) Reassign(name string, val object.Object) (bool, *object.Error) {
 	data, ok := e.dataStorage[name]
+	if !ok && e.outerScope != nil {
+		return e.outerScope.Reassign(name, val)
+	}
 	if !ok {
-		return false, object.NewError("identifier not found: '%s'", name)
+		return false, object.NewErrorWithKind(object.NAME_ERROR, "identifier not found: '%s'", name)
 	}
 	if !data.isMutable {
 		return false, object.NewError("can not reassign variables declared with '%s'", token.VAL)
@@ -58,3 +154,53 @@ func (e *Environment) Reassign(name string, val object.Object) (bool, *object.Er
 	e.dataStorage[name] = newValue(val, true)
 	return true, nil
 }
+
+// GetAt returns the variable in the given slot of the environment depth levels above this one.
+func (e *Environment) GetAt(depth, index int) (object.Object, bool) {
+	env := e
+	for ; depth > 0; depth-- {
+		env = env.outerScope
+	}
+	data := env.slots[index].data
+	return data, data != nil
+}
+
+// DeclareAt declares the variable stored in the given slot of this environment.
+func (e *Environment) DeclareAt(index int, name string, val object.Object, mutable bool) (bool, *object.Error) {
+	if e.slots[index].data != nil {
+		return false, alreadyDeclared(name)
+	}
+	if val == nil {
+		val = object.NULL
+	}
+	e.slots[index] = newValue(val, mutable)
+	return true, nil
+}
+
+// ReassignAt changes the variable in the given slot of the environment depth levels above this one.
+func (e *Environment) ReassignAt(depth, index int, name string, val object.Object) (bool, *object.Error) {
+	env := e
+	for ; depth > 0; depth-- {
+		env = env.outerScope
+	}
+	data := env.slots[index]
+	if data.data == nil {
+		return false, object.NewErrorWithKind(object.NAME_ERROR, "identifier not found: '%s'", name)
+	}
+	if !data.isMutable {
+		return false, object.NewError("can not reassign variables declared with '%s'", token.VAL)
+	}
+	env.slots[index] = newValue(val, true)
+	return true, nil
+}
+
+func (e *Environment) store(name string, data value) {
+	if e.dataStorage == nil {
+		e.dataStorage = make(map[string]value)
+	}
+	e.dataStorage[name] = data
+}
+
+func alreadyDeclared(name string) *object.Error {
+	return object.NewErrorWithKind(object.NAME_ERROR, "given identifier '%s' is already declared", name)
+}
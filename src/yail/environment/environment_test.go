@@ -1,6 +1,7 @@
 package environment
 
 import (
+	"strings"
 	"testing"
 	"yail/object"
 	"yail/utils"
@@ -62,3 +63,74 @@ func TestCanNotReassignWithAssignFunctions(t *testing.T) {
 	utils.ValidateValue(err.Message, "given identifier 'x' is already declared", t)
 	utils.ValidateObject(obj, value, t)
 }
+
+func TestSlots(t *testing.T) {
+	env := NewEnvironment()
+	inner := NewInnerEnvironment(env, 2)
+
+	_, ok := inner.GetAt(0, 0)
+	utils.ValidateValue(ok, false, t)
+	_, err := inner.ReassignAt(0, 0, "x", object.NewInteger(1))
+	utils.ValidateValue(err.Message, "identifier not found: 'x'", t)
+
+	assignedOk, _ := inner.DeclareAt(0, "x", object.NewInteger(1), true)
+	_, err = inner.DeclareAt(0, "x", object.NewInteger(2), true)
+	utils.ValidateValue(assignedOk, true, t)
+	utils.ValidateValue(err.Message, "given identifier 'x' is already declared", t)
+
+	inner.ReassignAt(0, 0, "x", object.NewInteger(3))
+	obj, _ := inner.GetAt(0, 0)
+	utils.ValidateObject(obj, object.NewInteger(3), t)
+
+	inner.DeclareAt(1, "y", object.NewInteger(4), false)
+	_, err = inner.ReassignAt(0, 1, "y", object.NewInteger(5))
+	utils.ValidateValue(err.Message, "can not reassign variables declared with 'val'", t)
+
+	innermost := NewInnerEnvironment(NewInnerEnvironment(inner, 0), 0)
+	innermost.ReassignAt(2, 0, "x", object.NewInteger(6))
+	obj, _ = innermost.GetAt(2, 0)
+	utils.ValidateObject(obj, object.NewInteger(6), t)
+}
+
+func TestCallStackIsShared(t *testing.T) {
+	env := NewEnvironment()
+	inner := NewInnerEnvironment(NewInnerEnvironment(env, 0), 0)
+	inner.CallStack().Push(object.StackFrame{Function: "outer"})
+	inner.CallStack().Push(object.StackFrame{Function: "inner"})
+
+	utils.ValidateValue(env.CallStack().Depth(), 2, t)
+	frames := env.CallStack().Frames()
+	utils.ValidateValue(frames[0].Function, "inner", t)
+	utils.ValidateValue(frames[1].Function, "outer", t)
+
+	env.CallStack().Pop()
+	utils.ValidateValue(inner.CallStack().Depth(), 1, t)
+}
+
+func TestModules(t *testing.T) {
+	env := NewEnvironment()
+	env.SetFile("main.yail")
+	module := NewModuleEnvironment(env, "a.yail")
+	utils.ValidateValue(module.Modules() == env.Modules(), true, t)
+	utils.ValidateValue(NewInnerEnvironment(module, 0).File(), "a.yail", t)
+
+	modules := env.Modules()
+	endA, chain := modules.Begin("a.yail")
+	utils.ValidateValue(chain == nil, true, t)
+	endB, _ := modules.Begin("b.yail")
+	_, chain = modules.Begin("a.yail")
+	utils.ValidateValue(strings.Join(chain, " -> "), "a.yail -> b.yail -> a.yail", t)
+
+	module.ImmutableAssign("x", object.NewInteger(1))
+	module.ImmutableAssign("y", object.NewInteger(2))
+	endB(nil)
+	endA(NewModule("a.yail", module, []string{"x"}))
+	_, ok := modules.Loaded("b.yail")
+	utils.ValidateValue(ok, false, t)
+	loaded, ok := modules.Loaded("a.yail")
+	utils.ValidateValue(ok, true, t)
+	x, _ := loaded.Get("x")
+	utils.ValidateObject(x, object.NewInteger(1), t)
+	_, ok = loaded.Get("y")
+	utils.ValidateValue(ok, false, t)
+}
@@ -11,15 +11,20 @@ const FUNCTION_OBJ = "FUNCTION"
 
 // TODO: move to object package and handle import cycle
 type Function struct {
+	// Name is the name of the variable the function was first bound to, or empty for anonymous functions.
+	Name       string
 	Parameters []*ast.IdentifierExpression
 	Body       *ast.BlockStatement
-	Env        *Environment
+	// NumSlots is the number of variables the resolver found in the function.
+	NumSlots int
+	Env      *Environment
 }
 
 func NewFunction(node *ast.FunctionLiteral, env *Environment) *Function {
 	return &Function{
 		Parameters: node.Parameters,
 		Body:       node.Body,
+		NumSlots:   node.NumSlots,
 		Env:        env,
 	}
 }
@@ -41,3 +46,21 @@ func (f *Function) Inspect() string {
 	out.WriteString("\n}")
 	return out.String()
 }
+
+// ComposedFunction is the callable produced by `first >> second` and `second << first`.
+// Calling it applies First to the arguments and Second to the result.
+type ComposedFunction struct {
+	First  object.Object
+	Second object.Object
+}
+
+func NewComposedFunction(first, second object.Object) *ComposedFunction {
+	return &ComposedFunction{First: first, Second: second}
+}
+
+func (cf *ComposedFunction) Type() object.ObjectType {
+	return FUNCTION_OBJ
+}
+func (cf *ComposedFunction) Inspect() string {
+	return "composed function"
+}
@@ -0,0 +1,95 @@
+package environment
+
+import (
+	"os"
+	"path/filepath"
+	"yail/object"
+)
+
+const (
+	MODULE_OBJ = "MODULE"
+
+	// YAIL_PATH is the environment variable listing the directories where the modules are searched,
+	// when they are not found next to the importing file. The directories are separated as in the PATH variable.
+	YAIL_PATH = "YAIL_PATH"
+)
+
+// Module is a file evaluated by an import statement. Its exported variables are read as fields, such as `m.add`.
+type Module struct {
+	// Path is the canonical path of the file.
+	Path    string
+	Env     *Environment
+	exports map[string]bool
+}
+
+func NewModule(path string, env *Environment, exports []string) *Module {
+	module := &Module{Path: path, Env: env, exports: make(map[string]bool, len(exports))}
+	for _, name := range exports {
+		module.exports[name] = true
+	}
+	return module
+}
+
+func (m *Module) Type() object.ObjectType {
+	return MODULE_OBJ
+}
+func (m *Module) Inspect() string {
+	return "<module " + m.Path + ">"
+}
+
+// Get returns the current value of an exported variable of the module.
+func (m *Module) Get(name string) (object.Object, bool) {
+	if !m.exports[name] {
+		return nil, false
+	}
+	return m.Env.Get(name)
+}
+
+// Modules are the modules loaded by a program, evaluated once and cached by canonical path.
+type Modules struct {
+	// SearchPath are the directories where the modules are searched, after the directory of the importing file.
+	SearchPath []string
+	loaded     map[string]*Module
+	// loading are the modules being evaluated, from the first one imported, which form the import chain.
+	loading []string
+}
+
+func NewModules(searchPath []string) *Modules {
+	return &Modules{SearchPath: searchPath, loaded: make(map[string]*Module)}
+}
+
+// SearchPathFromEnv returns the directories listed by the YAIL_PATH environment variable.
+func SearchPathFromEnv() []string {
+	var directories []string
+	for _, directory := range filepath.SplitList(os.Getenv(YAIL_PATH)) {
+		if directory != "" {
+			directories = append(directories, directory)
+		}
+	}
+	return directories
+}
+
+// Loaded returns the module read from the file with the given canonical path, if it was already evaluated.
+func (m *Modules) Loaded(path string) (*Module, bool) {
+	module, ok := m.loaded[path]
+	return module, ok
+}
+
+// Begin records that the file with the given canonical path is being evaluated, and returns a function to call
+// once it is done. The module is cached when it is given to the returned function.
+// If the file is already being evaluated, Begin returns the import chain leading back to it instead.
+func (m *Modules) Begin(path string) (end func(module *Module), chain []string) {
+	for i, loading := range m.loading {
+		if loading == path {
+			chain = append(append([]string{}, m.loading[i:]...), path)
+			return nil, chain
+		}
+	}
+	m.loading = append(m.loading, path)
+	return func(module *Module) {
+		m.loading = m.loading[:len(m.loading)-1]
+		if module != nil {
+			m.loaded[path] = module
+		}
+	}, nil
+}
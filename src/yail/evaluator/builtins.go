@@ -1,6 +1,7 @@
 package evaluator
 
 import (
+	"unicode/utf8"
 	"yail/object"
 )
 
@@ -12,107 +13,122 @@ const (
 	PUSHLEFT = "pushleft"
 	POP      = "pop"
 	POPLEFT  = "popleft"
-
-	INVALID_TYPE_EXCEPTION_MESSAGE = "%s(%s) not supported"
-	INVALID_ARGUMENT_COUNT_MESSAGE = "wrong number of arguments: expected %d, but received %d"
+	ERROR    = "error"
+	TUPLE    = "tuple"
 )
 
 var builtinFunctions = map[string]*object.Builtin{
-	LEN: {
-		Fn: func(args ...object.Object) object.Object {
-			ok, err := validateArgCount(args, 1)
-			if !ok {
-				return err
-			}
-			switch arg := args[0].(type) {
-			case *object.String:
-				return &object.Integer{Value: int64(len(arg.Value))}
-			case *object.Array:
-				return &object.Integer{Value: int64(len(arg.Elements))}
-			default:
-				return object.NewError(INVALID_TYPE_EXCEPTION_MESSAGE, LEN, arg.Type())
-			}
-		},
-	},
-	HEAD: {
-		Fn: func(args ...object.Object) object.Object {
-			ok, err := validateArrayFunctionArguments(HEAD, 1, args)
-			if !ok {
-				return err
-			}
-			arr := args[0].(*object.Array)
-			if len(arr.Elements) > 0 {
-				return arr.Elements[0]
-			}
-			return object.NULL
-		},
-	},
-	TAIL: {
-		Fn: func(args ...object.Object) object.Object {
-			ok, err := validateArrayFunctionArguments(TAIL, 1, args)
-			if !ok {
-				return err
-			}
-			arr := args[0].(*object.Array)
-			length := len(arr.Elements)
-			if length > 0 {
-				return arr.Elements[length-1]
-			}
-			return object.NULL
-		},
-	},
-	PUSH: {
-		Fn: func(args ...object.Object) object.Object {
-			ok, err := validateArrayFunctionArguments(PUSH, 2, args)
-			if !ok {
-				return err
-			}
-			arr := args[0].(*object.Array)
-			arr.Elements = append(arr.Elements, args[1])
-			return object.NULL
-		},
-	},
-	PUSHLEFT: {
-		Fn: func(args ...object.Object) object.Object {
-			ok, err := validateArrayFunctionArguments(PUSHLEFT, 2, args)
-			if !ok {
-				return err
-			}
-			arr := args[0].(*object.Array)
-			arr.Elements = append([]object.Object{args[1]}, arr.Elements...)
-			return object.NULL
-		},
-	},
-	POP: {
-		Fn: func(args ...object.Object) object.Object {
-			ok, err := validateArrayFunctionArguments(POP, 1, args)
-			if !ok {
-				return err
-			}
-			arr := args[0].(*object.Array)
-			length := len(arr.Elements)
-			if length > 0 {
-				arr.Elements = arr.Elements[0 : length-1]
-				return arr
-			}
-			return object.NULL
-		},
-	},
-	POPLEFT: {
-		Fn: func(args ...object.Object) object.Object {
-			ok, err := validateArrayFunctionArguments(POPLEFT, 1, args)
-			if !ok {
-				return err
-			}
-			arr := args[0].(*object.Array)
-			length := len(arr.Elements)
-			if length > 0 {
-				arr.Elements = arr.Elements[1:length]
-				return arr
+	LEN: object.NewBuiltin(LEN, 1, 1, func(_ object.Applier, args ...object.Object) object.Object {
+		ok, err := validateArgCount(args, 1)
+		if !ok {
+			return err
+		}
+		switch arg := args[0].(type) {
+		case *object.String:
+			return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
+		case *object.Array:
+			return &object.Integer{Value: int64(len(arg.Elements))}
+		case *object.Tuple:
+			return &object.Integer{Value: int64(len(arg.Elements))}
+		case *object.HashMap:
+			return &object.Integer{Value: int64(arg.Len())}
+		default:
+			return object.NewErrorWithKind(object.TYPE_ERROR, object.INVALID_TYPE_EXCEPTION_MESSAGE, LEN, arg.Type())
+		}
+	}),
+	HEAD: object.NewBuiltin(HEAD, 1, 1, func(_ object.Applier, args ...object.Object) object.Object {
+		ok, err := validateArrayFunctionArguments(HEAD, 1, args)
+		if !ok {
+			return err
+		}
+		arr := args[0].(*object.Array)
+		if len(arr.Elements) > 0 {
+			return arr.Elements[0]
+		}
+		return object.NULL
+	}),
+	TAIL: object.NewBuiltin(TAIL, 1, 1, func(_ object.Applier, args ...object.Object) object.Object {
+		ok, err := validateArrayFunctionArguments(TAIL, 1, args)
+		if !ok {
+			return err
+		}
+		arr := args[0].(*object.Array)
+		length := len(arr.Elements)
+		if length > 0 {
+			return arr.Elements[length-1]
+		}
+		return object.NULL
+	}),
+	PUSH: object.NewBuiltin(PUSH, 2, 2, func(_ object.Applier, args ...object.Object) object.Object {
+		ok, err := validateArrayFunctionArguments(PUSH, 2, args)
+		if !ok {
+			return err
+		}
+		arr := args[0].(*object.Array)
+		arr.Elements = append(arr.Elements, args[1])
+		return object.NULL
+	}),
+	PUSHLEFT: object.NewBuiltin(PUSHLEFT, 2, 2, func(_ object.Applier, args ...object.Object) object.Object {
+		ok, err := validateArrayFunctionArguments(PUSHLEFT, 2, args)
+		if !ok {
+			return err
+		}
+		arr := args[0].(*object.Array)
+		arr.Elements = append([]object.Object{args[1]}, arr.Elements...)
+		return object.NULL
+	}),
+	POP: object.NewBuiltin(POP, 1, 1, func(_ object.Applier, args ...object.Object) object.Object {
+		ok, err := validateArrayFunctionArguments(POP, 1, args)
+		if !ok {
+			return err
+		}
+		arr := args[0].(*object.Array)
+		length := len(arr.Elements)
+		if length > 0 {
+			arr.Elements = arr.Elements[0 : length-1]
+			return arr
+		}
+		return object.NULL
+	}),
+	POPLEFT: object.NewBuiltin(POPLEFT, 1, 1, func(_ object.Applier, args ...object.Object) object.Object {
+		ok, err := validateArrayFunctionArguments(POPLEFT, 1, args)
+		if !ok {
+			return err
+		}
+		arr := args[0].(*object.Array)
+		length := len(arr.Elements)
+		if length > 0 {
+			arr.Elements = arr.Elements[1:length]
+			return arr
+		}
+		return object.NULL
+	}),
+	ERROR: newErrorBuiltin(),
+	TUPLE: object.NewBuiltin(TUPLE, 0, object.VARIADIC, func(_ object.Applier, args ...object.Object) object.Object {
+		return object.NewTuple(append([]object.Object{}, args...))
+	}),
+}
+
+// newErrorBuiltin creates the error function, which refers to itself to report a wrong number of arguments.
+func newErrorBuiltin() *object.Builtin {
+	var builtin *object.Builtin
+	builtin = object.NewBuiltin(ERROR, 1, 2, func(_ object.Applier, args ...object.Object) object.Object {
+		if !builtin.Accepts(len(args)) {
+			return builtin.ArgumentCountError(len(args))
+		}
+		for _, arg := range args {
+			if arg.Type() != object.STRING_OBJ {
+				return object.NewErrorWithKind(object.TYPE_ERROR, object.INVALID_TYPE_EXCEPTION_MESSAGE, ERROR, arg.Type())
 			}
-			return object.NULL
-		},
-	},
+		}
+		kind := object.THROWN_ERROR
+		if len(args) == 2 {
+			kind = args[0].(*object.String).Value
+		}
+		message := args[len(args)-1].(*object.String).Value
+		return object.NewErrorValue(object.NewErrorWithKind(kind, "%s", message))
+	})
+	return builtin
 }
 
 func validateArrayFunctionArguments(functionName string, expectedArgCount int, args []object.Object) (bool, *object.Error) {
@@ -121,14 +137,14 @@ func validateArrayFunctionArguments(functionName string, expectedArgCount int, a
 		return false, err
 	}
 	if args[0].Type() != object.ARRAY_OBJ {
-		return false, object.NewError(INVALID_TYPE_EXCEPTION_MESSAGE, functionName, args[0].Type())
+		return false, object.NewErrorWithKind(object.TYPE_ERROR, object.INVALID_TYPE_EXCEPTION_MESSAGE, functionName, args[0].Type())
 	}
 	return true, nil
 }
 
 func validateArgCount(args []object.Object, expectedArgCount int) (bool, *object.Error) {
 	if len(args) != expectedArgCount {
-		return false, object.NewError(INVALID_ARGUMENT_COUNT_MESSAGE, expectedArgCount, len(args))
+		return false, object.NewErrorWithKind(object.ARGUMENT_ERROR, object.INVALID_ARGUMENT_COUNT_MESSAGE, expectedArgCount, len(args))
 	}
 	return true, nil
 }
@@ -15,23 +15,26 @@ func evalArrayLiteral(node *ast.ArrayLiteral, env *environment.Environment) obje
 }
 
 func evalHashMapLiteral(node *ast.HashMapLiteral, env *environment.Environment) object.Object {
-	pairs := make(map[object.HashKey]*object.HashPair)
-	for keyNode, valueNode := range node.Pairs {
-		key := Eval(keyNode, env)
+	hashMap := object.NewHashMap()
+	for _, pair := range node.Pairs {
+		key := Eval(pair.Key, env)
 		if isError(key) {
 			return key
 		}
-		hashKey, ok := key.(object.Hashable)
-		if !ok {
-			return object.NewError("%s can not be used as hash key", key.Type())
+		hashKey, err := object.AsKey(key, applier(env, node.Token.Position))
+		if err != nil {
+			return err
 		}
-		value := Eval(valueNode, env)
+		value := Eval(pair.Value, env)
 		if isError(value) {
 			return value
 		}
-		pairs[hashKey.HashKey()] = object.NewHashPair(key, value)
+		hashMap.Set(hashKey, value)
+		if err := object.KeyError(hashKey); err != nil {
+			return err
+		}
 	}
-	return object.NewHashMap(pairs)
+	return hashMap
 }
 
 func evalCollectionAccess(node *ast.CollectionAccessExpression, env *environment.Environment) object.Object {
@@ -43,39 +46,58 @@ func evalCollectionAccess(node *ast.CollectionAccessExpression, env *environment
 	if isError(index) {
 		return index
 	}
-	return evalIndexExpression(left, index)
+	return Index(left, index, applier(env, node.Token.Position))
 }
 
-func evalIndexExpression(left, index object.Object) object.Object {
+// Index returns the element of an array or tuple, the value of a hash map or the field of an error for the given
+// index. apply calls the functions of the records used as keys.
+func Index(left, index object.Object, apply object.Applier) object.Object {
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
-		return evalArrayIndexAccessExpression(left, index)
+		return indexElements(left.(*object.Array).Elements, index)
+	case left.Type() == object.TUPLE_OBJ && index.Type() == object.INTEGER_OBJ:
+		return indexElements(left.(*object.Tuple).Elements, index)
 	case left.Type() == object.HASH_OBJ:
-		return evalHashKeyAccessExpression(left, index)
+		return evalHashKeyAccessExpression(left, index, apply)
+	case left.Type() == object.ERROR_VALUE_OBJ && index.Type() == object.STRING_OBJ:
+		return evalErrorFieldAccessExpression(left, index)
+	case left.Type() == environment.MODULE_OBJ && index.Type() == object.STRING_OBJ:
+		return evalModuleFieldAccessExpression(left, index)
 	default:
-		return object.NewError("unsupported operation: %s[%s]", left.Type(), index.Type())
+		return object.NewErrorWithKind(object.TYPE_ERROR, "unsupported operation: %s[%s]", left.Type(), index.Type())
 	}
 }
 
-func evalArrayIndexAccessExpression(array, index object.Object) object.Object {
-	arrayObject := array.(*object.Array)
+func indexElements(elements []object.Object, index object.Object) object.Object {
 	idx := index.(*object.Integer).Value
-	max := int64(len(arrayObject.Elements) - 1)
+	max := int64(len(elements) - 1)
 	if idx < 0 || idx > max {
 		return object.NULL
 	}
-	return arrayObject.Elements[idx]
+	return elements[idx]
 }
 
-func evalHashKeyAccessExpression(hashMap, index object.Object) object.Object {
+func evalHashKeyAccessExpression(hashMap, index object.Object, apply object.Applier) object.Object {
 	hashObject := hashMap.(*object.HashMap)
-	key, ok := index.(object.Hashable)
-	if !ok {
-		return object.NewError("unusable as hash key: %s", index.Type())
+	key, err := object.AsKey(index, apply)
+	if err != nil {
+		return err
+	}
+	pair, ok := hashObject.Get(key)
+	if err := object.KeyError(key); err != nil {
+		return err
 	}
-	pair, ok := hashObject.Pairs[key.HashKey()]
 	if !ok {
 		return object.NULL
 	}
 	return pair.Value
 }
+
+func evalErrorFieldAccessExpression(errorValue, index object.Object) object.Object {
+	name := index.(*object.String).Value
+	field, ok := errorValue.(*object.ErrorValue).Field(name)
+	if !ok {
+		return object.NewErrorWithKind(object.NAME_ERROR, "unknown error field: %s", name)
+	}
+	return field
+}
@@ -0,0 +1,75 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"yail/ast"
+	"yail/environment"
+	"yail/object"
+)
+
+// CONTEXT_CHECK_INTERVAL is the number of steps between two checks of the context,
+// which keeps the cost of the checks low while still stopping promptly.
+const CONTEXT_CHECK_INTERVAL = 64
+
+// Limits bounds the work done by EvalContext. Zero means no limit.
+type Limits struct {
+	// MaxSteps is the number of AST nodes that can be evaluated.
+	MaxSteps int64
+	// MaxAllocations is the number of objects that can be created, counting values, collections,
+	// functions and the environments of function calls.
+	MaxAllocations int64
+}
+
+// EvalContext is Eval for programs that must be stopped: it returns a fatal error once the context is
+// cancelled or its deadline passes, or once the program exceeds one of the limits.
+// Fatal errors can not be caught by try expressions.
+func EvalContext(ctx context.Context, node ast.Node, env *environment.Environment, limits Limits) object.Object {
+	restore := env.SetBudget(NewBudget(ctx, limits))
+	defer restore()
+	return Eval(node, env)
+}
+
+// NewBudget returns the budget EvalContext gives to the program, for the engines running programs
+// without the evaluator.
+func NewBudget(ctx context.Context, limits Limits) environment.Budget {
+	return &budget{ctx: ctx, limits: limits}
+}
+
+type budget struct {
+	ctx         context.Context
+	limits      Limits
+	steps       int64
+	allocations int64
+}
+
+func (b *budget) Step() *object.Error {
+	b.steps += 1
+	if b.limits.MaxSteps > 0 && b.steps > b.limits.MaxSteps {
+		return object.NewFatalError(object.STEP_LIMIT_EXCEEDED, "step limit of %d exceeded", b.limits.MaxSteps)
+	}
+	if b.steps%CONTEXT_CHECK_INTERVAL == 1 {
+		return b.checkContext()
+	}
+	return nil
+}
+
+func (b *budget) Allocate() *object.Error {
+	b.allocations += 1
+	if b.limits.MaxAllocations > 0 && b.allocations > b.limits.MaxAllocations {
+		return object.NewFatalError(object.ALLOCATION_LIMIT_EXCEEDED, "allocation limit of %d exceeded", b.limits.MaxAllocations)
+	}
+	return nil
+}
+
+func (b *budget) checkContext() *object.Error {
+	err := b.ctx.Err()
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return object.NewFatalError(object.DEADLINE_EXCEEDED, "evaluation deadline exceeded")
+	default:
+		return object.NewFatalError(object.CANCELLED, "evaluation cancelled")
+	}
+}
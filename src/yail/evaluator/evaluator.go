@@ -4,21 +4,88 @@ import (
 	"yail/ast"
 	"yail/environment"
 	"yail/object"
+	"yail/resolver"
+	"yail/token"
+	"yail/types"
 )
 
 func Eval(node ast.Node, env *environment.Environment) object.Object {
+	budget := env.Budget()
+	if budget != nil {
+		if err := budget.Step(); err != nil {
+			return err
+		}
+	}
+	var result object.Object
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node, env)
 	case ast.Statement:
-		return evalStatement(node, env)
+		result = evalStatement(node, env)
 	case ast.Expression:
-		return evalExpression(node, env)
+		result = evalExpression(node, env)
+		if budget != nil && allocates(node, result) {
+			if err := budget.Allocate(); err != nil {
+				result = err
+			}
+		}
 	}
-	return nil
+	if err, ok := result.(*object.Error); ok && err.Position == (token.Position{}) {
+		err.Position = ast.PositionOf(node)
+	}
+	return result
+}
+
+// allocates reports whether evaluating the expression created its result, instead of returning an existing object.
+func allocates(node ast.Expression, result object.Object) bool {
+	switch node.(type) {
+	case *ast.IdentifierExpression, *ast.CollectionAccessExpression, *ast.IfExpression, *ast.TryExpression, *ast.CallExpression:
+		return false
+	}
+	switch result.(type) {
+	case *object.Integer, *object.String, *object.Array, *object.HashMap, *environment.Function, *environment.ComposedFunction:
+		return true
+	}
+	return false
 }
 
+// evalProgram resolves the variables of the program and checks its types before evaluating it, so that no statement
+// runs if the program uses an identifier that is never declared or declares a variable twice.
 func evalProgram(program *ast.Program, env *environment.Environment) object.Object {
+	if err := Check(program, env); err != nil {
+		return err
+	}
+	return evalStatements(program, env)
+}
+
+// Check resolves the variables of the program and checks its types, as Eval does before evaluating a program,
+// and returns the first error found.
+func Check(program *ast.Program, env *environment.Environment) *object.Error {
+	if errors := resolver.Resolve(program, globals{env}); len(errors) > 0 {
+		return errors[0]
+	}
+	if errors := types.Check(program); len(errors) > 0 {
+		return errors[0]
+	}
+	return nil
+}
+
+// EvalChecked evaluates a program accepted by Check, which may have been rewritten since, such as by the optimizer.
+// Its variables are resolved again, but its types are not checked again.
+func EvalChecked(program *ast.Program, env *environment.Environment) object.Object {
+	if errors := resolver.Resolve(program, globals{env}); len(errors) > 0 {
+		return errors[0]
+	}
+	return evalStatements(program, env)
+}
+
+// evalStatements evaluates the statements of a resolved program. The imports of a program read from a file are
+// searched next to it.
+func evalStatements(program *ast.Program, env *environment.Environment) object.Object {
+	if program.File != "" {
+		env.SetFile(program.File)
+	}
+
 	var result object.Object
 
 	for _, stmt := range program.Statements {
@@ -33,6 +100,19 @@ func evalProgram(program *ast.Program, env *environment.Environment) object.Obje
 	return result
 }
 
+// globals tells the resolver about the variables declared by the previous programs evaluated in the environment.
+type globals struct {
+	env *environment.Environment
+}
+
+func (g globals) IsDeclared(name string) bool {
+	return g.env.Has(name)
+}
+
+func (g globals) IsBuiltin(name string) bool {
+	return IsBuiltin(name)
+}
+
 func isError(obj object.Object) bool {
 	if obj != nil {
 		return obj.Type() == object.ERROR_OBJ
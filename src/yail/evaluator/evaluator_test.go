@@ -1,12 +1,17 @@
 package evaluator
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 	"yail/environment"
 	"yail/lexer"
 	"yail/object"
 	"yail/parser"
+	"yail/token"
 	"yail/utils"
 )
 
@@ -25,6 +30,33 @@ func TestEvalIntegerExpression(t *testing.T) {
 		{"1 + 3 / 2", 2},
 		{"1 + 10 % 4", 3},
 		{"(1 + 2) * (5 - 2)", 9},
+		{"12 & 10", 8},
+		{"12 | 10", 14},
+		{"12 ^ 10", 6},
+		{"~0", -1},
+		{"~5 & 7", 2},
+		{"1 << 10", 1024},
+		{"-1 << 63", -9223372036854775808},
+		{"1024 >> 3", 128},
+		{"-16 >> 2", -4},
+		{"2 ** 10", 1024},
+		{"0xFF", 255},
+		{"0XfF", 255},
+		{"0o755", 493},
+		{"0b1010", 10},
+		{"1_000_000", 1000000},
+		{"0b1111_0000 | 0x0F", 255},
+		{"-0x7FFF_FFFF_FFFF_FFFF - 1", -9223372036854775808},
+		{"9223372036854775807", 9223372036854775807},
+		{"2 ** 3 ** 2", 512},
+		{"-2 ** 2", -4},
+		{"(-2) ** 3", -8},
+		{"5 ** 0", 1},
+		{"0 ** 0", 1},
+		{"2 ** 62", 4611686018427387904},
+		{"(-2) ** 63", -9223372036854775808},
+		{"1 + 2 ** 3 * 2", 17},
+		{"240 | 1 << 2 & 12", 244},
 	}
 
 	for _, tt := range tests {
@@ -41,6 +73,17 @@ func TestEvalStringExpression(t *testing.T) {
 		{`"Hello World!"`, "Hello World!"},
 		{`"Hello" + "World"`, "HelloWorld"},
 		{`""`, ""},
+		{`"C:\Users\n\t\\d"`, `C:\Users\n\t\\d`},
+		{`val name = "Yail"; "Hello $name!"`, "Hello Yail!"},
+		{`val user = {"name": "Kim"}; val count = 3; "Hello ${user.name}, you have $count items"`, "Hello Kim, you have 3 items"},
+		{`"${1 + 2} = ${[1, 2][0] + 2}"`, "3 = 3"},
+		{`"${true}, ${null}, ${[1, "a"]}"`, "true, null, [1, a]"},
+		{`val f = func(x) { x * 2; }; "${f(2)}${"-"}${f(3)}"`, "4-6"},
+		{`"costs $5 or \${price}"`, "costs $5 or ${price}"},
+		{"val name = \"Yail\"\nval greeting = \"Hi $name\"\ngreeting", "Hi Yail"},
+		{`"""say "hi" \n $name"""`, `say "hi" \n $name`},
+		{"val query = \"\"\"\n    SELECT *\n      FROM t\n  \"\"\"\nquery", "SELECT *\n  FROM t"},
+		{`val map = {"a": {"b": "c"}}; map.a.b`, "c"},
 	}
 
 	for _, tt := range tests {
@@ -193,6 +236,56 @@ func TestFunction(t *testing.T) {
 	}
 }
 
+func TestPipeline(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"val double = func(x) { x * 2; }; 5 |> double;", 10},
+		{"val add = func(x, y) { x + y; }; 5 |> add(3);", 8},
+		{"val add = func(x, y) { x + y; }; val double = func(x) { x * 2; }; 1 |> add(2) |> double;", 6},
+		{"val double = func(x) { x * 2; }; 1 + 2 |> double == 6;", true},
+		{"[1, 2, 3] |> len;", 3},
+		{"val arr = [1, 2]; arr |> push(3); arr;", []int64{1, 2, 3}},
+		{"5 |> func(x) { x - 1; };", 4},
+	}
+	for _, tt := range tests {
+		testObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestComposition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"val inc = func(x) { x + 1; }; val double = func(x) { x * 2; }; (inc >> double)(5);", 12},
+		{"val inc = func(x) { x + 1; }; val double = func(x) { x * 2; }; (inc << double)(5);", 11},
+		{"val inc = func(x) { x + 1; }; val f = inc >> inc >> inc; f(0);", 3},
+		{"val inc = func(x) { x + 1; }; val lenPlusOne = len >> inc; lenPlusOne([1, 2]);", 3},
+		{"val inc = func(x) { x + 1; }; 5 |> inc >> func(x) { x * 10; };", 60},
+		{"val inc = func(x) { x + 1; }; val f = inc >> inc; f == f;", true},
+	}
+	for _, tt := range tests {
+		testObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestOptionalSemicolons(t *testing.T) {
+	input := `
+			val sum = func(x, y) {
+			  val result = x +
+			    y
+			  return result
+			}
+			var total = sum(1, 2)
+			total = total |> sum(
+			  10
+			)
+			total`
+	testObject(t, testEval(input), 13)
+}
+
 func TestNestedScopes(t *testing.T) {
 	bindings := `
 			var i = 5; 
@@ -218,6 +311,67 @@ func TestNestedScopes(t *testing.T) {
 	}
 }
 
+func TestStaticScopes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"val x = 1; val f = func() { if (true) { val x = 2 }; x }; f()", 2},
+		{"val x = 1; val f = func(c) { if (c) { val x = 2 }; x }; f(false)", "identifier not found: x"},
+		{"val f = func() { try { throw 1 } catch (e) { func() { e.value } } }; f()()", 1},
+		{"val f = func() { var x = 1; try { throw 2 } catch (e) { val y = e.value; x = x + y }; x }; f()", 3},
+		{"try { throw 1 } catch (e) { val leaked = 5; 1 }; leaked", "identifier not found: leaked"},
+		{"val x = 1; val f = func() { val y = x; val x = 2; y }; f()", 1},
+		{"val x = 1; val f = func() { val y = x; val x = 2; y + x }; f()", 3},
+		{"val x = 1; val f = func() { try { throw 2 } catch (e) { val y = x; val x = e.value; y * 10 + x } }; f()", 12},
+		{"val f = func() { val y = z; val z = 2; y }; f()", "identifier not found: z"},
+		{"val f = func() { val x = 1; if (true) { var x = 2 } }", "given identifier 'x' is already declared"},
+		{"val g = func() { try { 1 } catch (e) { val e = 2 } }", "given identifier 'e' is already declared"},
+		{"val f = func() { g() }; val g = func() { 3 }; f()", 3},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if message, ok := tt.expected.(string); ok {
+			err, ok := evaluated.(*object.Error)
+			utils.ValidateValue(ok, true, t)
+			if ok {
+				utils.ValidateValue(err.Kind, object.NAME_ERROR, t)
+				utils.ValidateValue(err.Message, message, t)
+			}
+			continue
+		}
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestResolveBeforeEvaluation(t *testing.T) {
+	env := environment.NewEnvironment()
+	evaluated := Eval(parser.New(lexer.New("val a = 1\nval f = func() { b }")).ParseProgram(), env)
+	err, ok := evaluated.(*object.Error)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(err.Message, "identifier not found: b", t)
+	utils.ValidateValue(err.Position, token.Position{Line: 2, Column: 18}, t)
+	utils.ValidateValue(env.Has("a"), false, t)
+
+	Eval(parser.New(lexer.New("val a = 1")).ParseProgram(), env)
+	evaluated = Eval(parser.New(lexer.New("val b = a; val a = 2")).ParseProgram(), env)
+	utils.ValidateValue(evaluated.(*object.Error).Message, "given identifier 'a' is already declared", t)
+	testObject(t, Eval(parser.New(lexer.New("a + 1")).ParseProgram(), env), 2)
+}
+
+func TestTypeCheckBeforeEvaluation(t *testing.T) {
+	env := environment.NewEnvironment()
+	evaluated := Eval(parser.New(lexer.New("val a = 1\nval b: String = a")).ParseProgram(), env)
+	err, ok := evaluated.(*object.Error)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(err.Kind, object.TYPE_ERROR, t)
+	utils.ValidateValue(err.Message, "'b' is declared as String, but is given Int", t)
+	utils.ValidateValue(err.Position, token.Position{Line: 2, Column: 5}, t)
+	utils.ValidateValue(env.Has("a"), false, t)
+
+	testObject(t, Eval(parser.New(lexer.New("val f = func(a: Int, b: Int?): Int { a }; f(1, null)")).ParseProgram(), env), 1)
+}
+
 func TestClosures(t *testing.T) {
 	input := `
 			val newAdder = func(x) {
@@ -272,25 +426,30 @@ func TestHashMapLiterals(t *testing.T) {
 		true: 5,
 		false: 6
 	}`
-	expected := map[object.HashKey]int64{
-		object.NewString("one").HashKey():   1,
-		object.NewString("two").HashKey():   2,
-		object.NewString("three").HashKey(): 3,
-		object.NewInteger(4).HashKey():      4,
-		object.TRUE.HashKey():               5,
-		object.FALSE.HashKey():              6,
+	expected := []struct {
+		key   object.Hashable
+		value int64
+	}{
+		{object.NewString("one"), 1},
+		{object.NewString("two"), 2},
+		{object.NewString("three"), 3},
+		{object.NewInteger(4), 4},
+		{object.TRUE, 5},
+		{object.FALSE, 6},
 	}
 
 	evaluated := testEval(input)
 	result, ok := evaluated.(*object.HashMap)
 	utils.ValidateValue(ok, true, t)
-	utils.ValidateValue(len(result.Pairs), len(expected), t)
+	utils.ValidateValue(result.Len(), len(expected), t)
 
-	for expectedKey, expectedValue := range expected {
-		pair, ok := result.Pairs[expectedKey]
+	for _, tt := range expected {
+		pair, ok := result.Get(tt.key)
 		utils.ValidateValue(ok, true, t)
-		testObject(t, pair.Value, expectedValue)
+		testObject(t, pair.Value, tt.value)
 	}
+	// the pairs are printed in the order of the source
+	utils.ValidateValue(result.Inspect(), "{one: 1, two: 2, three: 3, 4: 4, true: 5, false: 6}", t)
 }
 
 func TestHashMapAccessExpressions(t *testing.T) {
@@ -304,6 +463,28 @@ func TestHashMapAccessExpressions(t *testing.T) {
 		{`val key = "foo"; {"foo": 5}[key]`, 5},
 		{`{100: { "a": 1, "b": 2 }}[100]["a"]`, 1},
 		{`{true: [10, 20, 30]}[true][0]`, 10},
+		{`{3: 5}[len([1, 2, 3])]`, 5},
+		{`{1: 5, "1": 6, true: 7}["1"]`, 6},
+		{`{0: 5, false: 6}[0]`, 5},
+		{`{[1, 2]: 5}[[1, 2]]`, 5},
+		{`{[1, 2]: 5}[[2, 1]]`, nil},
+		{`{[1, [2]]: 5}[tuple(1, tuple(2))]`, 5},
+		{`val key = [1]; val h = {key: 5}; push(key, 2); h[key]`, nil},
+		{`val key = [1]; val h = {key: 5}; push(key, 2); h[[1]]`, 5},
+		{`tuple(1, 2)[1]`, 2},
+		{`len(tuple())`, 0},
+		{`tuple(1, 2) == tuple(1, 2)`, true},
+		{`tuple(1, 2) != tuple(1, 2)`, false},
+		{`tuple(1, 2) == tuple(2, 1)`, false},
+		{`tuple(1) == tuple(1, 2)`, false},
+		{`tuple("a", [1, tuple(true)]) == tuple("a", tuple(1, [true]))`, true},
+		{`tuple(null, 1) == tuple(null, 1)`, true},
+		{`tuple({}) == tuple({})`, false},
+		{`val t = tuple(1); t == t`, true},
+		{`tuple(1) == 1`, false},
+		{`import "maps" as maps; maps.keys({[1, 2]: 3})[0] == tuple(1, 2)`, true},
+		{`val a = [1]; push(a, a); tuple(a) == tuple(a)`, true},
+		{`import "arrays" as arrays; val a = [1]; push(a, a); len(arrays.distinct([a, [1], a, [1]]))`, 2},
 	}
 
 	for _, tt := range tests {
@@ -312,6 +493,25 @@ func TestHashMapAccessExpressions(t *testing.T) {
 	}
 }
 
+func TestRecordKeys(t *testing.T) {
+	input := `val point = func(x, y) {
+		{"x": x, "y": y, "hash": func(p) { p["x"] * 31 + p["y"] }, "equals": func(p, q) { if (p["x"] == q["x"]) { p["y"] == q["y"] } else { false } }}
+	};
+	val names = {point(0, 0): "origin", point(1, 2): "a", point(1, 2): "b"};
+	[names[point(1, 2)], names[point(2, 1)], names[[point(0, 0)][0]], len(names), names[tuple(point(0, 0))]]`
+	utils.ValidateValue(testEval(input).Inspect(), "[b, null, origin, 2, null]", t)
+}
+
+func TestDistinctRecords(t *testing.T) {
+	// the points share a hash, so that only their equals function tells them apart
+	input := `import "arrays" as arrays;
+	val point = func(x, y) {
+		{"x": x, "y": y, "hash": func(p) { 0 }, "equals": func(p, q) { if (p["x"] == q["x"]) { p["y"] == q["y"] } else { false } }}
+	};
+	arrays.map(arrays.distinct([point(1, 2), point(2, 1), point(1, 2), point(2, 1)]), func(p) { p["x"] * 10 + p["y"] })`
+	utils.ValidateValue(testEval(input).Inspect(), "[12, 21]", t)
+}
+
 func TestEvalBuiltinFunction(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -319,8 +519,12 @@ func TestEvalBuiltinFunction(t *testing.T) {
 	}{
 		{`len("")`, 0},
 		{`len("Hello World")`, 11},
+		{`len("héllo, 世界")`, 9},
+		{`import "strings" as strings; val s = "héllo"; strings.indexOf(strings.substring(s, 1, len(s)), "o")`, 3},
 		{`len([])`, 0},
 		{`len(["a", "b"])`, 2},
+		{`len({})`, 0},
+		{`len({"a": 1, 2: [3, 4]})`, 2},
 		{`head([1, 2, 3])`, 1},
 		{`head([])`, nil},
 		{`tail([1, 2, 3])`, 3},
@@ -385,6 +589,26 @@ func TestErrorHandling(t *testing.T) {
 			`{"key": "abc"}[func(x) {x}];`,
 			"unusable as hash key: FUNCTION",
 		},
+		{
+			`{[1, null]: 2}`,
+			"unusable as hash key: NULL",
+		},
+		{
+			`val a = [1]; push(a, a); {a: 1}`,
+			"unusable as hash key: ARRAY containing itself",
+		},
+		{
+			`val a = [1]; val t = tuple(a); push(a, t); try { {t: 1} } catch (e) { throw e.message }`,
+			"unusable as hash key: TUPLE containing itself",
+		},
+		{
+			`{{"hash": func(r) { "a" }, "equals": func(r, s) { true }}: 1}`,
+			"the hash function of a record returned STRING instead of an integer",
+		},
+		{
+			`val r = func() { {"hash": func(r) { 1 }, "equals": func(r, s) { 1 }} }; {r(): 1}[r()]`,
+			"the equals function of a record returned INTEGER instead of a boolean",
+		},
 		{
 			`len(1)`,
 			"len(INTEGER) not supported",
@@ -393,6 +617,90 @@ func TestErrorHandling(t *testing.T) {
 			`len("one", "two")`,
 			"wrong number of arguments: expected 1, but received 2",
 		},
+		{
+			"2 ** 63",
+			"integer overflow: 2 ** 63",
+		},
+		{
+			"3 ** 40",
+			"integer overflow: 3 ** 40",
+		},
+		{
+			"2 ** -1",
+			"negative exponent: 2 ** -1",
+		},
+		{
+			"1 << -1",
+			"negative shift count: 1 << -1",
+		},
+		{
+			"1 << 64",
+			"shift count too large: 1 << 64",
+		},
+		{
+			"-16 >> 70",
+			"shift count too large: -16 >> 70",
+		},
+		{
+			"9223372036854775807 + 1",
+			"integer overflow: 9223372036854775807 + 1",
+		},
+		{
+			"-9223372036854775807 - 2",
+			"integer overflow: -9223372036854775807 - 2",
+		},
+		{
+			"4611686018427387904 * 2",
+			"integer overflow: 4611686018427387904 * 2",
+		},
+		{
+			"val min = -9223372036854775807 - 1; min / -1",
+			"integer overflow: -9223372036854775808 / -1",
+		},
+		{
+			"val min = -9223372036854775807 - 1; -min",
+			"integer overflow: -(-9223372036854775808)",
+		},
+		{
+			"~true",
+			"unknown operator: ~BOOLEAN",
+		},
+		{
+			"true & false",
+			"unknown operator: BOOLEAN & BOOLEAN",
+		},
+		{
+			`"a" | 1`,
+			"type mismatch: STRING | INTEGER",
+		},
+		{
+			`"Hello ${name}"`,
+			"identifier not found: name",
+		},
+		{
+			`"${1 + true}"`,
+			"type mismatch: INTEGER + BOOLEAN",
+		},
+		{
+			"10 / 0",
+			"division by zero: 10 / 0",
+		},
+		{
+			"10 % 0",
+			"division by zero: 10 % 0",
+		},
+		{
+			"val add = func(x, y) { x + y; }; add(1);",
+			"wrong number of arguments: expected 2, but received 1",
+		},
+		{
+			"val inc = func(x) { x + 1; }; (inc >> 5)(1);",
+			"type mismatch: FUNCTION >> INTEGER",
+		},
+		{
+			"val add = func(x, y) { x + y; }; 1 |> add;",
+			"wrong number of arguments: expected 2, but received 1",
+		},
 	}
 
 	for _, tt := range tests {
@@ -402,6 +710,271 @@ func TestErrorHandling(t *testing.T) {
 	}
 }
 
+func TestTryCatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"try { 1 } catch (e) { 2 }", 1},
+		{"try { 1 / 0 } catch (e) { 2 }", 2},
+		{"var x = 0; try { x = 1; throw 5; x = 2; } catch (e) { x = x + e.value; }; x", 6},
+		{"var x = 0; try { 1 / 0 } catch (e) { 2 } finally { x = 10 }; x", 10},
+		{"var x = 0; val r = try { 1 } finally { x = 10 }; r + x", 11},
+		{"val f = func() { try { return 1; } finally { return 2; } }; f()", 2},
+		{"val f = func() { try { throw 1; } catch (e) { return 3; }; 4 }; f()", 3},
+		{"val e = 7; try { throw 1 } catch (e) { e.value }; e", 7},
+		{"try { try { throw 1 } catch (e) { throw e.value + 1 } } catch (e) { e.value }", 2},
+		{"try { try { throw 1 } finally { 2 } } catch (e) { e.value + 10 }", 11},
+		{"try { } catch (e) { 1 }", nil},
+	}
+
+	for _, tt := range tests {
+		testObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestCaughtErrorValue(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`try { throw "boom" } catch (e) { e.message }`, "boom"},
+		{`try { throw "boom" } catch (e) { e.kind }`, "Error"},
+		{`try { throw error("ValidationError", "bad input") } catch (e) { e.kind + ": " + e.message }`, "ValidationError: bad input"},
+		{`val f = func(c) { if (c) { val x = 1 }; x }; try { f(false) } catch (e) { e.kind }`, "NameError"},
+		{`try { 1 + "a" } catch (e) { e.kind }`, "TypeError"},
+		{`try { 1 % 0 } catch (e) { e.kind }`, "ArithmeticError"},
+		{`try { len(1, 2) } catch (e) { e.kind }`, "ArgumentError"},
+		{`try { val a = 1; a = 2; } catch (e) { e.kind }`, "RuntimeError"},
+		{`try { 1 / 0 } catch (e) { "$e" }`, "ArithmeticError: division by zero: 1 / 0"},
+		{`val inner = func() { throw "x" }; val outer = func() { inner(); 1 }; try { outer() } catch (e) { "${e.stack}" }`, "[inner, outer]"},
+		{`try { func() { 1 / 0 }() } catch (e) { "${e.stack}" }`, "[<anonymous>]"},
+		{`try { try { throw "a" } catch (e) { throw e } } catch (e) { e.message }`, "a"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := evaluated.(*object.String)
+		utils.ValidateValue(ok, true, t)
+		if ok {
+			utils.ValidateValue(str.Value, tt.expected, t)
+		}
+	}
+}
+
+func TestUncaughtError(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedKind    string
+		expectedMessage string
+	}{
+		{`throw "boom"; 5`, "Error", "boom"},
+		{`throw [1, 2]`, "Error", "[1, 2]"},
+		{`try { 1 / 0 } finally { 5 }`, "ArithmeticError", "division by zero: 1 / 0"},
+		{`try { 1 } catch (e) { 2 } finally { throw "final" }`, "Error", "final"},
+		{`try { throw "a" } catch (e) { e.unknown }`, "NameError", "unknown error field: unknown"},
+		{`error(1)`, "TypeError", "error(INTEGER) not supported"},
+		{`error()`, "ArgumentError", "wrong number of arguments: expected 1 to 2, but received 0"},
+		{`error("a", "b", "c")`, "ArgumentError", "wrong number of arguments: expected 1 to 2, but received 3"},
+	}
+
+	for _, tt := range tests {
+		err, ok := testEval(tt.input).(*object.Error)
+		utils.ValidateValue(ok, true, t)
+		if ok {
+			utils.ValidateValue(err.Kind, tt.expectedKind, t)
+			utils.ValidateValue(err.Message, tt.expectedMessage, t)
+		}
+	}
+}
+
+func TestStackTrace(t *testing.T) {
+	input := `val inner = func(x) {
+  x / 0
+}
+val outer = func() {
+  val result = [1, 2] |> len |> inner
+  result
+}
+outer()`
+	err, ok := testEval(input).(*object.Error)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(err.Position, token.Position{Line: 2, Column: 5}, t)
+	utils.ValidateValue(len(err.Stack), 2, t)
+	utils.ValidateValue(err.Stack[0], object.StackFrame{Function: "inner", CallSite: token.Position{Line: 5, Column: 30}}, t)
+	utils.ValidateValue(err.Stack[1], object.StackFrame{Function: "outer", CallSite: token.Position{Line: 8, Column: 6}}, t)
+
+	tests := []struct {
+		input            string
+		expectedPosition token.Position
+		expectedStack    int
+	}{
+		{"val x = 1\nx + y", token.Position{Line: 2, Column: 5}, 0},
+		{"val f = func() { throw 1 }\n\nf()", token.Position{Line: 1, Column: 18}, 1},
+		{"val f = func(g) { g() + 1 }\nf(func() { len(1) })", token.Position{Line: 2, Column: 15}, 2},
+		{"val f = func(g) { g() }\nf(func() { len(1) })", token.Position{Line: 2, Column: 15}, 1},
+		{"val f = func() { try { 1 / 0 } catch (e) { throw e } }\nf()", token.Position{Line: 1, Column: 26}, 1},
+	}
+	for _, tt := range tests {
+		err, ok := testEval(tt.input).(*object.Error)
+		utils.ValidateValue(ok, true, t)
+		utils.ValidateValue(err.Position, tt.expectedPosition, t)
+		utils.ValidateValue(len(err.Stack), tt.expectedStack, t)
+	}
+}
+
+func TestTailCall(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"val sum = func(n, acc) { if (n == 0) { acc } else { sum(n - 1, acc + n) } }; sum(100000, 0)", 5000050000},
+		{"val count = func(n) { if (n == 0) { return 0; }; return count(n - 1); }; count(100000)", 0},
+		{"val loop = func(n) { if (n > 0) { return n |> dec |> loop; }; n }; val dec = func(n) { n - 1 }; loop(50000)", 0},
+		{"val isEven = func(n) { if (n == 0) { true } else { isOdd(n - 1) } }; val isOdd = func(n) { if (n == 0) { false } else { isEven(n - 1) } }; isEven(100001)", false},
+		{"val f = func(n) { if (n == 0) { len([1, 2]) } else { f(n - 1) } }; f(20000)", 2},
+		{"val f = func(n) { try { if (n == 0) { throw 1 } else { f(n - 1) } } catch (e) { e.value + n } }; f(3)", 1},
+	}
+
+	for _, tt := range tests {
+		testObject(t, testEval(tt.input), tt.expected)
+	}
+
+	err, ok := testEval("val f = func(n) { if (n == 0) { 1 } else { f(n - 1, 2) } }; f(3)").(*object.Error)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(err.Message, "wrong number of arguments: expected 1, but received 2", t)
+	utils.ValidateValue(len(err.Stack), 1, t)
+}
+
+func TestStackOverflow(t *testing.T) {
+	input := "val f = func(n) { 1 + f(n + 1) }; f(0)"
+	err, ok := testEval(input).(*object.Error)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(err.Kind, object.STACK_OVERFLOW, t)
+	utils.ValidateValue(err.Message, "stack overflow: maximum call depth of 10000 exceeded", t)
+	utils.ValidateValue(len(err.Stack), environment.DEFAULT_MAX_CALL_DEPTH, t)
+
+	env := environment.NewEnvironment()
+	env.CallStack().SetMaxDepth(50)
+	program := parser.New(lexer.New("val f = func(n) { 1 + f(n + 1) }; try { f(0) } catch (e) { len(e.stack) }")).ParseProgram()
+	testObject(t, Eval(program, env), 50)
+	utils.ValidateValue(env.CallStack().Depth(), 0, t)
+}
+
+func TestEvalContext(t *testing.T) {
+	loop := "val loop = func(n) { loop(n + 1) }; "
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelExpired()
+
+	tests := []struct {
+		ctx          context.Context
+		input        string
+		limits       Limits
+		expectedKind string
+	}{
+		{context.Background(), loop + "loop(0)", Limits{MaxSteps: 1000}, object.STEP_LIMIT_EXCEEDED},
+		{context.Background(), loop + "loop(0)", Limits{MaxAllocations: 1000}, object.ALLOCATION_LIMIT_EXCEEDED},
+		{context.Background(), "val f = func() { [1, 2, 3] }; f(); f(); f()", Limits{MaxAllocations: 10}, object.ALLOCATION_LIMIT_EXCEEDED},
+		{expired, loop + "loop(0)", Limits{}, object.DEADLINE_EXCEEDED},
+		{cancelled, "1 + 2", Limits{}, object.CANCELLED},
+		{context.Background(), loop + "try { loop(0) } catch (e) { 1 }", Limits{MaxSteps: 1000}, object.STEP_LIMIT_EXCEEDED},
+		{context.Background(), loop + "try { loop(0) } finally { 1 }", Limits{MaxSteps: 1000}, object.STEP_LIMIT_EXCEEDED},
+		{context.Background(), `import "arrays" as a; a.range(0, 1000000000000)`, Limits{MaxSteps: 1000}, object.STEP_LIMIT_EXCEEDED},
+		{context.Background(), `import "arrays" as a; a.range(0, 1000000000000)`, Limits{MaxAllocations: 1000}, object.ALLOCATION_LIMIT_EXCEEDED},
+		{expired, `import "arrays" as a; a.range(0, 1000000000000)`, Limits{}, object.DEADLINE_EXCEEDED},
+		{context.Background(), `import "strings" as s; s.repeat("a", 100000)`, Limits{MaxSteps: 1000}, object.STEP_LIMIT_EXCEEDED},
+		{context.Background(), `import "strings" as s; s.padStart("a", 100000)`, Limits{MaxSteps: 1000}, object.STEP_LIMIT_EXCEEDED},
+	}
+
+	for _, tt := range tests {
+		program := parser.New(lexer.New(tt.input)).ParseProgram()
+		env := environment.NewEnvironment()
+		err, ok := EvalContext(tt.ctx, program, env, tt.limits).(*object.Error)
+		utils.ValidateValue(ok, true, t)
+		if ok {
+			utils.ValidateValue(err.Kind, tt.expectedKind, t)
+			utils.ValidateValue(err.Fatal, true, t)
+		}
+		utils.ValidateValue(env.Budget() == nil, true, t)
+	}
+
+	program := parser.New(lexer.New("val f = func(n) { if (n == 0) { 0 } else { f(n - 1) } }; f(10)")).ParseProgram()
+	limits := Limits{MaxSteps: 1000, MaxAllocations: 100}
+	testObject(t, EvalContext(context.Background(), program, environment.NewEnvironment(), limits), 0)
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	writeModules(t, dir, map[string]string{
+		"lib/math.yail": `
+			export val square = func(x) { x * x }
+			export val loaded = []
+			val hidden = 1
+			push(loaded, 1)`,
+		"main.yail": `
+			import "lib/math.yail" as m;
+			import "lib/math.yail" as again;
+			[m.square(4), len(again.loaded)]`,
+		"a.yail":      `import "b.yail" as b; export val a = 1`,
+		"b.yail":      `import "a.yail" as a; export val b = 2`,
+		"broken.yail": "val a = 1\na + \"b\"",
+	})
+	testObject(t, testEvalFile(filepath.Join(dir, "main.yail"), environment.NewEnvironment()), []int64{16, 1})
+
+	tests := []struct {
+		input            string
+		expectedKind     string
+		expectedMessage  string
+		expectedPosition token.Position
+	}{
+		{`import "lib/math.yail" as m; m.hidden`, object.NAME_ERROR, "module %s/lib/math.yail does not export 'hidden'", token.Position{Line: 1, Column: 31}},
+		{`import "missing.yail" as m;`, object.IMPORT_ERROR, "module not found: missing.yail (searched %s/missing.yail)", token.Position{Line: 1, Column: 1}},
+		{`import "a.yail" as a;`, object.IMPORT_ERROR, "import cycle: %[1]s/a.yail -> %[1]s/b.yail -> %[1]s/a.yail", token.Position{Line: 1, Column: 1}},
+		{`import "broken.yail" as b;`, object.IMPORT_ERROR, "%s/broken.yail: TypeError: type mismatch: INTEGER + STRING (line 2, column 3)", token.Position{Line: 1, Column: 1}},
+	}
+	canonical, _ := filepath.EvalSymlinks(dir)
+	for _, tt := range tests {
+		path := filepath.Join(dir, "main.yail")
+		if err := os.WriteFile(path, []byte(tt.input), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		err, ok := testEvalFile(path, environment.NewEnvironment()).(*object.Error)
+		utils.ValidateValue(ok, true, t)
+		if ok {
+			utils.ValidateValue(err.Kind, tt.expectedKind, t)
+			utils.ValidateValue(err.Message, fmt.Sprintf(tt.expectedMessage, canonical), t)
+			utils.ValidateValue(err.Position, tt.expectedPosition, t)
+		}
+	}
+}
+
+func TestImportStandardLibrary(t *testing.T) {
+	testObject(t, testEval(`import "math" as math; import "arrays" as arrays; arrays.range(math.abs(-3))`), []int64{0, 1, 2})
+	testObject(t, testEval(`import "math" as m; import "math" as again; m == again`), true)
+
+	err, ok := testEval(`import "math" as math; math.pi`).(*object.Error)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(err.Message, "module math does not export 'pi'", t)
+}
+
+func TestImportSearchPath(t *testing.T) {
+	library, dir := t.TempDir(), t.TempDir()
+	writeModules(t, library, map[string]string{"util.yail": "export val answer = 42"})
+	writeModules(t, dir, map[string]string{"main.yail": `import "util.yail" as u; u.answer`})
+	t.Setenv(environment.YAIL_PATH, library)
+	testObject(t, testEvalFile(filepath.Join(dir, "main.yail"), environment.NewEnvironment()), 42)
+}
+
+func TestPrograms(t *testing.T) {
+	for _, program := range utils.Programs {
+		if output := utils.Output(testEval(program.Input)); output != program.Expected {
+			t.Errorf("%q: expected %q, but the evaluator returned %q", program.Input, program.Expected, output)
+		}
+	}
+}
+
 func testEval(input string) object.Object {
 	p := parser.New(lexer.New(input))
 	program := p.ParseProgram()
@@ -436,3 +1009,27 @@ func testObject(
 		t.Errorf("Failed to handle %T.", v)
 	}
 }
+
+// testEvalFile evaluates the program in the file, so that its imports are searched next to it.
+func testEvalFile(path string, env *environment.Environment) object.Object {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return object.NewError("%s", err)
+	}
+	program := parser.New(lexer.New(string(source))).ParseProgram()
+	program.File = path
+	return Eval(program, env)
+}
+
+// writeModules writes the files in the directory, creating the directories in their paths.
+func writeModules(t *testing.T, dir string, files map[string]string) {
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package evaluator
 
 import (
+	"strings"
 	"yail/ast"
 	"yail/environment"
 	"yail/object"
@@ -14,6 +15,8 @@ func evalExpression(node ast.Expression, env *environment.Environment) object.Ob
 		return object.NewInteger(node.Value)
 	case *ast.StringLiteralExpression:
 		return object.NewString(node.Value)
+	case *ast.TemplateLiteral:
+		return evalTemplateLiteral(node, env)
 	case *ast.BooleanExpression:
 		return object.GetPooledBooleanObject(node.Value)
 	case *ast.NullExpression:
@@ -24,6 +27,8 @@ func evalExpression(node ast.Expression, env *environment.Environment) object.Ob
 		return evalInfixExpression(node, env)
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
 	case *ast.FunctionLiteral:
 		return environment.NewFunction(node, env)
 	case *ast.CallExpression:
@@ -39,13 +44,42 @@ func evalExpression(node ast.Expression, env *environment.Environment) object.Ob
 }
 
 func evalIdentifier(node *ast.IdentifierExpression, env *environment.Environment) object.Object {
+	if slot := node.Slot; slot != nil {
+		if val, ok := env.GetAt(slot.Depth, slot.Index); ok {
+			return val
+		}
+		return object.NewErrorWithKind(object.NAME_ERROR, "identifier not found: %s", node.Value)
+	}
 	if val, ok := env.Get(node.Value); ok {
 		return val
 	}
-	if builtin, ok := builtinFunctions[node.Value]; ok {
+	return LookupBuiltin(node.Value)
+}
+
+// IsBuiltin reports whether the name refers to a builtin function.
+func IsBuiltin(name string) bool {
+	_, ok := builtinFunctions[name]
+	return ok
+}
+
+// LookupBuiltin returns the builtin function with the given name, or an error if there is none.
+func LookupBuiltin(name string) object.Object {
+	if builtin, ok := builtinFunctions[name]; ok {
 		return builtin
 	}
-	return object.NewError("identifier not found: " + node.Value)
+	return object.NewErrorWithKind(object.NAME_ERROR, "identifier not found: %s", name)
+}
+
+func evalTemplateLiteral(node *ast.TemplateLiteral, env *environment.Environment) object.Object {
+	var out strings.Builder
+	for _, part := range node.Parts {
+		evaluated := Eval(part, env)
+		if isError(evaluated) {
+			return evaluated
+		}
+		out.WriteString(object.ToString(evaluated))
+	}
+	return object.NewString(out.String())
 }
 
 func evalIfExpression(expression *ast.IfExpression, env *environment.Environment) object.Object {
@@ -62,6 +96,32 @@ func evalIfExpression(expression *ast.IfExpression, env *environment.Environment
 	return object.NULL
 }
 
+func evalTryExpression(expression *ast.TryExpression, env *environment.Environment) object.Object {
+	result := Eval(expression.Block, env)
+	if err, ok := result.(*object.Error); ok && err.Fatal {
+		return err
+	}
+	if err, ok := result.(*object.Error); ok && expression.Catch != nil {
+		catchEnv := environment.NewInnerEnvironment(env, expression.NumSlots)
+		catchEnv.DeclareAt(expression.CatchParameter.Slot.Index, expression.CatchParameter.Value, object.NewErrorValue(err), false)
+		result = Eval(expression.Catch, catchEnv)
+		if err, ok := result.(*object.Error); ok && err.Fatal {
+			return err
+		}
+	}
+	if expression.Finally != nil {
+		finalResult := Eval(expression.Finally, env)
+		switch finalResult.(type) {
+		case *object.Error, *object.ReturnValue:
+			return finalResult // overrides the result of the try and catch blocks
+		}
+	}
+	if result == nil {
+		return object.NULL
+	}
+	return result
+}
+
 func evalExpressions(exps []ast.Expression, env *environment.Environment) []object.Object {
 	var result []object.Object
 	for _, e := range exps {
@@ -4,8 +4,11 @@ import (
 	"yail/ast"
 	"yail/environment"
 	"yail/object"
+	"yail/token"
 )
 
+const TAIL_CALL_OBJ = "TAIL_CALL"
+
 func evalFunctionCall(node *ast.CallExpression, env *environment.Environment) object.Object {
 	boundFunctionFromEnv := Eval(node.Function, env)
 	if isError(boundFunctionFromEnv) {
@@ -15,33 +18,148 @@ func evalFunctionCall(node *ast.CallExpression, env *environment.Environment) ob
 	if len(args) == 1 && isError(args[0]) {
 		return args[0] // error object
 	}
-	return applyFunction(boundFunctionFromEnv, args)
+	if function, ok := boundFunctionFromEnv.(*environment.Function); ok && node.IsTailCall {
+		return &tailCall{function: function, args: args, callSite: node.Token.Position}
+	}
+	return applyFunction(env, boundFunctionFromEnv, args, node.Token.Position)
 }
 
-func applyFunction(fn object.Object, args []object.Object) object.Object {
+// Apply calls a function created by the evaluator, for the engines running the functions of imported modules.
+func Apply(function *environment.Function, args []object.Object, callSite token.Position) object.Object {
+	return applyFunction(function.Env, function, args, callSite)
+}
+
+// applyFunction calls the function with the arguments, where callSite is the position of the call in the source code.
+// The functions the evaluator did not create are given to the foreign caller of the environment.
+func applyFunction(env *environment.Environment, fn object.Object, args []object.Object, callSite token.Position) object.Object {
 	switch function := fn.(type) {
 	case *environment.Function:
-		innerEnv := createInnerScopeEnvironment(function, args)
-		evaluated := Eval(function.Body, innerEnv)
-		return unwrapReturnValue(evaluated)
+		return callFunction(function, args, callSite)
+	case *environment.ComposedFunction:
+		intermediate := applyFunction(env, function.First, args, callSite)
+		if isError(intermediate) {
+			return intermediate
+		}
+		return applyFunction(env, function.Second, []object.Object{intermediate}, callSite)
 	case *object.Builtin:
-		return function.Fn(args...)
-	default:
-		return object.NewError("failed to invoke %s as a function", fn.Type())
+		return function.Fn(applier(env, callSite), args...)
+	}
+	if caller := env.ForeignCaller(); caller != nil && fn.Type() == environment.FUNCTION_OBJ {
+		return caller(fn, args, callSite)
+	}
+	return object.NewErrorWithKind(object.TYPE_ERROR, "failed to invoke %s as a function", fn.Type())
+}
+
+// applier returns the applier through which builtin functions and records call the functions they are given,
+// and charge their work to the budget of the environment.
+func applier(env *environment.Environment, callSite token.Position) object.Applier {
+	return &envApplier{env: env, callSite: callSite}
+}
+
+type envApplier struct {
+	env      *environment.Environment
+	callSite token.Position
+}
+
+func (a *envApplier) Apply(fn object.Object, args ...object.Object) object.Object {
+	return applyFunction(a.env, fn, args, a.callSite)
+}
+
+func (a *envApplier) Step() *object.Error {
+	if budget := a.env.Budget(); budget != nil {
+		return budget.Step()
+	}
+	return nil
+}
+
+func (a *envApplier) Allocate() *object.Error {
+	if budget := a.env.Budget(); budget != nil {
+		return budget.Allocate()
+	}
+	return nil
+}
+
+// callFunction evaluates the body of a user-defined function in a new stack frame.
+// Tail calls returned by the body are evaluated in the same frame, so that recursion in tail position
+// does not grow the stack.
+func callFunction(function *environment.Function, args []object.Object, callSite token.Position) object.Object {
+	if len(args) != len(function.Parameters) {
+		return object.NewErrorWithKind(object.ARGUMENT_ERROR, object.INVALID_ARGUMENT_COUNT_MESSAGE, len(function.Parameters), len(args))
+	}
+	callStack := function.Env.CallStack()
+	if callStack.Depth() >= callStack.MaxDepth() {
+		return object.NewErrorWithKind(object.STACK_OVERFLOW, "stack overflow: maximum call depth of %d exceeded", callStack.MaxDepth())
+	}
+	callStack.Push(object.StackFrame{Function: functionName(function), CallSite: callSite})
+	defer callStack.Pop()
+	for {
+		if budget := function.Env.Budget(); budget != nil {
+			if err := budget.Allocate(); err != nil {
+				err.Stack = callStack.Frames()
+				return err
+			}
+		}
+		innerEnv := createInnerScopeEnvironment(function, args)
+		evaluated := unwrapReturnValue(Eval(function.Body, innerEnv))
+		tail, ok := evaluated.(*tailCall)
+		if !ok {
+			if err, ok := evaluated.(*object.Error); ok && err.Stack == nil {
+				err.Stack = callStack.Frames()
+			}
+			return evaluated
+		}
+		if len(tail.args) != len(tail.function.Parameters) {
+			err := object.NewErrorWithKind(object.ARGUMENT_ERROR, object.INVALID_ARGUMENT_COUNT_MESSAGE, len(tail.function.Parameters), len(tail.args))
+			err.Position = tail.callSite
+			err.Stack = callStack.Frames()
+			return err
+		}
+		function, args = tail.function, tail.args
+		callStack.Rename(functionName(function))
 	}
 }
 
 func createInnerScopeEnvironment(fn *environment.Function, args []object.Object) *environment.Environment {
-	env := environment.NewInnerEnvironment(fn.Env)
+	env := environment.NewInnerEnvironment(fn.Env, fn.NumSlots)
 	for paramIdx, param := range fn.Parameters {
-		env.MutableAssign(param.Value, args[paramIdx])
+		if param.Slot != nil {
+			env.DeclareAt(param.Slot.Index, param.Value, args[paramIdx], true)
+		}
 	}
 	return env
 }
 
+func functionName(function *environment.Function) string {
+	if function.Name == "" {
+		return "<anonymous>"
+	}
+	return function.Name
+}
+
 func unwrapReturnValue(obj object.Object) object.Object {
 	if returnValue, ok := obj.(*object.ReturnValue); ok {
 		return returnValue.Unwrap()
 	}
 	return obj
 }
+
+// isCallable reports whether the object is a function, regardless of the engine that created it.
+func isCallable(obj object.Object) bool {
+	return obj.Type() == environment.FUNCTION_OBJ || obj.Type() == object.BUILTIN_OBJ
+}
+
+// tailCall is the result of a call in tail position, which is evaluated by the caller of the enclosing function
+// after its stack frame is released.
+type tailCall struct {
+	function *environment.Function
+	args     []object.Object
+	callSite token.Position
+}
+
+func (tc *tailCall) Type() object.ObjectType {
+	return TAIL_CALL_OBJ
+}
+
+func (tc *tailCall) Inspect() string {
+	return "tail call of " + functionName(tc.function)
+}
@@ -1,6 +1,7 @@
 package evaluator
 
 import (
+	"math"
 	"yail/ast"
 	"yail/environment"
 	"yail/object"
@@ -16,19 +17,28 @@ func evalInfixExpression(node *ast.InfixExpression, env *environment.Environment
 	if isError(right) {
 		return right
 	}
+	return ApplyInfix(node.Token, left, right)
+}
+
+// ApplyInfix applies the operator of an infix expression to evaluated operands.
+func ApplyInfix(operator token.Token, left, right object.Object) object.Object {
 	switch {
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
-		return evalIntegerInfixExpression(node.Token, left, right)
+		return evalIntegerInfixExpression(operator, left, right)
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
-		return evalStringInfixExpression(node.Token, left, right)
-	case node.Token.Type == token.EQUAL:
+		return evalStringInfixExpression(operator, left, right)
+	case isCallable(left) && isCallable(right):
+		return evalFunctionInfixExpression(operator, left, right)
+	case left.Type() == object.TUPLE_OBJ && right.Type() == object.TUPLE_OBJ:
+		return evalTupleInfixExpression(operator, left, right)
+	case operator.Type == token.EQUAL:
 		return object.GetPooledBooleanObject(left == right)
-	case node.Token.Type == token.NOT_EQUAL:
+	case operator.Type == token.NOT_EQUAL:
 		return object.GetPooledBooleanObject(left != right)
 	case left.Type() != right.Type():
-		return object.NewError("type mismatch: %s %s %s", left.Type(), node.Operator, right.Type())
+		return object.NewErrorWithKind(object.TYPE_ERROR, "type mismatch: %s %s %s", left.Type(), operator.Literal, right.Type())
 	default:
-		return object.NewError("unknown operator: %s %s %s", left.Type(), node.Operator, right.Type())
+		return object.NewErrorWithKind(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), operator.Literal, right.Type())
 	}
 }
 
@@ -37,15 +47,51 @@ func evalIntegerInfixExpression(infixToken token.Token, left, right object.Objec
 	rightVal := right.(*object.Integer).Value
 	switch infixToken.Literal {
 	case token.PLUS:
-		return object.NewInteger(leftVal + rightVal)
+		if result, ok := addExact(leftVal, rightVal); ok {
+			return object.NewInteger(result)
+		}
+		return overflowError(infixToken, leftVal, rightVal)
 	case token.MINUS:
-		return object.NewInteger(leftVal - rightVal)
+		if result, ok := subtractExact(leftVal, rightVal); ok {
+			return object.NewInteger(result)
+		}
+		return overflowError(infixToken, leftVal, rightVal)
 	case token.MULTIPLY:
-		return object.NewInteger(leftVal * rightVal)
+		if result, ok := multiplyExact(leftVal, rightVal); ok {
+			return object.NewInteger(result)
+		}
+		return overflowError(infixToken, leftVal, rightVal)
 	case token.DIVIDE:
+		if rightVal == 0 {
+			return object.NewErrorWithKind(object.ARITHMETIC_ERROR, "division by zero: %d / %d", leftVal, rightVal)
+		}
+		if leftVal == math.MinInt64 && rightVal == -1 {
+			return overflowError(infixToken, leftVal, rightVal)
+		}
 		return object.NewInteger(leftVal / rightVal)
 	case token.MODULO:
+		if rightVal == 0 {
+			return object.NewErrorWithKind(object.ARITHMETIC_ERROR, "division by zero: %d %% %d", leftVal, rightVal)
+		}
 		return object.NewInteger(leftVal % rightVal)
+	case token.POWER:
+		return evalPowerExpression(leftVal, rightVal)
+	case token.BIT_AND:
+		return object.NewInteger(leftVal & rightVal)
+	case token.BIT_OR:
+		return object.NewInteger(leftVal | rightVal)
+	case token.BIT_XOR:
+		return object.NewInteger(leftVal ^ rightVal)
+	case token.SHIFT_LEFT:
+		if err := checkShiftCount(infixToken, leftVal, rightVal); err != nil {
+			return err
+		}
+		return object.NewInteger(leftVal << rightVal)
+	case token.SHIFT_RIGHT:
+		if err := checkShiftCount(infixToken, leftVal, rightVal); err != nil {
+			return err
+		}
+		return object.NewInteger(leftVal >> rightVal)
 	case token.LESS_THAN:
 		return object.GetPooledBooleanObject(leftVal < rightVal)
 	case token.GREATER_THAN:
@@ -59,8 +105,81 @@ func evalIntegerInfixExpression(infixToken token.Token, left, right object.Objec
 	case token.GREATER_OR_EQUAL:
 		return object.GetPooledBooleanObject(leftVal >= rightVal)
 	default:
-		return object.NewError("unknown operator: %s %s %s", left.Type(), infixToken.Literal, right.Type())
+		return object.NewErrorWithKind(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), infixToken.Literal, right.Type())
+	}
+}
+
+func overflowError(operator token.Token, left, right int64) *object.Error {
+	return object.NewErrorWithKind(object.ARITHMETIC_ERROR, "integer overflow: %d %s %d", left, operator.Literal, right)
+}
+
+// checkShiftCount reports shift counts outside 0 to 63, which would shift every bit out of an int64.
+func checkShiftCount(operator token.Token, left, count int64) *object.Error {
+	if count < 0 {
+		return object.NewErrorWithKind(object.ARITHMETIC_ERROR, "negative shift count: %d %s %d", left, operator.Literal, count)
+	}
+	if count >= 64 {
+		return object.NewErrorWithKind(object.ARITHMETIC_ERROR, "shift count too large: %d %s %d", left, operator.Literal, count)
+	}
+	return nil
+}
+
+func evalPowerExpression(base, exponent int64) object.Object {
+	if exponent < 0 {
+		return object.NewErrorWithKind(object.ARITHMETIC_ERROR, "negative exponent: %d ** %d", base, exponent)
+	}
+	result, ok := power(base, exponent)
+	if !ok {
+		return object.NewErrorWithKind(object.ARITHMETIC_ERROR, "integer overflow: %d ** %d", base, exponent)
+	}
+	return object.NewInteger(result)
+}
+
+// power computes base ** exponent by repeated squaring, reporting false when the result does not fit in an int64.
+func power(base, exponent int64) (int64, bool) {
+	result := int64(1)
+	for exponent > 0 {
+		var ok bool
+		if exponent&1 == 1 {
+			if result, ok = multiplyExact(result, base); !ok {
+				return 0, false
+			}
+		}
+		exponent >>= 1
+		if exponent > 0 {
+			if base, ok = multiplyExact(base, base); !ok {
+				return 0, false
+			}
+		}
+	}
+	return result, true
+}
+
+func addExact(a, b int64) (int64, bool) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, false
+	}
+	return sum, true
+}
+
+func subtractExact(a, b int64) (int64, bool) {
+	difference := a - b
+	if (b > 0 && difference > a) || (b < 0 && difference < a) {
+		return 0, false
+	}
+	return difference, true
+}
+
+func multiplyExact(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
 	}
+	return product, true
 }
 
 func evalStringInfixExpression(infixToken token.Token, left, right object.Object) object.Object {
@@ -74,6 +193,80 @@ func evalStringInfixExpression(infixToken token.Token, left, right object.Object
 	case token.NOT_EQUAL:
 		return object.GetPooledBooleanObject(left != right)
 	default:
-		return object.NewError("unknown operator: %s %s %s", left.Type(), infixToken.Literal, right.Type())
+		return object.NewErrorWithKind(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), infixToken.Literal, right.Type())
+	}
+}
+
+// evalTupleInfixExpression compares tuples by their elements, like the keys of hash maps, except for records,
+// which are compared by identity since their equals function can not be called here.
+func evalTupleInfixExpression(infixToken token.Token, left, right object.Object) object.Object {
+	switch infixToken.Literal {
+	case token.EQUAL:
+		return object.GetPooledBooleanObject(keysEqual(left, right))
+	case token.NOT_EQUAL:
+		return object.GetPooledBooleanObject(!keysEqual(left, right))
+	default:
+		return object.NewErrorWithKind(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), infixToken.Literal, right.Type())
+	}
+}
+
+// keysEqual compares values as keys of hash maps, where tuples and arrays are equal when their elements are, and the
+// values that can not be keys are only equal to themselves.
+func keysEqual(left, right object.Object) bool {
+	return compareKeys(left, right, map[[2]object.Object]bool{})
+}
+
+// compareKeys compares values like keysEqual, where comparing holds the tuples and arrays being compared, which are
+// taken as equal when they are met again inside themselves.
+func compareKeys(left, right object.Object, comparing map[[2]object.Object]bool) bool {
+	leftElements, leftIsList := listElements(left)
+	rightElements, rightIsList := listElements(right)
+	if leftIsList || rightIsList {
+		if !leftIsList || !rightIsList || len(leftElements) != len(rightElements) {
+			return false
+		}
+		pair := [2]object.Object{left, right}
+		if comparing[pair] {
+			return true
+		}
+		comparing[pair] = true
+		defer delete(comparing, pair)
+		for i := range leftElements {
+			if !compareKeys(leftElements[i], rightElements[i], comparing) {
+				return false
+			}
+		}
+		return true
+	}
+	leftKey, leftIsKey := left.(object.Hashable)
+	rightKey, rightIsKey := right.(object.Hashable)
+	if leftIsKey && rightIsKey {
+		return leftKey.Equals(rightKey)
+	}
+	return left == right
+}
+
+func listElements(value object.Object) ([]object.Object, bool) {
+	switch value := value.(type) {
+	case *object.Tuple:
+		return value.Elements, true
+	case *object.Array:
+		return value.Elements, true
+	}
+	return nil, false
+}
+
+func evalFunctionInfixExpression(infixToken token.Token, left, right object.Object) object.Object {
+	switch infixToken.Literal {
+	case token.SHIFT_RIGHT:
+		return environment.NewComposedFunction(left, right)
+	case token.SHIFT_LEFT:
+		return environment.NewComposedFunction(right, left)
+	case token.EQUAL:
+		return object.GetPooledBooleanObject(left == right)
+	case token.NOT_EQUAL:
+		return object.GetPooledBooleanObject(left != right)
+	default:
+		return object.NewErrorWithKind(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), infixToken.Literal, right.Type())
 	}
 }
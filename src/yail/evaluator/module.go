@@ -0,0 +1,172 @@
+package evaluator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"yail/ast"
+	"yail/environment"
+	"yail/lexer"
+	"yail/object"
+	"yail/parser"
+	"yail/stdlib"
+	"yail/token"
+)
+
+func evalImport(node *ast.ImportStatement, env *environment.Environment) object.Object {
+	module := Import(env, env.File(), node.Path)
+	if isError(module) {
+		return module
+	}
+	var ok bool
+	var err *object.Error
+	if slot := node.Alias.Slot; slot != nil {
+		ok, err = env.DeclareAt(slot.Index, node.Alias.Value, module, false)
+	} else {
+		ok, err = env.ImmutableAssign(node.Alias.Value, module)
+	}
+	if !ok {
+		return err
+	}
+	return nil
+}
+
+// Import returns the module of the standard library with the given name, or the module read from the file at the
+// given path, evaluating it in its own environment the first time a program sharing the state of the importer
+// imports it. A relative path is searched in the directory of the importing file, which is the current directory
+// when from is empty, and then in the directories of the search path.
+func Import(importer *environment.Environment, from, path string) object.Object {
+	modules := importer.Modules()
+	if library, ok := stdlib.Lookup(path); ok {
+		return importLibrary(importer, library)
+	}
+	file, err := findModule(path, from, modules.SearchPath)
+	if err != nil {
+		return err
+	}
+	if module, ok := modules.Loaded(file); ok {
+		return module
+	}
+	end, chain := modules.Begin(file)
+	if chain != nil {
+		for i, link := range chain {
+			chain[i] = displayPath(link)
+		}
+		return object.NewErrorWithKind(object.IMPORT_ERROR, "import cycle: %s", strings.Join(chain, " -> "))
+	}
+	module, result := loadModule(importer, file)
+	end(module)
+	return result
+}
+
+// importLibrary returns the module of the standard library, whose functions are bound in an environment of their own.
+func importLibrary(importer *environment.Environment, library *stdlib.Module) *environment.Module {
+	modules := importer.Modules()
+	if module, ok := modules.Loaded(library.Name); ok {
+		return module
+	}
+	env := environment.NewModuleEnvironment(importer, "")
+	var names []string
+	for _, function := range library.Functions {
+		env.ImmutableAssign(function.Name, library.Builtin(function))
+		names = append(names, function.Name)
+	}
+	module := environment.NewModule(library.Name, env, names)
+	end, _ := modules.Begin(library.Name) // the module imports nothing, so there is no cycle
+	end(module)
+	return module
+}
+
+// findModule returns the canonical path of the first file found for the imported path.
+func findModule(path, from string, searchPath []string) (string, *object.Error) {
+	var candidates []string
+	if filepath.IsAbs(path) {
+		candidates = append(candidates, path)
+	} else {
+		directory := "."
+		if from != "" {
+			directory = filepath.Dir(from)
+		}
+		candidates = append(candidates, filepath.Join(directory, path))
+		for _, directory := range searchPath {
+			candidates = append(candidates, filepath.Join(directory, path))
+		}
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return canonicalPath(candidate)
+		}
+	}
+	return "", object.NewErrorWithKind(object.IMPORT_ERROR, "module not found: %s (searched %s)", path, strings.Join(candidates, ", "))
+}
+
+func canonicalPath(path string) (string, *object.Error) {
+	absolute, err := filepath.Abs(path)
+	if err == nil {
+		absolute, err = filepath.EvalSymlinks(absolute)
+	}
+	if err != nil {
+		return "", object.NewErrorWithKind(object.IMPORT_ERROR, "failed to resolve %s: %s", path, err)
+	}
+	return absolute, nil
+}
+
+// displayPath shortens the canonical path of a module to a path relative to the current directory, when it is in it.
+func displayPath(path string) string {
+	directory, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	relative, err := filepath.Rel(directory, path)
+	if err != nil || strings.HasPrefix(relative, "..") {
+		return path
+	}
+	return relative
+}
+
+// loadModule evaluates the file in a new environment. It returns the module, or nil and the error that stopped it.
+func loadModule(importer *environment.Environment, file string) (*environment.Module, object.Object) {
+	source, err := os.ReadFile(file)
+	if err != nil {
+		return nil, object.NewErrorWithKind(object.IMPORT_ERROR, "failed to read %s: %s", displayPath(file), err)
+	}
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+	if errors := p.Errors(); len(errors) > 0 {
+		return nil, object.NewErrorWithKind(object.IMPORT_ERROR, "%s: %s", displayPath(file), errors[0])
+	}
+	program.File = file
+	env := environment.NewModuleEnvironment(importer, file)
+	if result, ok := evalProgram(program, env).(*object.Error); ok {
+		if result.Fatal {
+			return nil, result
+		}
+		if result.Kind == object.IMPORT_ERROR {
+			// raised by an import of the module, whose message already names the file at fault
+			result.Position = token.Position{}
+			return nil, result
+		}
+		return nil, object.NewErrorWithKind(object.IMPORT_ERROR, "%s: %s: %s (%s)", displayPath(file), result.Kind, result.Message, result.Position)
+	}
+	module := environment.NewModule(file, env, exportedNames(program))
+	return module, module
+}
+
+func exportedNames(program *ast.Program) []string {
+	var names []string
+	for _, statement := range program.Statements {
+		if binding, ok := statement.(*ast.VariableBindingStatement); ok && binding.Exported {
+			names = append(names, binding.Name.Value)
+		}
+	}
+	return names
+}
+
+func evalModuleFieldAccessExpression(module, index object.Object) object.Object {
+	name := index.(*object.String).Value
+	value, ok := module.(*environment.Module).Get(name)
+	if !ok {
+		return object.NewErrorWithKind(object.NAME_ERROR, "module %s does not export '%s'", displayPath(module.(*environment.Module).Path), name)
+	}
+	return value
+}
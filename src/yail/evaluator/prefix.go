@@ -1,6 +1,7 @@
 package evaluator
 
 import (
+	"math"
 	"yail/ast"
 	"yail/environment"
 	"yail/object"
@@ -12,13 +13,20 @@ func evalPrefixExpression(node *ast.PrefixExpression, env *environment.Environme
 	if isError(right) {
 		return right
 	}
-	switch node.Token.Literal {
+	return ApplyPrefix(node.Token, right)
+}
+
+// ApplyPrefix applies the operator of a prefix expression to an evaluated operand.
+func ApplyPrefix(operator token.Token, right object.Object) object.Object {
+	switch operator.Literal {
 	case token.NOT:
 		return evalNotOperatorExpression(right)
 	case token.MINUS:
 		return evalNegativePrefixOperatorExpression(right)
+	case token.BIT_NOT:
+		return evalBitwiseNotPrefixOperatorExpression(right)
 	default:
-		return object.NewError("unknown operator: %s%s", node.Operator, right.Type())
+		return object.NewErrorWithKind(object.TYPE_ERROR, "unknown operator: %s%s", operator.Literal, right.Type())
 	}
 }
 
@@ -29,14 +37,25 @@ func evalNotOperatorExpression(right object.Object) object.Object {
 	case object.FALSE:
 		return object.TRUE
 	default:
-		return object.NewError("unknown operator: !%s", right.Type())
+		return object.NewErrorWithKind(object.TYPE_ERROR, "unknown operator: !%s", right.Type())
 	}
 }
 
 func evalNegativePrefixOperatorExpression(right object.Object) object.Object {
 	if right.Type() != object.INTEGER_OBJ {
-		return object.NewError("unknown operator: -%s", right.Type())
+		return object.NewErrorWithKind(object.TYPE_ERROR, "unknown operator: -%s", right.Type())
 	}
 	value := right.(*object.Integer).Value
+	if value == math.MinInt64 {
+		return object.NewErrorWithKind(object.ARITHMETIC_ERROR, "integer overflow: -(%d)", value)
+	}
 	return object.NewInteger(-value)
 }
+
+func evalBitwiseNotPrefixOperatorExpression(right object.Object) object.Object {
+	if right.Type() != object.INTEGER_OBJ {
+		return object.NewErrorWithKind(object.TYPE_ERROR, "unknown operator: ~%s", right.Type())
+	}
+	value := right.(*object.Integer).Value
+	return object.NewInteger(^value)
+}
@@ -19,6 +19,10 @@ func evalStatement(node ast.Statement, env *environment.Environment) object.Obje
 		return evalReturnStatement(node, env)
 	case *ast.BlockStatement:
 		return evalBlockStatement(node, env)
+	case *ast.ThrowStatement:
+		return evalThrowStatement(node, env)
+	case *ast.ImportStatement:
+		return evalImport(node, env)
 	}
 	return nil
 }
@@ -28,6 +32,9 @@ func evalVariableBinding(node *ast.VariableBindingStatement, env *environment.En
 	if isError(val) {
 		return val
 	}
+	if function, ok := val.(*environment.Function); ok && function.Name == "" {
+		function.Name = node.Name.Value
+	}
 	ok, err := assignNewVariable(node, env, val)
 	if !ok {
 		return err
@@ -36,6 +43,9 @@ func evalVariableBinding(node *ast.VariableBindingStatement, env *environment.En
 }
 
 func assignNewVariable(node *ast.VariableBindingStatement, env *environment.Environment, val object.Object) (bool, *object.Error) {
+	if slot := node.Name.Slot; slot != nil {
+		return env.DeclareAt(slot.Index, node.Name.Value, val, node.Token.Type == token.VAR)
+	}
 	switch node.Token.Type {
 	case token.VAL:
 		return env.ImmutableAssign(node.Name.Value, val)
@@ -51,7 +61,13 @@ func evalReassignment(node *ast.ReassignmentStatement, env *environment.Environm
 	if isError(val) {
 		return val
 	}
-	ok, err := env.Reassign(node.Name.Value, val)
+	var ok bool
+	var err *object.Error
+	if slot := node.Name.Slot; slot != nil {
+		ok, err = env.ReassignAt(slot.Depth, slot.Index, node.Name.Value, val)
+	} else {
+		ok, err = env.Reassign(node.Name.Value, val)
+	}
 	if !ok {
 		return err
 	}
@@ -66,6 +82,17 @@ func evalReturnStatement(node *ast.ReturnStatement, env *environment.Environment
 	return object.NewReturnValue(val)
 }
 
+func evalThrowStatement(node *ast.ThrowStatement, env *environment.Environment) object.Object {
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+	if errorValue, ok := val.(*object.ErrorValue); ok {
+		return errorValue.Rethrow()
+	}
+	return object.NewThrownError(val)
+}
+
 func evalBlockStatement(block *ast.BlockStatement, env *environment.Environment) object.Object {
 	var result object.Object
 	for _, statement := range block.Statements {
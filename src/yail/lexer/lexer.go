@@ -1,10 +1,24 @@
 package lexer
 
-import "yail/token"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"yail/token"
+)
 
 const (
 	EOF_CHAR         = 0
 	STRING_DELIMITER = '"'
+	ESCAPE_CHAR      = '\\'
+	TEMPLATE_CHAR    = '$'
+
+	ESCAPED_TEMPLATE_CHAR = `\$`
+
+	RAW_STRING_DELIMITER = `"""`
+	COMMENT_PREFIX       = "//"
 )
 
 type Lexer struct {
@@ -12,16 +26,37 @@ type Lexer struct {
 	curPosition  int
 	nextPosition int
 	curChar      byte
+	line         int
+	column       int
+
+	// tokenStart is the position of the token being read.
+	tokenStart token.Position
+	errors     []*Error
+	comments   []token.Comment
+}
+
+// Error describes a token that could not be read, such as a malformed number.
+type Error struct {
+	Position token.Position
+	Message  string
 }
 
 func New(sourceCode string) *Lexer {
-	lexer := &Lexer{sourceCode: sourceCode}
+	lexer := &Lexer{sourceCode: sourceCode, line: 1}
 	lexer.readNextChar()
 	return lexer
 }
 
 func (lexer *Lexer) NextToken() token.Token {
-	lexer.eatWhitespace()
+	newlineBefore := lexer.eatWhitespace()
+	lexer.tokenStart = token.Position{Line: lexer.line, Column: lexer.column}
+	tok := lexer.readToken()
+	tok.Position = lexer.tokenStart
+	tok.NewlineBefore = newlineBefore
+	return tok
+}
+
+func (lexer *Lexer) readToken() token.Token {
 	if lexer.curChar == EOF_CHAR {
 		return token.EOF_TOKEN
 	}
@@ -29,7 +64,10 @@ func (lexer *Lexer) NextToken() token.Token {
 		return token.NewKeywordOrIdentifier(lexer.readConsecutiveLetters())
 	}
 	if IsDigit(lexer.curChar) {
-		return token.NewInteger(lexer.readNumber())
+		return lexer.readNumber()
+	}
+	if strings.HasPrefix(lexer.sourceCode[lexer.curPosition:], RAW_STRING_DELIMITER) {
+		return lexer.readRawString()
 	}
 	if lexer.curChar == STRING_DELIMITER {
 		return lexer.readString()
@@ -64,6 +102,14 @@ func (lexer *Lexer) getTwoCharacterToken() (token.Token, bool) {
 }
 
 func (lexer *Lexer) readNextChar() {
+	if lexer.curChar == EOF_CHAR && lexer.curPosition >= len(lexer.sourceCode) {
+		return // already at the end of the source code
+	}
+	if lexer.curChar == '\n' {
+		lexer.line += 1
+		lexer.column = 0
+	}
+	lexer.column += 1
 	if lexer.nextPosition >= len(lexer.sourceCode) {
 		lexer.curChar = EOF_CHAR
 	} else {
@@ -73,10 +119,39 @@ func (lexer *Lexer) readNextChar() {
 	lexer.nextPosition += 1
 }
 
-func (lexer *Lexer) eatWhitespace() {
-	for lexer.curChar == ' ' || lexer.curChar == '\t' || lexer.curChar == '\n' || lexer.curChar == '\r' {
+// eatWhitespace skips the whitespace and the comments before the next token,
+// and reports whether they include a line break.
+func (lexer *Lexer) eatWhitespace() bool {
+	newline := false
+	for {
+		switch {
+		case lexer.curChar == ' ' || lexer.curChar == '\t' || lexer.curChar == '\r':
+			lexer.readNextChar()
+		case lexer.curChar == '\n':
+			newline = true
+			lexer.readNextChar()
+		case strings.HasPrefix(lexer.sourceCode[lexer.curPosition:], COMMENT_PREFIX):
+			lexer.readComment()
+		default:
+			return newline
+		}
+	}
+}
+
+// readComment reads a comment up to the end of the line, which is left to be read as whitespace.
+func (lexer *Lexer) readComment() {
+	position := token.Position{Line: lexer.line, Column: lexer.column}
+	start := lexer.curPosition
+	for lexer.curChar != '\n' && lexer.curChar != EOF_CHAR {
 		lexer.readNextChar()
 	}
+	text := strings.TrimSpace(strings.TrimPrefix(lexer.sourceCode[start:lexer.curPosition], COMMENT_PREFIX))
+	lexer.comments = append(lexer.comments, token.Comment{Position: position, Text: text})
+}
+
+// Comments returns the comments skipped so far.
+func (lexer *Lexer) Comments() []token.Comment {
+	return lexer.comments
 }
 
 func (lexer *Lexer) readConsecutiveLetters() string {
@@ -87,22 +162,130 @@ func (lexer *Lexer) readConsecutiveLetters() string {
 	return lexer.sourceCode[curPosition:lexer.curPosition]
 }
 
-func (lexer *Lexer) readNumber() string {
+// readNumber reads an integer literal, which may have a 0x, 0o or 0b prefix and use underscores
+// between digits. A malformed or too large literal is read as an illegal token.
+func (lexer *Lexer) readNumber() token.Token {
 	curPosition := lexer.curPosition
-	for IsDigit(lexer.curChar) {
+	for IsLetter(lexer.curChar) || IsDigit(lexer.curChar) {
 		lexer.readNextChar()
 	}
-	return lexer.sourceCode[curPosition:lexer.curPosition]
+	literal := lexer.sourceCode[curPosition:lexer.curPosition]
+	_, err := strconv.ParseInt(literal, 0, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		lexer.addError("integer literal is too large: %s (the maximum is %d)", literal, int64(math.MaxInt64))
+		return token.NewIllegal(literal)
+	}
+	if err != nil {
+		lexer.addError("malformed integer literal: %s", literal)
+		return token.NewIllegal(literal)
+	}
+	return token.NewInteger(literal)
+}
+
+func (lexer *Lexer) addError(format string, a ...interface{}) {
+	lexer.errors = append(lexer.errors, &Error{Position: lexer.tokenStart, Message: fmt.Sprintf(format, a...)})
+}
+
+// Errors returns the problems found in the tokens read so far.
+func (lexer *Lexer) Errors() []*Error {
+	return lexer.errors
+}
+
+// ErrorAt returns the problem found in the token starting at the given position, if any.
+func (lexer *Lexer) ErrorAt(position token.Position) (*Error, bool) {
+	for _, err := range lexer.errors {
+		if err.Position == position {
+			return err, true
+		}
+	}
+	return nil, false
 }
 
 func (lexer *Lexer) readString() token.Token {
 	startPosition := lexer.curPosition + 1
+	isTemplate := lexer.skipStringContent()
+	content := lexer.sourceCode[startPosition:lexer.curPosition]
+	if lexer.curChar == EOF_CHAR {
+		lexer.addError("unterminated string")
+		return token.NewIllegal(lexer.sourceCode[startPosition-1:])
+	}
+	lexer.readNextChar()
+	if isTemplate {
+		return token.NewTemplate(content)
+	}
+	return token.NewString(Unescape(content))
+}
+
+// readRawString reads a string wrapped by triple quotes, which may span multiple lines
+// and contain quotes. Escape sequences and embedded expressions are not processed,
+// and the common indentation of the lines is removed.
+func (lexer *Lexer) readRawString() token.Token {
+	for i := 0; i < len(RAW_STRING_DELIMITER); i++ {
+		lexer.readNextChar()
+	}
+	startPosition := lexer.curPosition
+	for lexer.curChar != EOF_CHAR && !strings.HasPrefix(lexer.sourceCode[lexer.curPosition:], RAW_STRING_DELIMITER) {
+		lexer.readNextChar()
+	}
+	content := lexer.sourceCode[startPosition:lexer.curPosition]
+	if lexer.curChar == EOF_CHAR {
+		lexer.addError("unterminated raw string")
+		return token.NewIllegal(RAW_STRING_DELIMITER + content)
+	}
+	for i := 0; i < len(RAW_STRING_DELIMITER); i++ {
+		lexer.readNextChar()
+	}
+	return token.NewString(TrimIndent(content))
+}
+
+// skipStringContent moves to the delimiter closing the string that starts at the current character,
+// and reports whether the string embeds any expression.
+func (lexer *Lexer) skipStringContent() bool {
+	isTemplate := false
 	for {
 		lexer.readNextChar()
-		if lexer.curChar == STRING_DELIMITER || lexer.curChar == EOF_CHAR {
+		switch {
+		case lexer.curChar == EOF_CHAR || lexer.curChar == STRING_DELIMITER:
+			return isTemplate
+		case lexer.isEscaped():
 			lexer.readNextChar()
-			break
+		case lexer.curChar == TEMPLATE_CHAR && lexer.peekChar() == '{':
+			isTemplate = true
+			lexer.readNextChar()
+			lexer.skipEmbeddedExpression()
+		case lexer.curChar == TEMPLATE_CHAR && IsLetter(lexer.peekChar()):
+			isTemplate = true
 		}
 	}
-	return token.NewString(lexer.sourceCode[startPosition : lexer.curPosition-1])
+}
+
+// skipEmbeddedExpression moves to the brace closing an embedded expression, skipping nested braces and strings.
+func (lexer *Lexer) skipEmbeddedExpression() {
+	depth := 1
+	for {
+		lexer.readNextChar()
+		switch lexer.curChar {
+		case EOF_CHAR:
+			return
+		case '{':
+			depth += 1
+		case '}':
+			depth -= 1
+			if depth == 0 {
+				return
+			}
+		case STRING_DELIMITER:
+			lexer.skipStringContent()
+			if lexer.curChar == EOF_CHAR {
+				return
+			}
+		}
+	}
+}
+
+func (lexer *Lexer) peekChar() byte {
+	if lexer.nextPosition >= len(lexer.sourceCode) {
+		return EOF_CHAR
+	}
+	return lexer.sourceCode[lexer.nextPosition]
 }
@@ -42,6 +42,113 @@ func TestLiteral(t *testing.T) {
 	}
 }
 
+func TestStringToken(t *testing.T) {
+	input := `"a\b\\c\nd\$e"; "C:\"; "price: $10"; "Hi $name!"; "${ {"a": "}"}["a"] } ok"; "\${x}";`
+	lexer := New(input)
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.STRING, `a\b\\c\nd$e`},
+		{token.SEMICOLON, ";"},
+		{token.STRING, `C:\`},
+		{token.SEMICOLON, ";"},
+		{token.STRING, "price: $10"},
+		{token.SEMICOLON, ";"},
+		{token.TEMPLATE, "Hi $name!"},
+		{token.SEMICOLON, ";"},
+		{token.TEMPLATE, `${ {"a": "}"}["a"] } ok`},
+		{token.SEMICOLON, ";"},
+		{token.STRING, "${x}"},
+		{token.SEMICOLON, ";"},
+
+		{token.EOF, ""},
+	}
+
+	for _, tt := range tests {
+		tok := lexer.NextToken()
+		utils.ValidateValue(tok.Type, tt.expectedType, t)
+		utils.ValidateValue(tok.Literal, tt.expectedLiteral, t)
+	}
+}
+
+func TestRawStringToken(t *testing.T) {
+	input := "\"\"\"a\\n\"b\" $c\"\"\"; \"\"\"\n    line 1\n      line 2\n    \"\"\"\nx"
+	lexer := New(input)
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedLine    int
+	}{
+		{token.STRING, `a\n"b" $c`, 1},
+		{token.SEMICOLON, ";", 1},
+		{token.STRING, "line 1\n  line 2", 1},
+		{token.IDENTIFIER, "x", 5},
+		{token.EOF, "", 5},
+	}
+
+	for _, tt := range tests {
+		tok := lexer.NextToken()
+		utils.ValidateValue(tok.Type, tt.expectedType, t)
+		utils.ValidateValue(tok.Literal, tt.expectedLiteral, t)
+		utils.ValidateValue(tok.Position.Line, tt.expectedLine, t)
+	}
+}
+
+func TestTrimIndent(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"abc", "abc"},
+		{"  abc  ", "abc  "},
+		{"\n  a\n    b\n  ", "a\n  b"},
+		{"\n\ta\n\n\tb\n", "a\n\nb"},
+		{"first\n  second", "first\n  second"},
+		{"\n", ""},
+	}
+
+	for _, tt := range tests {
+		utils.ValidateValue(TrimIndent(tt.input), tt.expected, t)
+	}
+}
+
+func TestSplitTemplate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []TemplatePart
+	}{
+		{
+			"Hello ${user.name}, you have $count items",
+			[]TemplatePart{
+				{Text: "Hello ", Offset: 0},
+				{Expression: "user.name", IsExpression: true, Offset: 8},
+				{Text: ", you have ", Offset: 18},
+				{Expression: "count", IsExpression: true, Offset: 30},
+				{Text: " items", Offset: 35},
+			},
+		},
+		{
+			`${a}${"}"}\$b $`,
+			[]TemplatePart{
+				{Expression: "a", IsExpression: true, Offset: 2},
+				{Expression: `"}"`, IsExpression: true, Offset: 6},
+				{Text: "$b $", Offset: 10},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		parts := SplitTemplate(tt.input)
+		utils.ValidateValue(len(parts), len(tt.expected), t)
+		for i, part := range parts {
+			utils.ValidateValue(part, tt.expected[i], t)
+		}
+	}
+}
+
 func TestVariableBinding(t *testing.T) {
 	input := `var five = 5;
     	      val a = b;
@@ -163,7 +270,7 @@ func TestFunctionExpression(t *testing.T) {
 }
 
 func TestSingleCharacterToken(t *testing.T) {
-	input := `[!true, 1 + (2 - 3) * 10 / 2 % 3, -10 < 5, {1: 5 > 10}];`
+	input := `[!true, 1 + (2 - 3) * 10 / 2 % 3, -10 < 5, {1: x.y > 10, 5 > 10}];`
 	lexer := New(input)
 
 	tests := []struct {
@@ -199,6 +306,12 @@ func TestSingleCharacterToken(t *testing.T) {
 		{token.LEFT_BRACE, "{"},
 		{token.INTEGER, "1"},
 		{token.COLON, ":"},
+		{token.IDENTIFIER, "x"},
+		{token.DOT, "."},
+		{token.IDENTIFIER, "y"},
+		{token.GREATER_THAN, ">"},
+		{token.INTEGER, "10"},
+		{token.COMMA, ","},
 		{token.INTEGER, "5"},
 		{token.GREATER_THAN, ">"},
 		{token.INTEGER, "10"},
@@ -216,11 +329,43 @@ func TestSingleCharacterToken(t *testing.T) {
 	}
 }
 
+func TestBitwiseToken(t *testing.T) {
+	input := `~a & b | c ^ d;`
+	lexer := New(input)
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.BIT_NOT, "~"},
+		{token.IDENTIFIER, "a"},
+		{token.BIT_AND, "&"},
+		{token.IDENTIFIER, "b"},
+		{token.BIT_OR, "|"},
+		{token.IDENTIFIER, "c"},
+		{token.BIT_XOR, "^"},
+		{token.IDENTIFIER, "d"},
+		{token.SEMICOLON, ";"},
+
+		{token.EOF, ""},
+	}
+
+	for _, tt := range tests {
+		tok := lexer.NextToken()
+		utils.ValidateValue(tok.Type, tt.expectedType, t)
+		utils.ValidateValue(tok.Literal, tt.expectedLiteral, t)
+	}
+}
+
 func TestTwoCharacterToken(t *testing.T) {
 	input := `5 == 3;
               5 != 3;
 			  5 <= 3;
-			  5 >= 3;`
+			  5 >= 3;
+			  2 ** 3;
+			  1 << 2 >> 3;
+			  x |> f;
+			  f >> g << h;`
 	lexer := New(input)
 
 	tests := []struct {
@@ -247,6 +392,30 @@ func TestTwoCharacterToken(t *testing.T) {
 		{token.INTEGER, "3"},
 		{token.SEMICOLON, ";"},
 
+		{token.INTEGER, "2"},
+		{token.POWER, "**"},
+		{token.INTEGER, "3"},
+		{token.SEMICOLON, ";"},
+
+		{token.INTEGER, "1"},
+		{token.SHIFT_LEFT, "<<"},
+		{token.INTEGER, "2"},
+		{token.SHIFT_RIGHT, ">>"},
+		{token.INTEGER, "3"},
+		{token.SEMICOLON, ";"},
+
+		{token.IDENTIFIER, "x"},
+		{token.PIPE, "|>"},
+		{token.IDENTIFIER, "f"},
+		{token.SEMICOLON, ";"},
+
+		{token.IDENTIFIER, "f"},
+		{token.SHIFT_RIGHT, ">>"},
+		{token.IDENTIFIER, "g"},
+		{token.SHIFT_LEFT, "<<"},
+		{token.IDENTIFIER, "h"},
+		{token.SEMICOLON, ";"},
+
 		{token.EOF, ""},
 	}
 
@@ -257,23 +426,164 @@ func TestTwoCharacterToken(t *testing.T) {
 	}
 }
 
+func TestIntegerToken(t *testing.T) {
+	input := "0xFF 0o755 0b1010 1_000_000 0x_ff_ff 42"
+	lexer := New(input)
+
+	expectedLiterals := []string{"0xFF", "0o755", "0b1010", "1_000_000", "0x_ff_ff", "42"}
+	for _, expected := range expectedLiterals {
+		tok := lexer.NextToken()
+		utils.ValidateValue(tok.Type, token.INTEGER, t)
+		utils.ValidateValue(tok.Literal, expected, t)
+	}
+	utils.ValidateValue(lexer.NextToken().Type, token.EOF, t)
+	utils.ValidateValue(len(lexer.Errors()), 0, t)
+}
+
+func TestMalformedIntegerToken(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedLiteral string
+		expectedMessage string
+	}{
+		{"1__000", "1__000", "malformed integer literal: 1__000"},
+		{"100_", "100_", "malformed integer literal: 100_"},
+		{"0x", "0x", "malformed integer literal: 0x"},
+		{"0b102", "0b102", "malformed integer literal: 0b102"},
+		{"0o8", "0o8", "malformed integer literal: 0o8"},
+		{"12abc", "12abc", "malformed integer literal: 12abc"},
+		{"9223372036854775808", "9223372036854775808", "integer literal is too large: 9223372036854775808 (the maximum is 9223372036854775807)"},
+		{"0xFFFFFFFFFFFFFFFFF", "0xFFFFFFFFFFFFFFFFF", "integer literal is too large: 0xFFFFFFFFFFFFFFFFF (the maximum is 9223372036854775807)"},
+	}
+
+	for _, tt := range tests {
+		lexer := New("x = " + tt.input)
+		lexer.NextToken()
+		lexer.NextToken()
+		tok := lexer.NextToken()
+		utils.ValidateValue(tok.Type, token.ILLEGAL, t)
+		utils.ValidateValue(tok.Literal, tt.expectedLiteral, t)
+
+		err, ok := lexer.ErrorAt(tok.Position)
+		utils.ValidateValue(ok, true, t)
+		utils.ValidateValue(err.Message, tt.expectedMessage, t)
+		utils.ValidateValue(err.Position, token.Position{Line: 1, Column: 5}, t)
+	}
+}
+
+func TestUnterminatedStringToken(t *testing.T) {
+	for _, input := range []string{`x = "abc`, `x = "a ${b`, `x = "a \$`} {
+		lexer := New(input)
+		lexer.NextToken()
+		lexer.NextToken()
+		tok := lexer.NextToken()
+		utils.ValidateValue(tok.Type, token.ILLEGAL, t)
+		utils.ValidateValue(tok.Literal, input[4:], t)
+
+		err, ok := lexer.ErrorAt(tok.Position)
+		utils.ValidateValue(ok, true, t)
+		utils.ValidateValue(err.Message, "unterminated string", t)
+		utils.ValidateValue(err.Position, token.Position{Line: 1, Column: 5}, t)
+		utils.ValidateValue(lexer.NextToken().Type, token.EOF, t)
+	}
+}
+
+func TestUnterminatedRawStringToken(t *testing.T) {
+	input := "x = 1\ny = \"\"\"abc\n  \"\""
+	lexer := New(input)
+	for i := 0; i < 5; i++ {
+		lexer.NextToken()
+	}
+	tok := lexer.NextToken()
+	utils.ValidateValue(tok.Type, token.ILLEGAL, t)
+	utils.ValidateValue(tok.Literal, "\"\"\"abc\n  \"\"", t)
+
+	err, ok := lexer.ErrorAt(tok.Position)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(err.Message, "unterminated raw string", t)
+	utils.ValidateValue(err.Position, token.Position{Line: 2, Column: 5}, t)
+	utils.ValidateValue(lexer.NextToken().Type, token.EOF, t)
+}
+
+func TestNewlineBefore(t *testing.T) {
+	input := `val x = 5
+	  x +
+	  1; y`
+	lexer := New(input)
+
+	tests := []struct {
+		expectedType          token.TokenType
+		expectedNewlineBefore bool
+	}{
+		{token.VAL, false},
+		{token.IDENTIFIER, false},
+		{token.ASSIGN, false},
+		{token.INTEGER, false},
+		{token.IDENTIFIER, true},
+		{token.PLUS, false},
+		{token.INTEGER, true},
+		{token.SEMICOLON, false},
+		{token.IDENTIFIER, false},
+		{token.EOF, false},
+	}
+
+	for _, tt := range tests {
+		tok := lexer.NextToken()
+		utils.ValidateValue(tok.Type, tt.expectedType, t)
+		utils.ValidateValue(tok.NewlineBefore, tt.expectedNewlineBefore, t)
+	}
+}
+
+func TestComment(t *testing.T) {
+	input := `// header
+val x = 10 / 2 // five
+  //indented
+x`
+	lexer := New(input)
+
+	tests := []struct {
+		expectedType          token.TokenType
+		expectedNewlineBefore bool
+	}{
+		{token.VAL, true},
+		{token.IDENTIFIER, false},
+		{token.ASSIGN, false},
+		{token.INTEGER, false},
+		{token.DIVIDE, false},
+		{token.INTEGER, false},
+		{token.IDENTIFIER, true},
+		{token.EOF, false},
+	}
+
+	for _, tt := range tests {
+		tok := lexer.NextToken()
+		utils.ValidateValue(tok.Type, tt.expectedType, t)
+		utils.ValidateValue(tok.NewlineBefore, tt.expectedNewlineBefore, t)
+	}
+	comments := lexer.Comments()
+	utils.ValidateValue(len(comments), 3, t)
+	utils.ValidateValue(comments[0], token.Comment{Position: token.Position{Line: 1, Column: 1}, Text: "header"}, t)
+	utils.ValidateValue(comments[1], token.Comment{Position: token.Position{Line: 2, Column: 16}, Text: "five"}, t)
+	utils.ValidateValue(comments[2], token.Comment{Position: token.Position{Line: 3, Column: 3}, Text: "indented"}, t)
+}
+
 func TestIllegalToken(t *testing.T) {
-	input := `&;
-    	      var x = a^b;`
+	input := `#;
+    	      var x = a@b;`
 	lexer := New(input)
 
 	tests := []struct {
 		expectedType    token.TokenType
 		expectedLiteral string
 	}{
-		{token.ILLEGAL, "&"},
+		{token.ILLEGAL, "#"},
 		{token.SEMICOLON, ";"},
 
 		{token.VAR, "var"},
 		{token.IDENTIFIER, "x"},
 		{token.ASSIGN, "="},
 		{token.IDENTIFIER, "a"},
-		{token.ILLEGAL, "^"},
+		{token.ILLEGAL, "@"},
 		{token.IDENTIFIER, "b"},
 		{token.SEMICOLON, ";"},
 
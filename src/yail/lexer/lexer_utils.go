@@ -1,5 +1,7 @@
 package lexer
 
+import "strings"
+
 func IsLetter(curChar byte) bool {
 	return ('a' <= curChar && curChar <= 'z') || ('A' <= curChar && curChar <= 'Z') || curChar == '_'
 }
@@ -7,3 +9,37 @@ func IsLetter(curChar byte) bool {
 func IsDigit(curChar byte) bool {
 	return '0' <= curChar && curChar <= '9'
 }
+
+// TrimIndent removes the indentation shared by all non-blank lines,
+// and drops the first and the last lines when they are blank.
+func TrimIndent(text string) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) > 1 && isBlank(lines[0]) {
+		lines = lines[1:]
+	}
+	if len(lines) > 1 && isBlank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	indent := -1
+	for _, line := range lines {
+		if isBlank(line) {
+			continue
+		}
+		lineIndent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == -1 || lineIndent < indent {
+			indent = lineIndent
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		} else if isBlank(line) {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
@@ -0,0 +1,65 @@
+package lexer
+
+import "strings"
+
+// TemplatePart is either a piece of literal text or the source code of an expression embedded in a string.
+type TemplatePart struct {
+	Text         string
+	Expression   string
+	IsExpression bool
+	// Offset is the byte offset of the part inside the string content.
+	Offset int
+}
+
+// Unescape replaces `\$`, which writes a `$` that does not start an embedded expression, with `$`.
+// Other backslashes are kept as they are, so that strings like regular expressions and paths keep their content.
+func Unescape(content string) string {
+	return strings.ReplaceAll(content, ESCAPED_TEMPLATE_CHAR, string(TEMPLATE_CHAR))
+}
+
+// isEscaped reports whether the character at the position of the lexer is a backslash escaping a `$`.
+func (lexer *Lexer) isEscaped() bool {
+	return lexer.curChar == ESCAPE_CHAR && lexer.peekChar() == TEMPLATE_CHAR
+}
+
+// SplitTemplate splits the content of a template token into literal text and embedded expressions,
+// which are written as `${expression}` or `$identifier`.
+func SplitTemplate(content string) []TemplatePart {
+	var parts []TemplatePart
+	lexer := New(content)
+	textStart := 0
+	addText := func(end int) {
+		if end > textStart {
+			text := Unescape(content[textStart:end])
+			parts = append(parts, TemplatePart{Text: text, Offset: textStart})
+		}
+	}
+	for lexer.curChar != EOF_CHAR {
+		switch {
+		case lexer.isEscaped():
+			lexer.readNextChar()
+		case lexer.curChar == TEMPLATE_CHAR && lexer.peekChar() == '{':
+			addText(lexer.curPosition)
+			lexer.readNextChar()
+			start := lexer.curPosition + 1
+			lexer.skipEmbeddedExpression()
+			end := lexer.curPosition
+			if end > len(content) {
+				end = len(content)
+			}
+			parts = append(parts, TemplatePart{Expression: content[start:end], IsExpression: true, Offset: start})
+			textStart = end + 1
+		case lexer.curChar == TEMPLATE_CHAR && IsLetter(lexer.peekChar()):
+			addText(lexer.curPosition)
+			lexer.readNextChar()
+			start := lexer.curPosition
+			identifier := lexer.readConsecutiveLetters()
+			parts = append(parts, TemplatePart{Expression: identifier, IsExpression: true, Offset: start})
+			textStart = lexer.curPosition
+			continue
+		}
+		lexer.readNextChar()
+	}
+	addText(len(content))
+	return parts
+}
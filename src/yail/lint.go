@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"yail/analysis"
+	"yail/lexer"
+	"yail/parser"
+)
+
+// lint runs the rules of the analysis package over the given files, and returns the exit code of the command:
+// 0 when no problem is found, 1 when some are, and 2 when the command is misused.
+func lint(args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(out)
+	enable := flags.String("enable", "", "comma separated rules to run, instead of all of them")
+	disable := flags.String("disable", "", "comma separated rules not to run")
+	flags.Usage = func() {
+		fmt.Fprintln(out, "usage: yail lint [-enable rules] [-disable rules] file...")
+		flags.PrintDefaults()
+		fmt.Fprintln(out, "rules:")
+		for _, rule := range analysis.Rules {
+			fmt.Fprintf(out, "  %s: %s\n", rule.Name, rule.Description)
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	rules, err := selectRules(*enable, *disable)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 2
+	}
+
+	status := 0
+	for _, path := range flags.Args() {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(out, "failed to read %s: %s\n", path, err)
+			return 2
+		}
+		p := parser.New(lexer.New(string(source)))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			for _, err := range p.Errors() {
+				fmt.Fprintf(out, "%s: %s\n", path, err)
+			}
+			status = 1
+			continue
+		}
+		diagnostics, _ := analysis.Analyze(program, rules...)
+		for _, diagnostic := range diagnostics {
+			fmt.Fprintf(out, "%s: %s\n", path, diagnostic)
+			status = 1
+		}
+	}
+	return status
+}
+
+// selectRules returns the names of the rules enabled and not disabled by the comma separated lists.
+func selectRules(enable, disable string) ([]string, error) {
+	var rules []string
+	if enable == "" {
+		for _, rule := range analysis.Rules {
+			rules = append(rules, rule.Name)
+		}
+	} else {
+		rules = splitRules(enable)
+	}
+	disabled := map[string]bool{}
+	for _, name := range splitRules(disable) {
+		disabled[name] = true
+	}
+
+	for _, name := range append(rules, splitRules(disable)...) {
+		if _, ok := analysis.Lookup(name); !ok {
+			return nil, fmt.Errorf("unknown rule: %s", name)
+		}
+	}
+	var selected []string
+	for _, name := range rules {
+		if !disabled[name] {
+			selected = append(selected, name)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no rule is enabled")
+	}
+	return selected, nil
+}
+
+func splitRules(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
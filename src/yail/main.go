@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"os/user"
@@ -8,10 +9,50 @@ import (
 )
 
 func main() {
+	engineName := flag.String("engine", repl.EVALUATOR_ENGINE, "engine running the programs: eval or vm")
+	optimize := flag.Bool("optimize", false, "fold constants, remove dead branches and inline constants before running")
+	debugAST := flag.Bool("debug-ast", false, "print the optimized syntax tree of the programs to stderr (implies -optimize)")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: yail [flags] [script]\n       yail lint [-enable rules] [-disable rules] file...\n       yail doc [module...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.Arg(0) == "lint" {
+		os.Exit(lint(flag.Args()[1:], os.Stdout))
+	}
+	if flag.Arg(0) == "doc" {
+		os.Exit(doc(flag.Args()[1:], os.Stdout))
+	}
+	engine, err := repl.NewEngine(*engineName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *debugAST {
+		engine = repl.NewOptimizingEngine(engine, os.Stderr)
+	} else if *optimize {
+		engine = repl.NewOptimizingEngine(engine, nil)
+	}
+
+	if flag.NArg() > 0 {
+		runScript(flag.Arg(0), engine)
+		return
+	}
 	user, err := user.Current()
 	if err != nil {
 		panic(err)
 	}
 	fmt.Printf("Hello %s! This is the interactive mode for YAIL!\n", user.Username)
-	repl.Run(os.Stdin, os.Stdout)
+	repl.Run(os.Stdin, os.Stdout, engine)
+}
+
+func runScript(path string, engine repl.Engine) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read %s: %s\n", path, err)
+		os.Exit(1)
+	}
+	if !repl.RunFile(path, string(source), os.Stdout, engine) {
+		os.Exit(1)
+	}
 }
@@ -32,3 +32,8 @@ func (b *Boolean) Inspect() string {
 func (b *Boolean) HashKey() HashKey {
 	return b.hashKey
 }
+
+func (b *Boolean) Equals(other Hashable) bool {
+	boolean, ok := other.(*Boolean)
+	return ok && boolean.Value == b.Value
+}
@@ -1,12 +1,60 @@
 package object
 
-type BuiltinFunction func(args ...Object) Object
+// BuiltinFunction is the Go implementation of a builtin function. The applier calls the functions given as arguments,
+// which may be functions of the language.
+type BuiltinFunction func(apply Applier, args ...Object) Object
+
+// Applier calls the functions given to builtin functions, and charges the work builtin functions do themselves
+// to the budget of the program, if any.
+type Applier interface {
+	// Apply calls a function with the arguments, and returns its result or the error it raised.
+	Apply(fn Object, args ...Object) Object
+	// Step and Allocate count a step and an object created by a builtin function, and return the fatal error
+	// that stops the program once its budget is exhausted.
+	Step() *Error
+	Allocate() *Error
+}
+
+// ApplierFunc is an Applier calling functions with a Go function, for programs without budget.
+type ApplierFunc func(fn Object, args ...Object) Object
+
+func (f ApplierFunc) Apply(fn Object, args ...Object) Object {
+	return f(fn, args...)
+}
+
+func (f ApplierFunc) Step() *Error {
+	return nil
+}
+
+func (f ApplierFunc) Allocate() *Error {
+	return nil
+}
 
 const BUILTIN_OBJ = "BUILTIN"
 
+// The messages of the errors raised by builtin functions given wrong arguments.
+const (
+	INVALID_TYPE_EXCEPTION_MESSAGE   = "%s(%s) not supported"
+	INVALID_ARGUMENT_COUNT_MESSAGE   = "wrong number of arguments: expected %d, but received %d"
+	INVALID_ARGUMENT_RANGE_MESSAGE   = "wrong number of arguments: expected %d to %d, but received %d"
+	INVALID_ARGUMENT_MINIMUM_MESSAGE = "wrong number of arguments: expected at least %d, but received %d"
+)
+
+// VARIADIC is the MaxArgs of the builtin functions accepting any number of arguments.
+const VARIADIC = -1
+
 type Builtin struct {
-	Fn   BuiltinFunction
-	name string
+	Fn BuiltinFunction
+	// MinArgs and MaxArgs are the numbers of arguments the function accepts, so that calls can be checked
+	// without running them.
+	MinArgs int
+	MaxArgs int
+	name    string
+}
+
+// NewBuiltin creates a builtin function with the given name, which is shown when the function is printed.
+func NewBuiltin(name string, minArgs, maxArgs int, fn BuiltinFunction) *Builtin {
+	return &Builtin{Fn: fn, MinArgs: minArgs, MaxArgs: maxArgs, name: name}
 }
 
 func (b *Builtin) Type() ObjectType {
@@ -16,3 +64,20 @@ func (b *Builtin) Type() ObjectType {
 func (b *Builtin) Inspect() string {
 	return "builtin function " + b.name
 }
+
+// Accepts reports whether the function can be called with the given number of arguments.
+func (b *Builtin) Accepts(argc int) bool {
+	return argc >= b.MinArgs && (b.MaxArgs == VARIADIC || argc <= b.MaxArgs)
+}
+
+// ArgumentCountError returns the error raised when the function is called with a wrong number of arguments.
+func (b *Builtin) ArgumentCountError(argc int) *Error {
+	switch {
+	case b.MaxArgs == VARIADIC:
+		return NewErrorWithKind(ARGUMENT_ERROR, INVALID_ARGUMENT_MINIMUM_MESSAGE, b.MinArgs, argc)
+	case b.MinArgs != b.MaxArgs:
+		return NewErrorWithKind(ARGUMENT_ERROR, INVALID_ARGUMENT_RANGE_MESSAGE, b.MinArgs, b.MaxArgs, argc)
+	default:
+		return NewErrorWithKind(ARGUMENT_ERROR, INVALID_ARGUMENT_COUNT_MESSAGE, b.MinArgs, argc)
+	}
+}
@@ -1,15 +1,80 @@
 package object
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"yail/token"
+)
 
-const ERROR_OBJ = "ERROR"
+const (
+	ERROR_OBJ       = "ERROR"
+	ERROR_VALUE_OBJ = "ERROR_VALUE"
+)
 
+// Error kinds tell scripts what went wrong without parsing the message.
+const (
+	RUNTIME_ERROR    = "RuntimeError"
+	TYPE_ERROR       = "TypeError"
+	NAME_ERROR       = "NameError"
+	ARGUMENT_ERROR   = "ArgumentError"
+	ARITHMETIC_ERROR = "ArithmeticError"
+	STACK_OVERFLOW   = "StackOverflowError"
+	IMPORT_ERROR     = "ImportError"
+	COMPARISON_ERROR = "ComparisonError"
+
+	// The kinds of fatal errors, raised when the evaluation must stop.
+	CANCELLED                 = "Cancelled"
+	DEADLINE_EXCEEDED         = "DeadlineExceeded"
+	STEP_LIMIT_EXCEEDED       = "StepLimitExceeded"
+	ALLOCATION_LIMIT_EXCEEDED = "AllocationLimitExceeded"
+	THROWN_ERROR              = "Error"
+
+	// MAIN_FRAME is the name shown in tracebacks for the code outside any function.
+	MAIN_FRAME = "<main>"
+
+	MAX_REPEATED_TRACEBACK_LINES = 3
+)
+
+// StackFrame is a function call that was in progress when an error was raised.
+type StackFrame struct {
+	Function string
+	// CallSite is where the function was called.
+	CallSite token.Position
+}
+
+// Error aborts the evaluation until it is caught by a try expression or reaches the top level.
 type Error struct {
+	Kind    string
 	Message string
+	// Value is the value given to throw, or nil when the error was raised by the interpreter.
+	Value Object
+	// Position is where the error was raised, or the zero position when it is unknown.
+	Position token.Position
+	// Stack holds the function calls the error went through, from the innermost.
+	// It is nil until the error leaves a function.
+	Stack []StackFrame
+	// Fatal errors can not be caught, and stop the program without evaluating finally blocks.
+	Fatal bool
 }
 
 func NewError(format string, a ...interface{}) *Error {
-	return &Error{Message: fmt.Sprintf(format, a...)}
+	return NewErrorWithKind(RUNTIME_ERROR, format, a...)
+}
+
+func NewErrorWithKind(kind, format string, a ...interface{}) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, a...)}
+}
+
+// NewFatalError creates an error that stops the program, even inside try expressions.
+func NewFatalError(kind, format string, a ...interface{}) *Error {
+	err := NewErrorWithKind(kind, format, a...)
+	err.Fatal = true
+	return err
+}
+
+// NewThrownError creates the error raised by throwing a value that is not an error value.
+func NewThrownError(value Object) *Error {
+	return &Error{Kind: THROWN_ERROR, Message: ToString(value), Value: value}
 }
 
 func (e *Error) Type() ObjectType {
@@ -19,3 +84,95 @@ func (e *Error) Type() ObjectType {
 func (e *Error) Inspect() string {
 	return "[ERROR] " + e.Message
 }
+
+func (e *Error) ToString() string {
+	return e.Message
+}
+
+// Traceback describes where the error was raised, from the outermost call to the innermost one.
+// Lines repeated by recursion are shown only a few times.
+func (e *Error) Traceback() string {
+	var lines []string
+	caller := MAIN_FRAME
+	for i := len(e.Stack) - 1; i >= 0; i-- {
+		lines = append(lines, fmt.Sprintf("  %s, in %s", e.Stack[i].CallSite, caller))
+		caller = e.Stack[i].Function
+	}
+	if e.Position != (token.Position{}) {
+		lines = append(lines, fmt.Sprintf("  %s, in %s", e.Position, caller))
+	} else {
+		lines = append(lines, fmt.Sprintf("  in %s", caller))
+	}
+
+	var out strings.Builder
+	out.WriteString("Traceback (most recent call last):\n")
+	for i := 0; i < len(lines); {
+		repeated := 1
+		for i+repeated < len(lines) && lines[i+repeated] == lines[i] {
+			repeated += 1
+		}
+		for j := 0; j < repeated && j < MAX_REPEATED_TRACEBACK_LINES; j++ {
+			out.WriteString(lines[i] + "\n")
+		}
+		if repeated > MAX_REPEATED_TRACEBACK_LINES {
+			out.WriteString(fmt.Sprintf("  [previous line repeated %d more times]\n", repeated-MAX_REPEATED_TRACEBACK_LINES))
+		}
+		i += repeated
+	}
+	out.WriteString(e.Kind + ": " + e.Message)
+	return out.String()
+}
+
+// ErrorValue is an error that was caught, which scripts can inspect and throw again.
+type ErrorValue struct {
+	Error *Error
+}
+
+func NewErrorValue(err *Error) *ErrorValue {
+	return &ErrorValue{Error: err}
+}
+
+func (ev *ErrorValue) Type() ObjectType {
+	return ERROR_VALUE_OBJ
+}
+
+func (ev *ErrorValue) Inspect() string {
+	return ev.Error.Kind + ": " + ev.Error.Message
+}
+
+func (ev *ErrorValue) ToString() string {
+	return ev.Inspect()
+}
+
+// Field returns the property of the error with the given name, and false if there is none.
+func (ev *ErrorValue) Field(name string) (Object, bool) {
+	switch name {
+	case "message":
+		return NewString(ev.Error.Message), true
+	case "kind":
+		return NewString(ev.Error.Kind), true
+	case "stack":
+		stack := make([]Object, len(ev.Error.Stack))
+		for i, frame := range ev.Error.Stack {
+			stack[i] = NewString(frame.Function)
+		}
+		return NewArray(stack), true
+	case "traceback":
+		return NewString(ev.Error.Traceback()), true
+	case "value":
+		if ev.Error.Value == nil {
+			return NULL, true
+		}
+		return ev.Error.Value, true
+	}
+	return nil, false
+}
+
+// Rethrow returns a copy of the caught error, so that it can be raised again.
+func (ev *ErrorValue) Rethrow() *Error {
+	err := *ev.Error
+	if ev.Error.Stack != nil {
+		err.Stack = append([]StackFrame{}, ev.Error.Stack...)
+	}
+	return &err
+}
@@ -10,12 +10,18 @@ const (
 	HASH_OBJ = "HASH"
 )
 
+// HashMap keeps its pairs in the order their keys were first set, in which they are listed and printed. The pairs
+// are found by the HashKey of their keys, in buckets holding every pair whose key has the same HashKey.
 type HashMap struct {
-	Pairs map[HashKey]*HashPair
+	// pairs holds nil in place of the deleted pairs, until they make up half of it.
+	pairs []*HashPair
+	// buckets holds the indices in pairs of the keys with each HashKey.
+	buckets map[HashKey][]int
+	size    int
 }
 
-func NewHashMap(pairs map[HashKey]*HashPair) *HashMap {
-	return &HashMap{Pairs: pairs}
+func NewHashMap() *HashMap {
+	return &HashMap{buckets: map[HashKey][]int{}}
 }
 
 type HashKey struct {
@@ -26,12 +32,103 @@ type HashKey struct {
 type HashPair struct {
 	Key   Object
 	Value Object
+	// hashable is the key as given to Set, which differs from Key for the keys made by AsKey.
+	hashable Hashable
 }
 
 func NewHashPair(key, value Object) *HashPair {
 	return &HashPair{Key: key, Value: value}
 }
 
+// HashableKey returns the key of the pair as a Hashable, to set it in another hash map.
+func (p *HashPair) HashableKey() Hashable {
+	if p.hashable == nil {
+		return p.Key.(Hashable)
+	}
+	return p.hashable
+}
+
+// find returns the position of the key in its bucket, and its index in pairs, or -1 when the key is missing.
+func (h *HashMap) find(key Hashable) (int, int) {
+	for position, index := range h.buckets[key.HashKey()] {
+		if key.Equals(h.pairs[index].hashable) {
+			return position, index
+		}
+	}
+	return -1, -1
+}
+
+// Get returns the pair of the key.
+func (h *HashMap) Get(key Hashable) (*HashPair, bool) {
+	_, index := h.find(key)
+	if index < 0 {
+		return nil, false
+	}
+	return h.pairs[index], true
+}
+
+// Set gives the value to the key, which keeps its place when it is already in the hash map. The keys of values
+// that are not Hashable themselves, such as arrays, are made by AsKey.
+func (h *HashMap) Set(key Hashable, value Object) {
+	if _, index := h.find(key); index >= 0 {
+		h.pairs[index].Value = value
+		return
+	}
+	hashKey := key.HashKey()
+	h.buckets[hashKey] = append(h.buckets[hashKey], len(h.pairs))
+	h.pairs = append(h.pairs, &HashPair{Key: keyValue(key), Value: value, hashable: key})
+	h.size++
+}
+
+// Delete removes the key, and returns whether it was in the hash map.
+func (h *HashMap) Delete(key Hashable) bool {
+	position, index := h.find(key)
+	if index < 0 {
+		return false
+	}
+	hashKey := key.HashKey()
+	if bucket := h.buckets[hashKey]; len(bucket) == 1 {
+		delete(h.buckets, hashKey)
+	} else {
+		h.buckets[hashKey] = append(bucket[:position:position], bucket[position+1:]...)
+	}
+	h.pairs[index] = nil
+	h.size--
+	if h.size < len(h.pairs)/2 {
+		h.compact()
+	}
+	return true
+}
+
+func (h *HashMap) compact() {
+	pairs := make([]*HashPair, 0, h.size)
+	buckets := make(map[HashKey][]int, len(h.buckets))
+	for _, pair := range h.pairs {
+		if pair != nil {
+			hashKey := pair.hashable.HashKey()
+			buckets[hashKey] = append(buckets[hashKey], len(pairs))
+			pairs = append(pairs, pair)
+		}
+	}
+	h.pairs, h.buckets = pairs, buckets
+}
+
+// Len returns the number of pairs.
+func (h *HashMap) Len() int {
+	return h.size
+}
+
+// Pairs returns the pairs in the order their keys were first set.
+func (h *HashMap) Pairs() []*HashPair {
+	pairs := make([]*HashPair, 0, h.size)
+	for _, pair := range h.pairs {
+		if pair != nil {
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs
+}
+
 func (h *HashMap) Type() ObjectType {
 	return HASH_OBJ
 }
@@ -39,7 +136,7 @@ func (h *HashMap) Type() ObjectType {
 func (h *HashMap) Inspect() string {
 	var out bytes.Buffer
 	var pairs []string
-	for _, pair := range h.Pairs {
+	for _, pair := range h.Pairs() {
 		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
 	}
 	out.WriteString("{")
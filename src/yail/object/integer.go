@@ -5,13 +5,11 @@ import "fmt"
 const INTEGER_OBJ = "INTEGER"
 
 type Integer struct {
-	Value   int64
-	hashKey HashKey
+	Value int64
 }
 
 func NewInteger(value int64) *Integer {
-	hashKey := HashKey{Type: INTEGER_OBJ, Value: uint64(value)}
-	return &Integer{Value: value, hashKey: hashKey}
+	return &Integer{Value: value}
 }
 
 func (i *Integer) Type() ObjectType {
@@ -23,5 +21,10 @@ func (i *Integer) Inspect() string {
 }
 
 func (i *Integer) HashKey() HashKey {
-	return i.hashKey
+	return HashKey{Type: INTEGER_OBJ, Value: uint64(i.Value)}
+}
+
+func (i *Integer) Equals(other Hashable) bool {
+	integer, ok := other.(*Integer)
+	return ok && integer.Value == i.Value
 }
@@ -0,0 +1,214 @@
+package object
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+const (
+	// RECORD_HASH and RECORD_EQUALS are the keys of the functions making a hash map a record, which can be a key of
+	// other hash maps. The hash function is called with the record and returns an integer, which must be the same for
+	// equal records, and the equals function is called with two records of the same hash.
+	RECORD_HASH   = "hash"
+	RECORD_EQUALS = "equals"
+)
+
+// AsKey returns the value as a key of hash maps. Integers, strings and booleans are keys themselves, while tuples
+// and arrays are keys when their elements are, and records are keys through their hash and equals functions,
+// which are called with apply. An array or a tuple containing itself is not a key.
+func AsKey(value Object, apply Applier) (Hashable, *Error) {
+	if key, ok := value.(Hashable); ok {
+		return key, nil
+	}
+	return asKey(value, apply, map[Object]bool{})
+}
+
+// asKey returns the value as a key, where visiting holds the arrays and tuples whose elements are being made keys.
+func asKey(value Object, apply Applier, visiting map[Object]bool) (Hashable, *Error) {
+	switch value := value.(type) {
+	case Hashable:
+		return value, nil
+	case *Array, *Tuple:
+		if visiting[value] {
+			return nil, NewErrorWithKind(TYPE_ERROR, "unusable as hash key: %s containing itself", value.Type())
+		}
+		visiting[value] = true
+		defer delete(visiting, value)
+		if array, ok := value.(*Array); ok {
+			return newTupleKey(NewTuple(append([]Object{}, array.Elements...)), apply, visiting)
+		}
+		return newTupleKey(value.(*Tuple), apply, visiting)
+	case *HashMap:
+		if key, err, ok := newRecordKey(value, apply); ok {
+			return key, err
+		}
+	}
+	return nil, NewErrorWithKind(TYPE_ERROR, "unusable as hash key: %s", value.Type())
+}
+
+// IsKey reports whether AsKey accepts the value, without calling the functions of records.
+func IsKey(value Object) bool {
+	return isKey(value, map[Object]bool{})
+}
+
+func isKey(value Object, visiting map[Object]bool) bool {
+	var elements []Object
+	switch value := value.(type) {
+	case Hashable:
+		return true
+	case *Array:
+		elements = value.Elements
+	case *Tuple:
+		elements = value.Elements
+	case *HashMap:
+		_, hasHash := value.Get(NewString(RECORD_HASH))
+		_, hasEquals := value.Get(NewString(RECORD_EQUALS))
+		return hasHash && hasEquals
+	default:
+		return false
+	}
+	if visiting[value] {
+		return false
+	}
+	visiting[value] = true
+	defer delete(visiting, value)
+	for _, element := range elements {
+		if !isKey(element, visiting) {
+			return false
+		}
+	}
+	return true
+}
+
+// KeyError returns the error raised by the equals function of a record, while the key was compared with other keys.
+func KeyError(key Hashable) *Error {
+	switch key := key.(type) {
+	case *tupleKey:
+		for _, element := range key.keys {
+			if err := KeyError(element); err != nil {
+				return err
+			}
+		}
+	case *recordKey:
+		return key.err
+	}
+	return nil
+}
+
+// keyValue returns the value that the key was made of.
+func keyValue(key Hashable) Object {
+	switch key := key.(type) {
+	case *tupleKey:
+		return key.tuple
+	case *recordKey:
+		return key.record
+	}
+	return key
+}
+
+type tupleKey struct {
+	tuple   *Tuple
+	keys    []Hashable
+	hashKey HashKey
+}
+
+func newTupleKey(tuple *Tuple, apply Applier, visiting map[Object]bool) (*tupleKey, *Error) {
+	key := &tupleKey{tuple: tuple, keys: make([]Hashable, 0, len(tuple.Elements))}
+	hash := fnv.New64a()
+	for _, element := range tuple.Elements {
+		elementKey, err := asKey(element, apply, visiting)
+		if err != nil {
+			return nil, err
+		}
+		key.keys = append(key.keys, elementKey)
+		elementHashKey := elementKey.HashKey()
+		hash.Write([]byte(elementHashKey.Type))
+		hash.Write(binary.LittleEndian.AppendUint64(nil, elementHashKey.Value))
+	}
+	key.hashKey = HashKey{Type: TUPLE_OBJ, Value: hash.Sum64()}
+	return key, nil
+}
+
+func (k *tupleKey) Type() ObjectType {
+	return k.tuple.Type()
+}
+
+func (k *tupleKey) Inspect() string {
+	return k.tuple.Inspect()
+}
+
+func (k *tupleKey) HashKey() HashKey {
+	return k.hashKey
+}
+
+func (k *tupleKey) Equals(other Hashable) bool {
+	tuple, ok := other.(*tupleKey)
+	if !ok || len(tuple.keys) != len(k.keys) {
+		return false
+	}
+	for i, key := range k.keys {
+		if !key.Equals(tuple.keys[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+type recordKey struct {
+	record *HashMap
+	hash   int64
+	equals Object
+	apply  Applier
+	// err is the first error raised by equals, after which the record is equal to no other record.
+	err *Error
+}
+
+// newRecordKey returns the key of a record, and false when the hash map is not a record.
+func newRecordKey(record *HashMap, apply Applier) (*recordKey, *Error, bool) {
+	hash, hasHash := record.Get(NewString(RECORD_HASH))
+	equals, hasEquals := record.Get(NewString(RECORD_EQUALS))
+	if !hasHash || !hasEquals || apply == nil {
+		return nil, nil, false
+	}
+	result := apply.Apply(hash.Value, record)
+	if err, ok := result.(*Error); ok {
+		return nil, err, true
+	}
+	integer, ok := result.(*Integer)
+	if !ok {
+		return nil, NewErrorWithKind(TYPE_ERROR, "the %s function of a record returned %s instead of an integer", RECORD_HASH, result.Type()), true
+	}
+	return &recordKey{record: record, hash: integer.Value, equals: equals.Value, apply: apply}, nil, true
+}
+
+func (k *recordKey) Type() ObjectType {
+	return k.record.Type()
+}
+
+func (k *recordKey) Inspect() string {
+	return k.record.Inspect()
+}
+
+func (k *recordKey) HashKey() HashKey {
+	return HashKey{Type: HASH_OBJ, Value: uint64(k.hash)}
+}
+
+func (k *recordKey) Equals(other Hashable) bool {
+	record, ok := other.(*recordKey)
+	if !ok || k.err != nil {
+		return false
+	}
+	if record.record == k.record {
+		return true
+	}
+	result := k.apply.Apply(k.equals, k.record, record.record)
+	switch result := result.(type) {
+	case *Error:
+		k.err = result
+	case *Boolean:
+		return result.Value
+	default:
+		k.err = NewErrorWithKind(TYPE_ERROR, "the %s function of a record returned %s instead of a boolean", RECORD_EQUALS, result.Type())
+	}
+	return false
+}
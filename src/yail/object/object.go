@@ -7,6 +7,24 @@ type Object interface {
 	Inspect() string
 }
 
+// Hashable is implemented by the objects that can be keys of hash maps. Keys with the same HashKey are told apart
+// by Equals, so that HashKey only has to be the same for equal keys. The type of the key is part of its HashKey,
+// so that keys of different types never share one. Keys are compared with Equals called on the key looked up.
 type Hashable interface {
+	Object
 	HashKey() HashKey
+	Equals(other Hashable) bool
+}
+
+// Stringer is implemented by objects whose text form, used by string templates, differs from Inspect.
+type Stringer interface {
+	ToString() string
+}
+
+// ToString converts any object into the text inserted by string templates.
+func ToString(obj Object) string {
+	if stringer, ok := obj.(Stringer); ok {
+		return stringer.ToString()
+	}
+	return obj.Inspect()
 }
@@ -3,8 +3,68 @@ package object
 import (
 	"fmt"
 	"testing"
+	"yail/token"
 )
 
+func TestToString(t *testing.T) {
+	tests := []struct {
+		value    Object
+		expected string
+	}{
+		{NewString("Hello"), "Hello"},
+		{NewInteger(-5), "-5"},
+		{TRUE, "true"},
+		{NULL, "null"},
+		{NewArray([]Object{NewInteger(1), NewString("a")}), "[1, a]"},
+		{NewError("failed"), "failed"},
+	}
+	for _, tt := range tests {
+		if actual := ToString(tt.value); actual != tt.expected {
+			t.Errorf("expected %q to be %q", actual, tt.expected)
+		}
+	}
+}
+
+func TestTraceback(t *testing.T) {
+	err := NewErrorWithKind(ARITHMETIC_ERROR, "division by zero: 1 / 0")
+	expected := "Traceback (most recent call last):\n  in <main>\nArithmeticError: division by zero: 1 / 0"
+	if actual := err.Traceback(); actual != expected {
+		t.Errorf("expected %q to be %q", actual, expected)
+	}
+
+	err.Position = token.Position{Line: 2, Column: 5}
+	err.Stack = []StackFrame{
+		{Function: "inner", CallSite: token.Position{Line: 5, Column: 8}},
+		{Function: "outer", CallSite: token.Position{Line: 7, Column: 6}},
+	}
+	expected = `Traceback (most recent call last):
+  line 7, column 6, in <main>
+  line 5, column 8, in outer
+  line 2, column 5, in inner
+ArithmeticError: division by zero: 1 / 0`
+	if actual := err.Traceback(); actual != expected {
+		t.Errorf("expected %q to be %q", actual, expected)
+	}
+}
+
+func TestTracebackOfRecursion(t *testing.T) {
+	err := NewErrorWithKind(STACK_OVERFLOW, "stack overflow")
+	for i := 0; i < 5; i++ {
+		err.Stack = append(err.Stack, StackFrame{Function: "f", CallSite: token.Position{Line: 1, Column: 20}})
+	}
+	expected := `Traceback (most recent call last):
+  line 1, column 20, in <main>
+  line 1, column 20, in f
+  line 1, column 20, in f
+  line 1, column 20, in f
+  [previous line repeated 1 more times]
+  in f
+StackOverflowError: stack overflow`
+	if actual := err.Traceback(); actual != expected {
+		t.Errorf("expected %q to be %q", actual, expected)
+	}
+}
+
 func TestHashKeyComparison(t *testing.T) {
 	tests := []struct {
 		value1   Hashable
@@ -21,6 +81,9 @@ func TestHashKeyComparison(t *testing.T) {
 		{NewString("Hello"), TRUE, false},
 		{NewString("Hello"), NewInteger(1), false},
 		{NewInteger(1), FALSE, false},
+		{NewInteger(1), TRUE, false},
+		{NewInteger(0), FALSE, false},
+		{&Integer{Value: 7}, NewInteger(7), true},
 	}
 	for i, tt := range tests {
 		actual := tt.value1.HashKey() == tt.value2.HashKey()
@@ -28,5 +91,197 @@ func TestHashKeyComparison(t *testing.T) {
 		if actual != tt.expected {
 			t.Errorf("test %d: expected %+v to be %+v", i+1, actual, tt.expected)
 		}
+		if equal := tt.value1.Equals(tt.value2); equal != tt.expected {
+			t.Errorf("test %d: expected equality %+v to be %+v", i+1, equal, tt.expected)
+		}
+	}
+}
+
+func TestHashMapOrder(t *testing.T) {
+	h := NewHashMap()
+	for i := int64(0); i < 10; i++ {
+		h.Set(NewInteger(i), NewInteger(i*i))
+	}
+	h.Set(NewInteger(3), NewString("three"))
+	for i := int64(0); i < 8; i++ {
+		if i != 3 && !h.Delete(NewInteger(i)) {
+			t.Errorf("expected %d to be deleted", i)
+		}
+	}
+	if h.Delete(NewInteger(0)) {
+		t.Errorf("expected 0 to be deleted already")
+	}
+	h.Set(NewInteger(0), TRUE)
+
+	// a key set again keeps its place, while a deleted key goes to the end
+	expected := "{3: three, 8: 64, 9: 81, 0: true}"
+	if actual := h.Inspect(); actual != expected {
+		t.Errorf("expected %q to be %q", actual, expected)
+	}
+	if h.Len() != 4 {
+		t.Errorf("expected %d pairs to be 4", h.Len())
+	}
+	if pair, ok := h.Get(NewInteger(9)); !ok || pair.Value.Inspect() != "81" {
+		t.Errorf("expected 9 to be found with 81")
+	}
+}
+
+// collidingKey is a key whose HashKey is the same for all its values, as happens for strings of colliding hashes.
+type collidingKey struct {
+	*String
+}
+
+func (k collidingKey) HashKey() HashKey {
+	return HashKey{Type: STRING_OBJ, Value: 42}
+}
+
+func (k collidingKey) Equals(other Hashable) bool {
+	key, ok := other.(collidingKey)
+	return ok && key.Value == k.Value
+}
+
+func TestHashMapCollisions(t *testing.T) {
+	h := NewHashMap()
+	a, b, c := collidingKey{NewString("a")}, collidingKey{NewString("b")}, collidingKey{NewString("c")}
+	h.Set(a, NewInteger(1))
+	h.Set(b, NewInteger(2))
+	h.Set(c, NewInteger(3))
+	h.Set(collidingKey{NewString("b")}, NewInteger(20))
+	if !h.Delete(a) || h.Delete(a) {
+		t.Errorf("expected a to be deleted once")
+	}
+	if _, ok := h.Get(a); ok {
+		t.Errorf("expected a to be missing")
+	}
+	for _, tt := range []struct {
+		key      Hashable
+		expected string
+	}{{b, "20"}, {c, "3"}} {
+		if pair, ok := h.Get(tt.key); !ok || pair.Value.Inspect() != tt.expected {
+			t.Errorf("expected %s to be found with %s", tt.key.Inspect(), tt.expected)
+		}
+	}
+	if h.Len() != 2 {
+		t.Errorf("expected %d pairs to be 2", h.Len())
+	}
+}
+
+func TestAsKey(t *testing.T) {
+	key := func(value Object) Hashable {
+		hashable, err := AsKey(value, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Message)
+		}
+		return hashable
+	}
+	array := NewArray([]Object{NewInteger(1), NewArray([]Object{NewString("a")})})
+	tests := []struct {
+		value1   Object
+		value2   Object
+		expected bool
+	}{
+		{array, NewTuple([]Object{NewInteger(1), NewTuple([]Object{NewString("a")})}), true},
+		{array, NewArray([]Object{NewInteger(1), NewArray([]Object{NewString("b")})}), false},
+		{NewArray(nil), NewTuple(nil), true},
+		{NewArray([]Object{NewInteger(1)}), NewInteger(1), false},
+		{NewArray([]Object{NewInteger(1)}), NewArray([]Object{TRUE}), false},
+		{NewArray([]Object{NewInteger(1), NewInteger(2)}), NewArray([]Object{NewInteger(2), NewInteger(1)}), false},
+	}
+	for i, tt := range tests {
+		key1, key2 := key(tt.value1), key(tt.value2)
+		if actual := key1.HashKey() == key2.HashKey() && key1.Equals(key2); actual != tt.expected {
+			t.Errorf("test %d: expected %+v to be %+v", i+1, actual, tt.expected)
+		}
+	}
+
+	// arrays are copied, so that changing them does not change the keys
+	h := NewHashMap()
+	h.Set(key(array), NULL)
+	array.Elements[0] = NewInteger(2)
+	if expected := "{(1, [a]): null}"; h.Inspect() != expected {
+		t.Errorf("expected %q to be %q", h.Inspect(), expected)
+	}
+
+	cyclic := NewArray([]Object{NewInteger(1)})
+	cyclic.Elements = append(cyclic.Elements, cyclic)
+	if _, err := AsKey(cyclic, nil); err == nil || err.Message != "unusable as hash key: ARRAY containing itself" {
+		t.Errorf("expected an array containing itself to be unusable as hash key")
+	}
+	shared := NewTuple(nil)
+	if IsKey(cyclic) || !IsKey(NewArray([]Object{shared, shared})) {
+		t.Errorf("expected only the array containing itself not to be a key")
+	}
+
+	for _, value := range []Object{NULL, NewArray([]Object{NULL}), NewHashMap()} {
+		if _, err := AsKey(value, nil); err == nil || err.Kind != TYPE_ERROR {
+			t.Errorf("expected %s to be unusable as hash key", value.Inspect())
+		}
+	}
+}
+
+func TestRecordKeys(t *testing.T) {
+	// the records are points, whose hash ignores y so that points with the same x share a bucket
+	calls := 0
+	apply := ApplierFunc(func(fn Object, args ...Object) Object {
+		calls++
+		return fn.(*Builtin).Fn(nil, args...)
+	})
+	field := func(record Object, name string) int64 {
+		pair, _ := record.(*HashMap).Get(NewString(name))
+		return pair.Value.(*Integer).Value
+	}
+	hash := NewBuiltin("hash", 1, 1, func(_ Applier, args ...Object) Object {
+		return NewInteger(field(args[0], "x"))
+	})
+	equals := NewBuiltin("equals", 2, 2, func(_ Applier, args ...Object) Object {
+		return GetPooledBooleanObject(field(args[0], "x") == field(args[1], "x") && field(args[0], "y") == field(args[1], "y"))
+	})
+	point := func(x, y int64) *HashMap {
+		record := NewHashMap()
+		record.Set(NewString("x"), NewInteger(x))
+		record.Set(NewString("y"), NewInteger(y))
+		record.Set(NewString(RECORD_HASH), hash)
+		record.Set(NewString(RECORD_EQUALS), equals)
+		return record
+	}
+	key := func(record Object) Hashable {
+		hashable, err := AsKey(record, apply)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Message)
+		}
+		return hashable
 	}
+
+	h := NewHashMap()
+	h.Set(key(point(1, 2)), NewString("a"))
+	h.Set(key(point(1, 3)), NewString("b"))
+	h.Set(key(NewArray([]Object{point(1, 2)})), NewString("c"))
+	h.Set(key(point(1, 2)), NewString("d"))
+	if h.Len() != 3 {
+		t.Errorf("expected %d pairs to be 3", h.Len())
+	}
+	if pair, ok := h.Get(key(point(1, 3))); !ok || pair.Value.Inspect() != "b" {
+		t.Errorf("expected point(1, 3) to be found with b")
+	}
+	if _, ok := pair(h, 0).Key.(*HashMap); !ok {
+		t.Errorf("expected the key to be the record, got %s", pair(h, 0).Key.Type())
+	}
+	if calls == 0 {
+		t.Errorf("expected the functions of the records to be called")
+	}
+
+	failing := NewBuiltin("equals", 2, 2, func(_ Applier, args ...Object) Object {
+		return NewInteger(1)
+	})
+	record := point(1, 4)
+	record.Set(NewString(RECORD_EQUALS), failing)
+	failingKey := key(record)
+	h.Get(failingKey)
+	if err := KeyError(failingKey); err == nil || err.Message != "the equals function of a record returned INTEGER instead of a boolean" {
+		t.Errorf("expected the error of the equals function, got %v", err)
+	}
+}
+
+func pair(h *HashMap, i int) *HashPair {
+	return h.Pairs()[i]
 }
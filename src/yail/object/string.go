@@ -27,3 +27,12 @@ func (s *String) Inspect() string {
 func (s *String) HashKey() HashKey {
 	return s.hashKey
 }
+
+func (s *String) Equals(other Hashable) bool {
+	str, ok := other.(*String)
+	return ok && str.Value == s.Value
+}
+
+func (s *String) ToString() string {
+	return s.Value
+}
@@ -0,0 +1,34 @@
+package object
+
+import (
+	"bytes"
+	"strings"
+)
+
+const TUPLE_OBJ = "TUPLE"
+
+// Tuple is a sequence of values that can not be changed. Tuples and arrays of hashable values can be keys of hash
+// maps, compared element by element, and the arrays used as keys are copied into tuples.
+type Tuple struct {
+	Elements []Object
+}
+
+func NewTuple(elements []Object) *Tuple {
+	return &Tuple{Elements: elements}
+}
+
+func (t *Tuple) Type() ObjectType {
+	return TUPLE_OBJ
+}
+
+func (t *Tuple) Inspect() string {
+	var out bytes.Buffer
+	var elements []string
+	for _, e := range t.Elements {
+		elements = append(elements, e.Inspect())
+	}
+	out.WriteString("(")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString(")")
+	return out.String()
+}
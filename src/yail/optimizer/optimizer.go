@@ -0,0 +1,284 @@
+package optimizer
+
+import (
+	"strconv"
+	"yail/ast"
+	"yail/evaluator"
+	"yail/object"
+	"yail/resolver"
+	"yail/token"
+)
+
+// Optimize rewrites the program in place so that it does less work when it runs, without changing its result:
+//
+//   - operators applied to literals are computed ahead of time, such as `60 * 60 * 24` or `"a" + "b"`,
+//   - the branch of an `if` expression that can not be taken is removed when the condition is a literal,
+//   - the identifiers of variables declared with `val` and bound to an integer, a boolean or null are replaced
+//     by the value, after the declaration.
+//
+// Operations raising an error are left as they are, so that the error is raised when the program runs.
+// Strings are never inlined, as two evaluations of a string literal give strings that are not equal with `==`.
+func Optimize(program *ast.Program) *ast.Program {
+	o := &optimizer{scope: resolver.NewScope[ast.Expression](nil, false)}
+	program.Statements = o.optimizeStatements(program.Statements, true)
+	return program
+}
+
+type optimizer struct {
+	// scope holds the constants visible in a function or a catch block, or in the program for the outermost scope.
+	// A nil constant is a variable that is not a constant, which hides the constants of the outer scopes.
+	scope *resolver.Scope[ast.Expression]
+}
+
+// optimizeStatements optimizes the statements of a program or a block, where topLevel is true for the statements
+// run unconditionally by the enclosing function, whose constants can be inlined in the statements that follow.
+func (o *optimizer) optimizeStatements(statements []ast.Statement, topLevel bool) []ast.Statement {
+	var optimized []ast.Statement
+	for i, statement := range statements {
+		isLast := i == len(statements)-1
+		statement = o.optimizeStatement(statement, topLevel)
+		if expression, ok := statement.(*ast.ExpressionStatement); ok {
+			if _, ok := value(expression.Expression); ok && !isLast {
+				continue // the value of a literal is only used at the end of a block
+			}
+			if branch, ok := expression.Expression.(*ast.IfExpression); ok {
+				if block, ok := takenBranch(branch); ok && canSplice(block, isLast) {
+					optimized = append(optimized, block.Statements...)
+					continue
+				}
+			}
+		}
+		optimized = append(optimized, statement)
+	}
+	return optimized
+}
+
+func (o *optimizer) optimizeStatement(node ast.Statement, topLevel bool) ast.Statement {
+	switch node := node.(type) {
+	case *ast.ExpressionStatement:
+		node.Expression = o.optimizeExpression(node.Expression)
+	case *ast.VariableBindingStatement:
+		node.Value = o.optimizeExpression(node.Value)
+		if topLevel && node.Token.Type == token.VAL && isInlinable(node.Value) {
+			o.scope.Set(node.Name.Value, node.Value)
+		}
+	case *ast.ReassignmentStatement:
+		node.Value = o.optimizeExpression(node.Value)
+	case *ast.ReturnStatement:
+		node.ReturnValue = o.optimizeExpression(node.ReturnValue)
+	case *ast.ThrowStatement:
+		node.Value = o.optimizeExpression(node.Value)
+	case *ast.BlockStatement:
+		o.optimizeBlock(node)
+	}
+	return node
+}
+
+func (o *optimizer) optimizeBlock(block *ast.BlockStatement) {
+	if block != nil {
+		block.Statements = o.optimizeStatements(block.Statements, false)
+	}
+}
+
+func (o *optimizer) optimizeExpression(node ast.Expression) ast.Expression {
+	switch node := node.(type) {
+	case *ast.IdentifierExpression:
+		if constant, _ := o.scope.Lookup(node.Value); constant != nil {
+			return constant
+		}
+	case *ast.TemplateLiteral:
+		o.optimizeExpressions(node.Parts)
+		return foldTemplate(node)
+	case *ast.PrefixExpression:
+		node.RightNode = o.optimizeExpression(node.RightNode)
+		return foldPrefix(node)
+	case *ast.InfixExpression:
+		node.LeftNode = o.optimizeExpression(node.LeftNode)
+		node.RightNode = o.optimizeExpression(node.RightNode)
+		return foldInfix(node)
+	case *ast.IfExpression:
+		node.Condition = o.optimizeExpression(node.Condition)
+		o.optimizeBlock(node.Consequence)
+		o.optimizeBlock(node.Alternative)
+		return eliminateDeadBranch(node)
+	case *ast.TryExpression:
+		o.optimizeBlock(node.Block)
+		if node.Catch != nil {
+			o.scope = resolver.NewScope(o.scope, true)
+			o.scope.Set(node.CatchParameter.Value, nil)
+			for _, name := range resolver.DeclaredNames(node.Catch) {
+				o.scope.Set(name, nil)
+			}
+			o.optimizeBlock(node.Catch)
+			o.scope = o.scope.Outer
+		}
+		o.optimizeBlock(node.Finally)
+	case *ast.FunctionLiteral:
+		o.optimizeFunction(node)
+	case *ast.CallExpression:
+		node.Function = o.optimizeExpression(node.Function)
+		o.optimizeExpressions(node.Arguments)
+	case *ast.ArrayLiteral:
+		o.optimizeExpressions(node.Elements)
+	case *ast.HashMapLiteral:
+		for _, pair := range node.Pairs {
+			pair.Key = o.optimizeExpression(pair.Key)
+			pair.Value = o.optimizeExpression(pair.Value)
+		}
+	case *ast.CollectionAccessExpression:
+		node.Left = o.optimizeExpression(node.Left)
+		node.Index = o.optimizeExpression(node.Index)
+	}
+	return node
+}
+
+func (o *optimizer) optimizeExpressions(expressions []ast.Expression) {
+	for i, expression := range expressions {
+		expressions[i] = o.optimizeExpression(expression)
+	}
+}
+
+// optimizeFunction optimizes the body of a function, where the parameters and the variables declared anywhere
+// in the body hide the constants of the outer scopes.
+func (o *optimizer) optimizeFunction(node *ast.FunctionLiteral) {
+	o.scope = resolver.NewScope(o.scope, false)
+	for _, parameter := range node.Parameters {
+		o.scope.Set(parameter.Value, nil)
+	}
+	for _, name := range resolver.DeclaredNames(node.Body) {
+		o.scope.Set(name, nil)
+	}
+	node.Body.Statements = o.optimizeStatements(node.Body.Statements, true)
+	o.scope = o.scope.Outer
+}
+
+func foldPrefix(node *ast.PrefixExpression) ast.Expression {
+	right, ok := value(node.RightNode)
+	if !ok {
+		return node
+	}
+	return literal(evaluator.ApplyPrefix(node.Token, right), node.Token.Position, node)
+}
+
+func foldInfix(node *ast.InfixExpression) ast.Expression {
+	left, ok := value(node.LeftNode)
+	if !ok {
+		return node
+	}
+	right, ok := value(node.RightNode)
+	if !ok {
+		return node
+	}
+	isComparison := node.Token.Type == token.EQUAL || node.Token.Type == token.NOT_EQUAL
+	if isComparison && (left.Type() == object.STRING_OBJ || right.Type() == object.STRING_OBJ) {
+		return node // strings are compared by identity
+	}
+	return literal(evaluator.ApplyInfix(node.Token, left, right), node.Token.Position, node)
+}
+
+func foldTemplate(node *ast.TemplateLiteral) ast.Expression {
+	var text string
+	for _, part := range node.Parts {
+		value, ok := value(part)
+		if !ok {
+			return node
+		}
+		text += object.ToString(value)
+	}
+	return literal(object.NewString(text), node.Token.Position, node)
+}
+
+// eliminateDeadBranch replaces an `if` expression whose condition is a literal by the branch it evaluates,
+// when the branch is a single expression. A condition that is not a boolean evaluates to null.
+func eliminateDeadBranch(node *ast.IfExpression) ast.Expression {
+	block, ok := takenBranch(node)
+	if !ok {
+		return node
+	}
+	if block == nil {
+		return ast.NULL
+	}
+	if len(block.Statements) == 1 {
+		if statement, ok := block.Statements[0].(*ast.ExpressionStatement); ok {
+			return statement.Expression
+		}
+	}
+	return node
+}
+
+// takenBranch returns the block evaluated by an `if` expression whose condition is a literal,
+// or nil when the expression evaluates to null.
+func takenBranch(node *ast.IfExpression) (*ast.BlockStatement, bool) {
+	condition, ok := value(node.Condition)
+	if !ok {
+		return nil, false
+	}
+	switch {
+	case condition == object.TRUE:
+		return node.Consequence, true
+	case condition == object.FALSE && node.Alternative != nil:
+		return node.Alternative, true
+	default:
+		return nil, true
+	}
+}
+
+// canSplice reports whether the statements of the taken branch of an `if` expression can replace the expression
+// statement holding it. When it is the last statement of its block, the value of the block must not change.
+func canSplice(block *ast.BlockStatement, isLast bool) bool {
+	if block == nil {
+		return !isLast
+	}
+	if !isLast {
+		return true
+	}
+	if len(block.Statements) == 0 {
+		return false
+	}
+	_, ok := block.Statements[len(block.Statements)-1].(*ast.ExpressionStatement)
+	return ok
+}
+
+// value returns the value of a literal.
+func value(node ast.Expression) (object.Object, bool) {
+	switch node := node.(type) {
+	case *ast.IntegerLiteralExpression:
+		return object.NewInteger(node.Value), true
+	case *ast.StringLiteralExpression:
+		return object.NewString(node.Value), true
+	case *ast.BooleanExpression:
+		return object.GetPooledBooleanObject(node.Value), true
+	case *ast.NullExpression:
+		return object.NULL, true
+	}
+	return nil, false
+}
+
+// literal returns the literal of a computed value, or the original node if the value can not be written as a literal,
+// such as an error.
+func literal(value object.Object, position token.Position, original ast.Expression) ast.Expression {
+	switch value := value.(type) {
+	case *object.Integer:
+		tok := token.NewInteger(strconv.FormatInt(value.Value, 10))
+		tok.Position = position
+		return ast.NewIntegerLiteral(tok, value.Value)
+	case *object.String:
+		tok := token.NewString(value.Value)
+		tok.Position = position
+		return ast.NewStringLiteral(tok)
+	case *object.Boolean:
+		return ast.GetPooledBoolean(value.Value)
+	}
+	if value == object.NULL {
+		return ast.NULL
+	}
+	return original
+}
+
+func isInlinable(node ast.Expression) bool {
+	switch node.(type) {
+	case *ast.IntegerLiteralExpression, *ast.BooleanExpression, *ast.NullExpression:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package optimizer
+
+import (
+	"testing"
+	"yail/ast"
+	"yail/environment"
+	"yail/evaluator"
+	"yail/lexer"
+	"yail/object"
+	"yail/parser"
+	"yail/utils"
+)
+
+func TestFoldConstants(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"60 * 60 * 24", "86400;"},
+		{`"prefix" + "-" + "x"`, "prefix-x;"},
+		{"-(2 ** 3) + ~0", "-9;"},
+		{"!true == false", "true;"},
+		{"1 < 2", "true;"},
+		{`"a${1 + 2}b${true}"`, "a3btrue;"},
+		{"x * (2 + 3)", "(x * 5);"},
+		{"1 + 2 + x", "(3 + x);"},
+		{"x + 1 + 2", "((x + 1) + 2);"},
+		{"1 / 0", "(1 / 0);"},
+		{`1 + "a"`, "(1 + a);"},
+		{`"a" == "a"`, "(a == a);"},
+		{"[1 + 1, len(2 * 2)]", "[2, len(4)];"},
+	}
+
+	for _, tt := range tests {
+		utils.ValidateValue(optimize(t, tt.input).String(), tt.expected, t)
+	}
+}
+
+func TestEliminateDeadBranches(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"if (true) { 1 } else { 2 }", "1;"},
+		{"if (1 > 2) { 1 } else { 2 }", "2;"},
+		{"if (false) { 1 }", "null;"},
+		{"if (1) { 1 } else { 2 }", "null;"},
+		{"val x = if (true) { 1 } else { 2 }", "val x = 1;"},
+		{"if (true) { val a = 1; a + 1 }; 3", "val a = 1; (a + 1); 3;"},
+		{"if (false) { val a = 1; a }; 3", "3;"},
+		{"1; true; x", "x;"},
+		{"if (true) { val a = 1 }", "iftrue val a = 1;;"},
+		{"if (x) { 1 } else { 2 }", "ifx 1;else 2;;"},
+	}
+
+	for _, tt := range tests {
+		utils.ValidateValue(optimize(t, tt.input).String(), tt.expected, t)
+	}
+}
+
+func TestInlineConstants(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"val day = 60 * 60 * 24; day * 7", "val day = 86400; 604800;"},
+		{"val a = 1; val b = a + 1; b", "val a = 1; val b = 2; 2;"},
+		{"var a = 1; a + 1", "var a = 1; (a + 1);"},
+		{`val s = "a"; s == s`, "val s = a; (s == s);"},
+		{"a; val a = 1", "a; val a = 1;"},
+		{"val f = func() { a }; val a = 1", "val f = func() { a; }; val a = 1;"},
+		{"val a = 1; val f = func() { a }", "val a = 1; val f = func() { 1; };"},
+		{"val a = 1; val f = func(a) { a }", "val a = 1; val f = func(a) { a; };"},
+		{"val a = 1; val f = func() { if (c) { val a = 2 }; a }", "val a = 1; val f = func() { ifc val a = 2;;a; };"},
+		{"val f = func() { val a = 2; a * a }", "val f = func() { val a = 2;4; };"},
+		{"if (c) { val a = 1 }; a", "ifc val a = 1;; a;"},
+		{"val e = 1; try { e } catch (e) { e }", "val e = 1; try 1;catch(e) e;;"},
+	}
+
+	for _, tt := range tests {
+		utils.ValidateValue(optimize(t, tt.input).String(), tt.expected, t)
+	}
+}
+
+func TestOptimizedProgramsGiveTheSameResult(t *testing.T) {
+	inputs := []string{
+		"val day = 60 * 60 * 24; val week = day * 7; week",
+		`val prefix = "a" + "b"; prefix + "-" + "c"`,
+		"val f = func(n) { if (true) { n * 2 } else { n } }; f(21)",
+		"val f = func(n) { if (false) { return 1 }; val x = 2; return n + x }; f(1)",
+		"val x = 1; val f = func(c) { if (c) { val x = 2 }; x }; f(false)",
+		"val x = 1; val f = func() { val y = x; val x = 2; y }; f()",
+		"val a = 1; a = 2",
+		"1 / 0",
+		"if (true) { 1 / 0 }",
+		"val t = true; if (t) { 1 } else { 2 }",
+		"if (true) { val a = 1 }",
+		"if (true) {}",
+		"val n = null; n == null",
+		`val s = "a"; s == s`,
+		"val f = func(n) { if (n == 0) { 0 } else { f(n - 1) } }; f(3)",
+		"val e = 5; try { throw 1 } catch (e) { e.value + e }",
+		"{1 + 1: 2}[2]",
+	}
+
+	for _, input := range inputs {
+		expected := evaluator.Eval(parse(t, input), environment.NewEnvironment())
+		actual := evaluator.Eval(optimize(t, input), environment.NewEnvironment())
+		if describe(actual) != describe(expected) {
+			t.Errorf("%q: expected %s, but the optimized program returned %s", input, describe(expected), describe(actual))
+		}
+	}
+}
+
+func optimize(t *testing.T, input string) *ast.Program {
+	return Optimize(parse(t, input))
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	utils.ValidateValue(len(p.Errors()), 0, t)
+	return program
+}
+
+func describe(obj object.Object) string {
+	if obj == nil {
+		return "nothing"
+	}
+	if err, ok := obj.(*object.Error); ok {
+		return err.Traceback()
+	}
+	return obj.Inspect()
+}
@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"fmt"
+	"yail/token"
+)
+
+type Severity int
+
+const (
+	SEVERITY_ERROR Severity = iota
+	SEVERITY_WARNING
+)
+
+func (s Severity) String() string {
+	if s == SEVERITY_WARNING {
+		return "WARNING"
+	}
+	return "ERROR"
+}
+
+// ParseError describes a problem found while parsing, located at the token that revealed it.
+type ParseError struct {
+	Position token.Position
+	Expected string
+	Actual   string
+	Message  string
+	Severity Severity
+}
+
+func newParseError(tok token.Token, expected, format string, a ...interface{}) *ParseError {
+	return &ParseError{
+		Position: tok.Position,
+		Expected: expected,
+		Actual:   tok.Literal,
+		Message:  fmt.Sprintf(format, a...),
+		Severity: SEVERITY_ERROR,
+	}
+}
+
+func newParseWarning(tok token.Token, format string, a ...interface{}) *ParseError {
+	return &ParseError{
+		Position: tok.Position,
+		Actual:   tok.Literal,
+		Message:  fmt.Sprintf(format, a...),
+		Severity: SEVERITY_WARNING,
+	}
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s (%s)", e.Message, e.Position)
+}
@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"fmt"
 	"yail/ast"
 	"yail/token"
 )
@@ -10,9 +9,15 @@ const (
 	NO_PRIORITY int = iota
 	EQUALS_PRIORITY
 	COMPARISON_PRIORITY
+	PIPELINE_PRIORITY
+	BIT_OR_PRIORITY
+	BIT_XOR_PRIORITY
+	BIT_AND_PRIORITY
+	SHIFT_COMPOSITION_PRIORITY
 	SUM_SUBTRACT_PRIORITY
 	PROD_DIV_PRIORITY
 	PREFIX_PRIORITY
+	POWER_PRIORITY
 	FUNCTION_CALL_PRIORITY
 	COLLECTION_ACCESS_PRIORITY
 )
@@ -29,8 +34,16 @@ var priorities = map[token.TokenType]int{
 	token.NOT_EQUAL:        EQUALS_PRIORITY,
 	token.LESS_OR_EQUAL:    EQUALS_PRIORITY,
 	token.GREATER_OR_EQUAL: EQUALS_PRIORITY,
+	token.PIPE:             PIPELINE_PRIORITY,
+	token.BIT_OR:           BIT_OR_PRIORITY,
+	token.BIT_XOR:          BIT_XOR_PRIORITY,
+	token.BIT_AND:          BIT_AND_PRIORITY,
+	token.SHIFT_LEFT:       SHIFT_COMPOSITION_PRIORITY,
+	token.SHIFT_RIGHT:      SHIFT_COMPOSITION_PRIORITY,
+	token.POWER:            POWER_PRIORITY,
 	token.LEFT_PARENTHESIS: FUNCTION_CALL_PRIORITY,
 	token.LEFT_BRACKET:     COLLECTION_ACCESS_PRIORITY,
+	token.DOT:              COLLECTION_ACCESS_PRIORITY,
 }
 
 type (
@@ -39,7 +52,8 @@ type (
 )
 
 func parseExpressionStatement(p *Parser) *ast.ExpressionStatement {
-	stmt := ast.NewExpressionStatement(p.curToken, p.parseExpression(NO_PRIORITY))
+	tok := p.curToken
+	stmt := ast.NewExpressionStatement(tok, p.parseExpression(NO_PRIORITY))
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
@@ -56,16 +70,23 @@ func (p *Parser) parseExpression(priority int) ast.Expression {
 
 func (p *Parser) parseCurToken() (bool, ast.Expression) {
 	nud := p.nuds[p.curToken.Type]
+	if nud == nil && p.curTokenIs(token.EOF) {
+		p.addDiagnostic(newParseError(p.curToken, "expression", "unexpected end of input"))
+		return false, nil
+	}
+	if err, ok := p.lexer.ErrorAt(p.curToken.Position); ok && p.curTokenIs(token.ILLEGAL) {
+		p.addDiagnostic(newParseError(p.curToken, "expression", "%s", err.Message))
+		return false, nil
+	}
 	if nud == nil {
-		msg := fmt.Sprintf("failed to understand: '%s'", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.addDiagnostic(newParseError(p.curToken, "expression", "failed to understand: '%s'", p.curToken.Literal))
 		return false, nil
 	}
 	return true, nud(p)
 }
 
 func (p *Parser) pratParse(leftExp ast.Expression, priority int) ast.Expression {
-	for !p.peekTokenIs(token.SEMICOLON) && priority < p.getNextTokenPriority() {
+	for !p.peekTokenIs(token.SEMICOLON) && !p.peekTokenStartsNewLine() && priority < p.getNextTokenPriority() {
 		infix := p.leds[p.peekToken.Type]
 		if infix == nil {
 			return leftExp
@@ -91,6 +112,7 @@ func (p *Parser) getNextTokenPriority() int {
 }
 
 func parseElements(end token.TokenType, p *Parser) []ast.Expression {
+	defer p.setNewlineEndsStatement(false)()
 	var elements []ast.Expression
 	p.nextToken()
 	if p.curTokenIs(end) {
@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"fmt"
 	"yail/ast"
 	"yail/token"
 )
@@ -19,8 +18,16 @@ func (p *Parser) initLeftDenotations() {
 		token.NOT_EQUAL:        parseInfixExpression,
 		token.LESS_OR_EQUAL:    parseInfixExpression,
 		token.GREATER_OR_EQUAL: parseInfixExpression,
+		token.BIT_AND:          parseInfixExpression,
+		token.BIT_OR:           parseInfixExpression,
+		token.BIT_XOR:          parseInfixExpression,
+		token.SHIFT_LEFT:       parseInfixExpression,
+		token.SHIFT_RIGHT:      parseInfixExpression,
+		token.POWER:            parseRightAssociativeInfixExpression,
+		token.PIPE:             parsePipelineExpression,
 		token.LEFT_PARENTHESIS: parseFunctionCallExpression,
 		token.LEFT_BRACKET:     parseCollectionAccessExpression,
+		token.DOT:              parseMemberAccessExpression,
 	}
 }
 
@@ -32,21 +39,51 @@ func parseInfixExpression(leftNode ast.Expression, p *Parser) ast.Expression {
 	return ast.NewInfix(leftNode, infixToken, rightNode)
 }
 
-func parseFunctionCallExpression(function ast.Expression, p *Parser) ast.Expression {
-	functionIdentifier, ok := function.(*ast.IdentifierExpression)
-	if !ok {
-		msg := fmt.Sprintf("unsupported operation : %s(", functionIdentifier.Value)
-		p.errors = append(p.errors, msg)
+// parseRightAssociativeInfixExpression parses `a ** b ** c` as `a ** (b ** c)`
+// by letting the right operand absorb operators of the same priority.
+func parseRightAssociativeInfixExpression(leftNode ast.Expression, p *Parser) ast.Expression {
+	infixToken := p.curToken
+	priority := p.getCurTokenPriority()
+	p.nextToken()
+	rightNode := p.parseExpression(priority - 1)
+	return ast.NewInfix(leftNode, infixToken, rightNode)
+}
+
+// parsePipelineExpression rewrites `x |> f(a, b)` into `f(x, a, b)` and `x |> f` into `f(x)`.
+func parsePipelineExpression(leftNode ast.Expression, p *Parser) ast.Expression {
+	pipeToken := p.curToken
+	p.nextToken()
+	rightNode := p.parseExpression(PIPELINE_PRIORITY)
+	if call, ok := rightNode.(*ast.CallExpression); ok {
+		call.Arguments = append([]ast.Expression{leftNode}, call.Arguments...)
+		return call
 	}
+	return ast.NewFunctionCall(pipeToken, rightNode, []ast.Expression{leftNode})
+}
+
+func parseFunctionCallExpression(function ast.Expression, p *Parser) ast.Expression {
+	callToken := p.curToken
 	args := parseElements(token.RIGHT_PARENTHESIS, p)
-	return ast.NewFunctionCall(functionIdentifier, args)
+	return ast.NewFunctionCall(callToken, function, args)
 }
 
 func parseCollectionAccessExpression(left ast.Expression, p *Parser) ast.Expression {
+	defer p.setNewlineEndsStatement(false)()
+	accessToken := p.curToken
 	p.nextToken()
 	index := p.parseExpression(NO_PRIORITY)
 	if !p.nextTokenAndValidate(token.RIGHT_BRACKET) {
 		return nil
 	}
-	return ast.NewCollectionAccess(left, index)
+	return ast.NewCollectionAccess(accessToken, left, index)
+}
+
+// parseMemberAccessExpression rewrites `x.name` into `x["name"]`.
+func parseMemberAccessExpression(left ast.Expression, p *Parser) ast.Expression {
+	accessToken := p.curToken
+	if !p.nextTokenAndValidate(token.IDENTIFIER) {
+		return nil
+	}
+	name := ast.NewStringLiteral(token.NewString(p.curToken.Literal))
+	return ast.NewCollectionAccess(accessToken, left, name)
 }
@@ -1,9 +1,9 @@
 package parser
 
 import (
-	"fmt"
 	"strconv"
 	"yail/ast"
+	"yail/lexer"
 	"yail/token"
 )
 
@@ -12,13 +12,16 @@ func (p *Parser) initNullDenotations() {
 		token.IDENTIFIER:       parseIdentifier,
 		token.INTEGER:          parseIntegerLiteral,
 		token.STRING:           parseStringLiteral,
+		token.TEMPLATE:         parseTemplateLiteral,
 		token.TRUE:             parseBooleanLiteral,
 		token.FALSE:            parseBooleanLiteral,
 		token.NULL:             parseNull,
 		token.NOT:              parsePrefixExpression,
 		token.MINUS:            parsePrefixExpression,
+		token.BIT_NOT:          parsePrefixExpression,
 		token.LEFT_PARENTHESIS: parseGroupedExpression,
 		token.IF:               parseIfExpression,
+		token.TRY:              parseTryExpression,
 		token.FUNCTION:         parseFunctionLiteral,
 		token.LEFT_BRACKET:     parseArrayLiteral,
 		token.LEFT_BRACE:       parseHashLiteral,
@@ -32,8 +35,7 @@ func parseIdentifier(p *Parser) ast.Expression {
 func parseIntegerLiteral(p *Parser) ast.Expression {
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.addDiagnostic(newParseError(p.curToken, "integer", "could not parse %q as integer", p.curToken.Literal))
 		return nil
 	}
 	return ast.NewIntegerLiteral(p.curToken, value)
@@ -43,6 +45,51 @@ func parseStringLiteral(p *Parser) ast.Expression {
 	return ast.NewStringLiteral(p.curToken)
 }
 
+func parseTemplateLiteral(p *Parser) ast.Expression {
+	templateToken := p.curToken
+	var parts []ast.Expression
+	for _, part := range lexer.SplitTemplate(templateToken.Literal) {
+		if !part.IsExpression {
+			parts = append(parts, ast.NewStringLiteral(token.NewString(part.Text)))
+			continue
+		}
+		expression := parseEmbeddedExpression(p, part)
+		if expression == nil {
+			return nil
+		}
+		parts = append(parts, expression)
+	}
+	return ast.NewTemplateLiteral(templateToken, parts)
+}
+
+// parseEmbeddedExpression parses an expression embedded in a template with a separate parser,
+// reporting its problems at their positions inside the template.
+func parseEmbeddedExpression(p *Parser, part lexer.TemplatePart) ast.Expression {
+	embedded := New(lexer.New(part.Expression))
+	expression := embedded.parseExpression(NO_PRIORITY)
+	if !embedded.panicking && !embedded.peekTokenIs(token.EOF) {
+		embedded.addDiagnostic(newParseError(embedded.peekToken, token.RIGHT_BRACE, "unexpected token in string template: '%s'", embedded.peekToken.Literal))
+	}
+	start := p.curToken.Position
+	start.Column += 1 + part.Offset // skip the opening quote
+	for _, diagnostic := range embedded.diagnostics {
+		diagnostic.Position = shiftPosition(diagnostic.Position, start)
+		p.addDiagnostic(diagnostic)
+	}
+	if embedded.panicking {
+		return nil
+	}
+	return expression
+}
+
+// shiftPosition converts a position relative to an embedded source into a position in the enclosing source.
+func shiftPosition(position, start token.Position) token.Position {
+	if position.Line == 1 {
+		return token.Position{Line: start.Line, Column: start.Column + position.Column - 1}
+	}
+	return token.Position{Line: start.Line + position.Line - 1, Column: position.Column}
+}
+
 func parseBooleanLiteral(p *Parser) ast.Expression {
 	return ast.GetPooledBoolean(p.curTokenIs(token.TRUE))
 }
@@ -59,6 +106,7 @@ func parsePrefixExpression(p *Parser) ast.Expression {
 }
 
 func parseGroupedExpression(p *Parser) ast.Expression {
+	defer p.setNewlineEndsStatement(false)()
 	p.nextToken()
 	exp := p.parseExpression(NO_PRIORITY) // always parse inside the `(~)` first
 	if !p.nextTokenAndValidate(token.RIGHT_PARENTHESIS) {
@@ -71,9 +119,12 @@ func parseIfExpression(p *Parser) ast.Expression {
 	if !p.nextTokenAndValidate(token.LEFT_PARENTHESIS) {
 		return nil
 	}
+	restore := p.setNewlineEndsStatement(false)
 	p.nextToken()
 	condition := p.parseExpression(NO_PRIORITY)
-	if !p.nextTokenAndValidate(token.RIGHT_PARENTHESIS) {
+	ok := p.nextTokenAndValidate(token.RIGHT_PARENTHESIS)
+	restore()
+	if !ok {
 		return nil
 	}
 	if !p.nextTokenAndValidate(token.LEFT_BRACE) {
@@ -91,34 +142,98 @@ func parseIfExpression(p *Parser) ast.Expression {
 	return ast.NewIf(condition, consequence)
 }
 
+func parseTryExpression(p *Parser) ast.Expression {
+	if !p.nextTokenAndValidate(token.LEFT_BRACE) {
+		return nil
+	}
+	block := parseBlockStatement(p)
+	var parameter *ast.IdentifierExpression
+	var catch, finally *ast.BlockStatement
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+		if parameter = parseCatchParameter(p); parameter == nil {
+			return nil
+		}
+		if !p.nextTokenAndValidate(token.LEFT_BRACE) {
+			return nil
+		}
+		catch = parseBlockStatement(p)
+	}
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+		if !p.nextTokenAndValidate(token.LEFT_BRACE) {
+			return nil
+		}
+		finally = parseBlockStatement(p)
+	}
+	if catch == nil && finally == nil {
+		p.nextToken()
+		p.addDiagnostic(newParseError(p.curToken, string(token.CATCH), "missing catch or finally after try block"))
+		return nil
+	}
+	return ast.NewTry(block, parameter, catch, finally)
+}
+
+func parseCatchParameter(p *Parser) *ast.IdentifierExpression {
+	defer p.setNewlineEndsStatement(false)()
+	if !p.nextTokenAndValidate(token.LEFT_PARENTHESIS) || !p.nextTokenAndValidate(token.IDENTIFIER) {
+		return nil
+	}
+	parameter := ast.NewIdentifier(p.curToken)
+	if !p.nextTokenAndValidate(token.RIGHT_PARENTHESIS) {
+		return nil
+	}
+	return parameter
+}
+
 func parseFunctionLiteral(p *Parser) ast.Expression {
 	if !p.nextTokenAndValidate(token.LEFT_PARENTHESIS) {
 		return nil
 	}
-	params := parseFunctionParameters(p)
-	if !p.nextTokenAndValidate(token.LEFT_BRACE) {
+	params, types := parseFunctionParameters(p)
+	returnType, ok := parseOptionalTypeAnnotation(p)
+	if !ok || !p.nextTokenAndValidate(token.LEFT_BRACE) {
 		return nil
 	}
 	body := parseBlockStatement(p)
-	return ast.NewFunctionLiteral(params, body)
+	function := ast.NewFunctionLiteral(params, body)
+	function.ParameterTypes = types
+	function.ReturnType = returnType
+	return function
 }
 
-func parseFunctionParameters(p *Parser) []*ast.IdentifierExpression {
+// parseFunctionParameters parses the parameters of a function with their optional types,
+// where the types are nil unless a parameter is annotated.
+func parseFunctionParameters(p *Parser) ([]*ast.IdentifierExpression, []*ast.TypeAnnotation) {
+	defer p.setNewlineEndsStatement(false)()
 	var identifiers []*ast.IdentifierExpression
+	var types []*ast.TypeAnnotation
+	isAnnotated := false
 	p.nextToken()
 	if p.curTokenIs(token.RIGHT_PARENTHESIS) {
-		return identifiers
+		return identifiers, nil
 	}
-	identifiers = append(identifiers, ast.NewIdentifier(p.curToken))
-	for p.peekTokenIs(token.COMMA) {
+	for {
+		identifiers = append(identifiers, ast.NewIdentifier(p.curToken))
+		annotation, ok := parseOptionalTypeAnnotation(p)
+		if !ok {
+			return nil, nil
+		}
+		types = append(types, annotation)
+		isAnnotated = isAnnotated || annotation != nil
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
 		p.nextToken()
 		p.nextToken()
-		identifiers = append(identifiers, ast.NewIdentifier(p.curToken))
 	}
 	if !p.nextTokenAndValidate(token.RIGHT_PARENTHESIS) {
-		return nil
+		return nil, nil
+	}
+	if !isAnnotated {
+		types = nil
 	}
-	return identifiers
+	return identifiers, types
 }
 
 func parseArrayLiteral(p *Parser) ast.Expression {
@@ -127,16 +242,25 @@ func parseArrayLiteral(p *Parser) ast.Expression {
 }
 
 func parseHashLiteral(p *Parser) ast.Expression {
-	pairs := make(map[ast.Expression]ast.Expression)
+	defer p.setNewlineEndsStatement(false)()
+	var pairs []*ast.HashMapPair
+	keys := map[interface{}]bool{}
 	for !p.peekTokenIs(token.RIGHT_BRACE) {
 		p.nextToken()
+		keyToken := p.curToken
 		key := p.parseExpression(NO_PRIORITY)
+		if value, ok := literalValue(key); ok {
+			if keys[value] {
+				p.addDiagnostic(newParseError(keyToken, "", "duplicate key %#v in hash map literal", value))
+			}
+			keys[value] = true
+		}
 		if !p.nextTokenAndValidate(token.COLON) {
 			return nil
 		}
 		p.nextToken()
 		value := p.parseExpression(NO_PRIORITY)
-		pairs[key] = value
+		pairs = append(pairs, ast.NewHashMapPair(key, value))
 		if !p.peekTokenIs(token.RIGHT_BRACE) && !p.nextTokenAndValidate(token.COMMA) {
 			return nil
 		}
@@ -146,3 +270,16 @@ func parseHashLiteral(p *Parser) ast.Expression {
 	}
 	return ast.NewHashMapLiteral(pairs)
 }
+
+// literalValue returns the value of an integer, string or boolean literal, whose Go types tell them apart.
+func literalValue(expression ast.Expression) (interface{}, bool) {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteralExpression:
+		return expression.Value, true
+	case *ast.StringLiteralExpression:
+		return expression.Value, true
+	case *ast.BooleanExpression:
+		return expression.Value, true
+	}
+	return nil, false
+}
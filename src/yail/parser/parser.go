@@ -1,32 +1,65 @@
 package parser
 
 import (
-	"fmt"
 	"yail/ast"
 	"yail/lexer"
 	"yail/token"
 )
 
+var statementEndings = map[token.TokenType]bool{
+	token.IDENTIFIER:        true,
+	token.INTEGER:           true,
+	token.STRING:            true,
+	token.TEMPLATE:          true,
+	token.TRUE:              true,
+	token.FALSE:             true,
+	token.NULL:              true,
+	token.RETURN:            true,
+	token.RIGHT_PARENTHESIS: true,
+	token.RIGHT_BRACKET:     true,
+	token.RIGHT_BRACE:       true,
+}
+
+// statementStarts are the tokens where parsing resumes after a broken statement.
+var statementStarts = map[token.TokenType]bool{
+	token.VAR:    true,
+	token.VAL:    true,
+	token.RETURN: true,
+	token.THROW:  true,
+	token.IMPORT: true,
+	token.EXPORT: true,
+}
+
 type Parser struct {
-	lexer  *lexer.Lexer
-	errors []string
+	lexer       *lexer.Lexer
+	diagnostics []*ParseError
 
 	curToken  token.Token
 	peekToken token.Token
 
+	// panicking suppresses follow-up errors of a broken statement until the parser synchronizes.
+	panicking bool
+	// braceDepth counts the braces opened up to the current token, and blockDepth is its value inside the current block.
+	braceDepth int
+	blockDepth int
+
+	// newlineEndsStatement is false while parsing inside brackets, where line breaks are insignificant.
+	newlineEndsStatement bool
+
 	nuds map[token.TokenType]nullDenotation
 	leds map[token.TokenType]leftDenotation
 }
 
 func New(lexer *lexer.Lexer) *Parser {
 	p := &Parser{
-		lexer:  lexer,
-		errors: []string{},
+		lexer:                lexer,
+		diagnostics:          []*ParseError{},
+		newlineEndsStatement: true,
 	}
 	p.initNullDenotations()
 	p.initLeftDenotations()
-	p.curToken = p.lexer.NextToken()
-	p.peekToken = p.lexer.NextToken()
+	p.nextToken()
+	p.nextToken()
 	return p
 }
 
@@ -36,15 +69,22 @@ func (p *Parser) ParseProgram() *ast.Program {
 
 	for !p.curTokenIs(token.EOF) {
 		stmt := p.parseStatement()
-		if stmt != nil {
+		if p.panicking {
+			p.synchronize()
+		} else if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
 		}
 		p.nextToken()
 	}
+	program.Comments = p.lexer.Comments()
 	return program
 }
 
 func (p *Parser) parseStatement() ast.Statement {
+	if p.curTokenIs(token.SEMICOLON) {
+		p.addDiagnostic(newParseWarning(p.curToken, "unnecessary semicolon"))
+		return nil
+	}
 	if isVariableBindingStatement(p) {
 		return parseVariableBindingStatement(p)
 	}
@@ -54,28 +94,113 @@ func (p *Parser) parseStatement() ast.Statement {
 	if isReturnStatement(p) {
 		return parseReturnStatement(p)
 	}
+	if isThrowStatement(p) {
+		return parseThrowStatement(p)
+	}
+	if isImportStatement(p) {
+		return parseImportStatement(p)
+	}
+	if isExportStatement(p) {
+		return parseExportStatement(p)
+	}
 	return parseExpressionStatement(p)
 }
 
-func (p *Parser) Errors() []string {
-	return p.errors
+// Errors returns the problems that prevent the program from being evaluated.
+func (p *Parser) Errors() []*ParseError {
+	var errors []*ParseError
+	for _, diagnostic := range p.diagnostics {
+		if diagnostic.Severity == SEVERITY_ERROR {
+			errors = append(errors, diagnostic)
+		}
+	}
+	return errors
+}
+
+// Diagnostics returns every error and warning in the order they were found.
+func (p *Parser) Diagnostics() []*ParseError {
+	return p.diagnostics
+}
+
+func (p *Parser) addDiagnostic(diagnostic *ParseError) {
+	if diagnostic.Severity == SEVERITY_ERROR {
+		if p.panicking {
+			return
+		}
+		p.panicking = true
+	}
+	p.diagnostics = append(p.diagnostics, diagnostic)
+}
+
+// synchronize skips the rest of a broken statement, stopping at its last token
+// so that the caller can move on to the next statement.
+func (p *Parser) synchronize() {
+	p.panicking = false
+	for !p.curTokenIs(token.SEMICOLON) && !p.curTokenIs(token.EOF) && !p.curTokenClosesBlock() {
+		closesBlock := p.peekTokenIs(token.RIGHT_BRACE) && p.braceDepth <= p.blockDepth
+		if p.peekTokenIs(token.EOF) || closesBlock || p.peekToken.NewlineBefore || statementStarts[p.peekToken.Type] {
+			return
+		}
+		p.nextToken()
+	}
 }
 
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
 	p.peekToken = p.lexer.NextToken()
+	switch {
+	case p.curTokenIs(token.LEFT_BRACE):
+		p.braceDepth += 1
+	case p.curTokenIs(token.RIGHT_BRACE) && p.braceDepth > 0:
+		p.braceDepth -= 1
+	}
 }
 
 func (p *Parser) nextTokenAndValidate(t token.TokenType) bool {
+	if p.panicking {
+		return false // leave the remaining tokens to synchronize
+	}
 	p.nextToken()
 	if p.curTokenIs(t) {
 		return true
 	}
-	msg := fmt.Sprintf("missing token: %s", t)
-	p.errors = append(p.errors, msg)
+	p.addDiagnostic(newParseError(p.curToken, string(t), "missing token: %s", t))
 	return false
 }
 
+// curTokenClosesBlock reports whether the current token is the closing brace of the block being parsed.
+func (p *Parser) curTokenClosesBlock() bool {
+	return p.curTokenIs(token.RIGHT_BRACE) && p.braceDepth < p.blockDepth
+}
+
+// validateStatementEnd consumes the semicolon ending the current statement.
+// The semicolon may be omitted before a closing brace, at the end of the input, or at the end of a line.
+func (p *Parser) validateStatementEnd() bool {
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+		return true
+	}
+	if p.peekTokenIs(token.RIGHT_BRACE) || p.peekTokenIs(token.EOF) || p.peekTokenStartsNewLine() {
+		return true
+	}
+	return p.nextTokenAndValidate(token.SEMICOLON)
+}
+
+// peekTokenStartsNewLine reports whether a line break ends the statement before the next token,
+// which is the case when the line ends with a token that can end a statement.
+func (p *Parser) peekTokenStartsNewLine() bool {
+	return p.newlineEndsStatement && p.peekToken.NewlineBefore && statementEndings[p.curToken.Type]
+}
+
+// setNewlineEndsStatement switches how line breaks are treated and returns a function restoring the previous mode.
+func (p *Parser) setNewlineEndsStatement(enabled bool) (restore func()) {
+	previous := p.newlineEndsStatement
+	p.newlineEndsStatement = enabled
+	return func() {
+		p.newlineEndsStatement = previous
+	}
+}
+
 func (p *Parser) curTokenIs(t token.TokenType) bool {
 	return p.curToken.Type == t
 }
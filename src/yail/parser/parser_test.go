@@ -84,6 +84,45 @@ func TestStringExpression(t *testing.T) {
 	utils.ValidateValue(literal.Value, "hello world", t)
 }
 
+func TestTemplateLiteral(t *testing.T) {
+	input := `"Hello ${user.name}, you have $count items";`
+	program := parseAndValidate(t, input)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	template, ok := stmt.Expression.(*ast.TemplateLiteral)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(len(template.Parts), 5, t)
+	utils.ValidateValue(template.Parts[0].(*ast.StringLiteralExpression).Value, "Hello ", t)
+	access, ok := template.Parts[1].(*ast.CollectionAccessExpression)
+	utils.ValidateValue(ok, true, t)
+	testLiteralExpression(t, access.Left, "user")
+	utils.ValidateValue(access.Index.(*ast.StringLiteralExpression).Value, "name", t)
+	utils.ValidateValue(template.Parts[2].(*ast.StringLiteralExpression).Value, ", you have ", t)
+	testLiteralExpression(t, template.Parts[3], "count")
+	utils.ValidateValue(template.Parts[4].(*ast.StringLiteralExpression).Value, " items", t)
+	utils.ValidateValue(template.String(), "Hello ${(user[name])}, you have ${count} items", t)
+}
+
+func TestTemplateLiteralErrors(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedMessage  string
+		expectedPosition token.Position
+	}{
+		{`val x = "a ${1 +} b";`, "unexpected end of input", token.Position{Line: 1, Column: 17}},
+		{`val x = 1;` + "\n" + `"${x y}";`, "unexpected token in string template: 'y'", token.Position{Line: 2, Column: 6}},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		utils.ValidateValue(len(errors), 1, t)
+		utils.ValidateValue(errors[0].Message, tt.expectedMessage, t)
+		utils.ValidateValue(errors[0].Position, tt.expectedPosition, t)
+	}
+}
+
 func TestPrefixExpression(t *testing.T) {
 	prefixTests := []struct {
 		input    string
@@ -96,6 +135,7 @@ func TestPrefixExpression(t *testing.T) {
 		{"-foobar;", "-", "foobar"},
 		{"!true;", "!", true},
 		{"!false;", "!", false},
+		{"~15;", "~", 15},
 	}
 
 	for _, tt := range prefixTests {
@@ -127,6 +167,12 @@ func TestInfixExpression(t *testing.T) {
 		{"5 != x;", 5, "!=", "x"},
 		{"5 <= x;", 5, "<=", "x"},
 		{"5 >= x;", 5, ">=", "x"},
+		{"5 & x;", 5, "&", "x"},
+		{"5 | x;", 5, "|", "x"},
+		{"5 ^ x;", 5, "^", "x"},
+		{"5 << x;", 5, "<<", "x"},
+		{"5 >> x;", 5, ">>", "x"},
+		{"5 ** x;", 5, "**", "x"},
 	}
 
 	for _, tt := range infixTests {
@@ -223,6 +269,101 @@ func TestFunctionParameters(t *testing.T) {
 	}
 }
 
+func TestTypeAnnotations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"val x: Int = 5;", "val x: Int = 5;"},
+		{"var y: String? = null", "var y: String? = null;"},
+		{"val z = 1", "val z = 1;"},
+		{"func(a: String, b: Int?): Bool { true }", "func(a: String, b: Int?): Bool { true; };"},
+		{"func(a, b: Int) { a }", "func(a, b: Int) { a; };"},
+		{"func(a): Func { a }", "func(a): Func { a; };"},
+	}
+
+	for _, tt := range tests {
+		program := parseAndValidate(t, tt.input)
+		utils.ValidateValue(program.String(), tt.expected, t)
+	}
+
+	function := parseAndValidate(t, "func(a, b: Int?) {}").Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	utils.ValidateValue(function.ParameterType(0) == nil, true, t)
+	utils.ValidateValue(function.ParameterType(1).Name, "Int", t)
+	utils.ValidateValue(function.ParameterType(1).Nullable, true, t)
+	utils.ValidateValue(function.ReturnType == nil, true, t)
+	unannotated := parseAndValidate(t, "func(a, b) {}").Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	utils.ValidateValue(unannotated.ParameterTypes == nil, true, t)
+}
+
+func TestTypeAnnotationErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"val x: = 5;", "missing token: IDENTIFIER"},
+		{"val x: 1 = 5;", "missing token: IDENTIFIER"},
+		{"func(a:) { a }", "missing token: IDENTIFIER"},
+		{"func(a): { a }", "missing token: IDENTIFIER"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		utils.ValidateValue(len(errors) > 0, true, t)
+		if len(errors) > 0 {
+			utils.ValidateValue(errors[0].Message, tt.expectedMessage, t)
+		}
+	}
+}
+
+func TestImportAndExport(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`import "lib/math.yail" as m;`, `import "lib/math.yail" as m;`},
+		{`import "math.yail" as math
+math.add(1, 2)`, `import "math.yail" as math; (math[add])(1, 2);`},
+		{"export val x: Int = 5", "export val x: Int = 5;"},
+		{"export var y = 1;", "export var y = 1;"},
+	}
+
+	for _, tt := range tests {
+		program := parseAndValidate(t, tt.input)
+		utils.ValidateValue(program.String(), tt.expected, t)
+	}
+
+	statement := parseAndValidate(t, `import "m.yail" as m`).Statements[0].(*ast.ImportStatement)
+	utils.ValidateValue(statement.Path, "m.yail", t)
+	utils.ValidateValue(statement.Alias.Value, "m", t)
+}
+
+func TestImportAndExportErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`import lib as m;`, "missing token: STRING"},
+		{`import "lib.yail";`, "missing token: as"},
+		{`import "lib.yail" as "m";`, "missing token: IDENTIFIER"},
+		{`func() { import "lib.yail" as m; }`, "import is only allowed at the top level"},
+		{`if (true) { export val x = 1 }`, "export is only allowed at the top level"},
+		{`export x = 1`, "export must be followed by a val or var declaration"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		utils.ValidateValue(len(errors) > 0, true, t)
+		if len(errors) > 0 {
+			utils.ValidateValue(errors[0].Message, tt.expectedMessage, t)
+		}
+	}
+}
+
 func TestEmptyFunctionCallExpression(t *testing.T) {
 	input := "add();"
 
@@ -254,6 +395,23 @@ func TestFunctionCallExpression(t *testing.T) {
 	testInfixExpression(t, exp.Arguments[2], 4, "+", 5)
 }
 
+func TestPipelineExpression(t *testing.T) {
+	input := "x |> add(1, 2);"
+
+	program := parseAndValidate(t, input)
+	utils.ValidateValue(len(program.Statements), 1, t)
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	utils.ValidateValue(ok, true, t)
+
+	exp, ok := stmt.Expression.(*ast.CallExpression)
+	utils.ValidateValue(ok, true, t)
+	testLiteralExpression(t, exp.Function, "add")
+	utils.ValidateValue(len(exp.Arguments), 3, t)
+	testLiteralExpression(t, exp.Arguments[0], "x")
+	testLiteralExpression(t, exp.Arguments[1], 1)
+	testLiteralExpression(t, exp.Arguments[2], 2)
+}
+
 func TestParsingEmptyArrayLiterals(t *testing.T) {
 	input := "[];"
 	program := parseAndValidate(t, input)
@@ -299,14 +457,17 @@ func TestParsingEmptyHashLiteral(t *testing.T) {
 }
 
 func TestParsingHashLiteralKeys(t *testing.T) {
-	input := `{"one": 1, "two": 2, true: 10, false: 20, 1: 100, 2: 200}`
-	expected := map[interface{}]int64{
-		"one": 1,
-		"two": 2,
-		true:  10,
-		false: 20,
-		1:     100,
-		2:     200,
+	input := `{"two": 2, "one": 1, true: 10, false: 20, 2: 200, 1: 100}`
+	expected := []struct {
+		key   interface{}
+		value int64
+	}{
+		{"two", 2},
+		{"one", 1},
+		{true, 10},
+		{false, 20},
+		{2, 200},
+		{1, 100},
 	}
 	program := parseAndValidate(t, input)
 
@@ -314,16 +475,42 @@ func TestParsingHashLiteralKeys(t *testing.T) {
 	hash, ok := stmt.Expression.(*ast.HashMapLiteral)
 	utils.ValidateValue(ok, true, t)
 	utils.ValidateValue(len(hash.Pairs), len(expected), t)
-	for key, value := range hash.Pairs {
-		switch k := key.(type) {
-		case *ast.StringLiteralExpression:
-			testLiteralExpression(t, value, expected[k.Value])
-		case *ast.BooleanExpression:
-			testLiteralExpression(t, value, expected[k.Value])
-		case *ast.IntegerLiteralExpression:
-			testLiteralExpression(t, value, expected[int(k.Value)])
+	for i, pair := range hash.Pairs {
+		// the pairs keep the order of the source
+		if key, ok := expected[i].key.(string); ok {
+			utils.ValidateValue(pair.Key.(*ast.StringLiteralExpression).Value, key, t)
+		} else {
+			testLiteralExpression(t, pair.Key, expected[i].key)
+		}
+		testLiteralExpression(t, pair.Value, expected[i].value)
+	}
+}
+
+func TestHashLiteralDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedMessage  string
+		expectedPosition token.Position
+	}{
+		{`{"a": 1, "b": 2, "a": 3}`, `duplicate key "a" in hash map literal`, token.Position{Line: 1, Column: 18}},
+		{`{1: 1,
+		  true: 2, 1: 3}`, "duplicate key 1 in hash map literal", token.Position{Line: 2, Column: 14}},
+		{`{true: 1, true: 2}`, "duplicate key true in hash map literal", token.Position{Line: 1, Column: 11}},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		utils.ValidateValue(len(errors), 1, t)
+		if len(errors) > 0 {
+			utils.ValidateValue(errors[0].Message, tt.expectedMessage, t)
+			utils.ValidateValue(errors[0].Position, tt.expectedPosition, t)
 		}
 	}
+
+	// keys of different types, and keys computed at runtime, are not duplicates
+	parseAndValidate(t, `{1: 1, "1": 2, true: 3, "true": 4, x: 5, x: 6}`)
 }
 
 func TestParsingHashLiteralValues(t *testing.T) {
@@ -344,11 +531,11 @@ func TestParsingHashLiteralValues(t *testing.T) {
 
 	utils.ValidateValue(ok, true, t)
 	utils.ValidateValue(len(hash.Pairs), len(hashTests), t)
-	for key, value := range hash.Pairs {
-		literal, ok := key.(*ast.StringLiteralExpression)
+	for _, pair := range hash.Pairs {
+		literal, ok := pair.Key.(*ast.StringLiteralExpression)
 		utils.ValidateValue(ok, true, t)
 		tt := hashTests[literal.Value]
-		testInfixExpression(t, value, tt.leftValue, tt.operator, tt.rightValue)
+		testInfixExpression(t, pair.Value, tt.leftValue, tt.operator, tt.rightValue)
 	}
 }
 
@@ -461,6 +648,58 @@ func TestOperationPriorities(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])));",
 		},
+		{
+			"a |> f |> g(b)",
+			"g(f(a), b);",
+		},
+		{
+			"a + b |> f == c",
+			"(f((a + b)) == c);",
+		},
+		{
+			"a |> f >> g << h",
+			"((f >> g) << h)(a);",
+		},
+		{
+			"f >> g + h",
+			"(f >> (g + h));",
+		},
+		{
+			"(f >> g)(a)",
+			"(f >> g)(a);",
+		},
+		{
+			"a | b ^ c & d",
+			"(a | (b ^ (c & d)));",
+		},
+		{
+			"a & b << 1 == c",
+			"((a & (b << 1)) == c);",
+		},
+		{
+			"a << b + c",
+			"(a << (b + c));",
+		},
+		{
+			"~a & b",
+			"((~a) & b);",
+		},
+		{
+			"a.b.c[d] + e.f(g)",
+			"((((a[b])[c])[d]) + (e[f])(g));",
+		},
+		{
+			"a ** b ** c",
+			"(a ** (b ** c));",
+		},
+		{
+			"-a ** b",
+			"(-(a ** b));",
+		},
+		{
+			"a * b ** c",
+			"(a * (b ** c));",
+		},
 	}
 
 	for _, tt := range tests {
@@ -470,13 +709,87 @@ func TestOperationPriorities(t *testing.T) {
 	}
 }
 
+func TestOptionalSemicolons(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"val x = 5\nvar y = x\ny = 10\nx",
+			"val x = 5; var y = x; y = 10; x;",
+		},
+		{
+			"val x = 1 +\n  2\nx",
+			"val x = (1 + 2); x;",
+		},
+		{
+			"val f = func(x) {\n  val y = x * 2\n  return y\n}\nf(1)",
+			"val f = func(x) { val y = (x * 2);y; }; f(1);",
+		},
+		{
+			"val f = func() { return }\nf()",
+			"val f = func() { null; }; f();",
+		},
+		{
+			"val f = func() {\n  return\n}",
+			"val f = func() { null; };",
+		},
+		{
+			"add(1,\n  2\n)\n[1,\n 2]",
+			"add(1, 2); [1, 2];",
+		},
+		{
+			"val x = (1\n + 2)",
+			"val x = (1 + 2);",
+		},
+		{
+			"val m = {\n  \"a\": 1\n}\nm[\n\"a\"\n]",
+			"val m = {a:1}; (m[a]);",
+		},
+		{
+			"val x = if (a) {\n  1\n}\nelse {\n  2\n}\nx",
+			"val x = ifa 1;else 2;; x;",
+		},
+		{
+			"val f = func(x) { x }\n(1)",
+			"val f = func(x) { x; }; 1;",
+		},
+		{
+			"val x = 5; val y = 6;",
+			"val x = 5; val y = 6;",
+		},
+	}
+
+	for _, tt := range tests {
+		program := parseAndValidate(t, tt.input)
+		utils.ValidateValue(program.String(), tt.expected, t)
+	}
+}
+
+func TestMissingStatementEnd(t *testing.T) {
+	tests := []string{
+		"val x = 5 val y = 6",
+		"var x = 5 10",
+		"x = 5 y = 6",
+		"return 5 6",
+	}
+
+	for _, input := range tests {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		errors := p.Errors()
+		utils.ValidateValue(len(errors) > 0, true, t)
+		utils.ValidateValue(errors[0].Message, "missing token: ;", t)
+	}
+}
+
 func TestIllegalInput(t *testing.T) {
 	tests := []struct {
 		input   string
 		illegal string
 	}{
-		{"&;", "&"},
-		{"5^2", "^"},
+		{"#;", "#"},
+		{"5?2", "?"},
 		{"a + #", "#"},
 		{"2@", "@"},
 		{"$1", "$"},
@@ -489,19 +802,219 @@ func TestIllegalInput(t *testing.T) {
 		errors := p.Errors()
 		for _, actual := range errors {
 			expected := fmt.Sprintf("failed to understand: '%s'", tt.illegal)
-			utils.ValidateValue(actual, expected, t)
+			utils.ValidateValue(actual.Message, expected, t)
 		}
 	}
 }
 
+func TestMalformedIntegerLiteral(t *testing.T) {
+	input := "val x = 1;\nval y = 0b12 + 1;"
+	p := New(lexer.New(input))
+	p.ParseProgram()
+
+	errors := p.Errors()
+	utils.ValidateValue(len(errors), 1, t)
+	utils.ValidateValue(errors[0].Message, "malformed integer literal: 0b12", t)
+	utils.ValidateValue(errors[0].Position, token.Position{Line: 2, Column: 9}, t)
+}
+
+func TestUnterminatedString(t *testing.T) {
+	input := "val x = 1;\nprint(\"abc);"
+	p := New(lexer.New(input))
+	p.ParseProgram()
+
+	errors := p.Errors()
+	utils.ValidateValue(len(errors), 1, t)
+	utils.ValidateValue(errors[0].Message, "unterminated string", t)
+	utils.ValidateValue(errors[0].Position, token.Position{Line: 2, Column: 7}, t)
+}
+
+func TestParseErrorDetails(t *testing.T) {
+	input := "val x = 5;\nval = 10;"
+	p := New(lexer.New(input))
+	p.ParseProgram()
+	errors := p.Errors()
+
+	utils.ValidateValue(len(errors), 1, t)
+	err := errors[0]
+	utils.ValidateValue(err.Message, "missing token: IDENTIFIER", t)
+	utils.ValidateValue(err.Expected, token.IDENTIFIER, t)
+	utils.ValidateValue(err.Actual, "=", t)
+	utils.ValidateValue(err.Position, token.Position{Line: 2, Column: 5}, t)
+	utils.ValidateValue(err.Severity, SEVERITY_ERROR, t)
+	utils.ValidateValue(err.Error(), "missing token: IDENTIFIER (line 2, column 5)", t)
+}
+
+func TestParseErrorRecovery(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedErrors  []string
+		expectedProgram string
+	}{
+		{
+			"val x = 5 +;\nval y = 10;\ny;",
+			[]string{"failed to understand: ';'"},
+			"val y = 10; y;",
+		},
+		{
+			"val = 5;\nval y = # + 1;\nval z = 3;",
+			[]string{"missing token: IDENTIFIER", "failed to understand: '#'"},
+			"val z = 3;",
+		},
+		{
+			"val x = 5 val y = 6\nval z = 7",
+			[]string{"missing token: ;"},
+			"val z = 7;",
+		},
+		{
+			"val f = func(x) { val y = ; x }; f(1);",
+			[]string{"failed to understand: ';'"},
+			"val f = func(x) { x; }; f(1);",
+		},
+		{
+			"val f = func(x) {\n  x +\n}\nval y = 2",
+			[]string{"failed to understand: '}'"},
+			"val f = func(x) {  }; val y = 2;",
+		},
+		{
+			"add(1, #, 3); val m = {\"a\": 1}; m",
+			[]string{"failed to understand: '#'"},
+			"val m = {a:1}; m;",
+		},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		errors := p.Errors()
+		utils.ValidateValue(len(errors), len(tt.expectedErrors), t)
+		for i, err := range errors {
+			utils.ValidateValue(err.Message, tt.expectedErrors[i], t)
+		}
+		utils.ValidateValue(program.String(), tt.expectedProgram, t)
+	}
+}
+
+func TestTryExpression(t *testing.T) {
+	input := `
+		val result = try {
+		  risky()
+		} catch (e) {
+		  e.message
+		} finally {
+		  cleanup()
+		}
+		try { x } finally { y }
+		throw "boom"`
+	program := parseAndValidate(t, input)
+	utils.ValidateValue(len(program.Statements), 3, t)
+
+	binding := program.Statements[0].(*ast.VariableBindingStatement)
+	expr, ok := binding.Value.(*ast.TryExpression)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(expr.CatchParameter.Value, "e", t)
+	utils.ValidateValue(len(expr.Block.Statements), 1, t)
+	utils.ValidateValue(len(expr.Catch.Statements), 1, t)
+	utils.ValidateValue(len(expr.Finally.Statements), 1, t)
+
+	stmt := program.Statements[1].(*ast.ExpressionStatement)
+	expr, ok = stmt.Expression.(*ast.TryExpression)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(expr.Catch == nil, true, t)
+	utils.ValidateValue(expr.CatchParameter == nil, true, t)
+	utils.ValidateValue(expr.String(), "try x;finally y;", t)
+
+	throw, ok := program.Statements[2].(*ast.ThrowStatement)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(throw.String(), "throw boom;", t)
+}
+
+func TestTryExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"try { x };", "missing catch or finally after try block"},
+		{"try { x } catch { y }", "missing token: ("},
+		{"try { x } catch (1) { y }", "missing token: IDENTIFIER"},
+		{"throw;", "failed to understand: ';'"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		utils.ValidateValue(len(errors), 1, t)
+		utils.ValidateValue(errors[0].Message, tt.expectedMessage, t)
+	}
+}
+
+func TestTailCallMarking(t *testing.T) {
+	input := `func(n) {
+		a(1)
+		if (n) { return b(2) }
+		val x = c(3)
+		if (n) { d(4) } else { try { e(5) } finally { f(6) } }
+	}`
+	program := parseAndValidate(t, input)
+	function := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+
+	tailCalls := map[string]bool{}
+	var visit func(node ast.Node)
+	visit = func(node ast.Node) {
+		switch node := node.(type) {
+		case *ast.BlockStatement:
+			for _, statement := range node.Statements {
+				visit(statement)
+			}
+		case *ast.ExpressionStatement:
+			visit(node.Expression)
+		case *ast.ReturnStatement:
+			visit(node.ReturnValue)
+		case *ast.VariableBindingStatement:
+			visit(node.Value)
+		case *ast.IfExpression:
+			visit(node.Consequence)
+			if node.Alternative != nil {
+				visit(node.Alternative)
+			}
+		case *ast.TryExpression:
+			visit(node.Block)
+			visit(node.Finally)
+		case *ast.CallExpression:
+			tailCalls[node.Function.String()] = node.IsTailCall
+		}
+	}
+	visit(function.Body)
+
+	expected := map[string]bool{"a": false, "b": true, "c": false, "d": true, "e": false, "f": false}
+	for name, isTailCall := range expected {
+		utils.ValidateValue(tailCalls[name], isTailCall, t)
+	}
+	utils.ValidateValue(len(tailCalls), len(expected), t)
+}
+
+func TestParseWarning(t *testing.T) {
+	p := New(lexer.New("val x = 5;;\nx;"))
+	program := p.ParseProgram()
+
+	utils.ValidateValue(len(p.Errors()), 0, t)
+	diagnostics := p.Diagnostics()
+	utils.ValidateValue(len(diagnostics), 1, t)
+	utils.ValidateValue(diagnostics[0].Severity, SEVERITY_WARNING, t)
+	utils.ValidateValue(diagnostics[0].Message, "unnecessary semicolon", t)
+	utils.ValidateValue(diagnostics[0].Position, token.Position{Line: 1, Column: 11}, t)
+	utils.ValidateValue(program.String(), "val x = 5; x;", t)
+}
+
 func validateNoParserErrors(t *testing.T, p *Parser) {
 	errors := p.Errors()
 	if len(errors) == 0 {
 		return
 	}
 	t.Errorf("parser has %d errors", len(errors))
-	for _, msg := range errors {
-		t.Errorf("parser error: %q", msg)
+	for _, err := range errors {
+		t.Errorf("parser error: %q", err.Error())
 	}
 	t.FailNow()
 }
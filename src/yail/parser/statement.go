@@ -14,16 +14,19 @@ func parseVariableBindingStatement(p *Parser) *ast.VariableBindingStatement {
 	if !p.nextTokenAndValidate(token.IDENTIFIER) {
 		return nil
 	}
-	name := ast.NewIdentifierFrom(p.curToken.Literal)
-	if !p.nextTokenAndValidate(token.ASSIGN) {
+	name := ast.NewIdentifier(p.curToken)
+	annotation, ok := parseOptionalTypeAnnotation(p)
+	if !ok || !p.nextTokenAndValidate(token.ASSIGN) {
 		return nil
 	}
 	p.nextToken()
 	value := p.parseExpression(NO_PRIORITY)
-	if !p.nextTokenAndValidate(token.SEMICOLON) {
+	if !p.validateStatementEnd() {
 		return nil
 	}
-	return ast.NewVariableBinding(curToken, name, value)
+	statement := ast.NewVariableBinding(curToken, name, value)
+	statement.Type = annotation
+	return statement
 }
 
 func isReassignmentStatement(p *Parser) bool {
@@ -35,13 +38,13 @@ func parseReassignmentStatement(p *Parser) *ast.ReassignmentStatement {
 	if !p.curTokenIs(token.IDENTIFIER) {
 		return nil
 	}
-	name := ast.NewIdentifierFrom(p.curToken.Literal)
+	name := ast.NewIdentifier(p.curToken)
 	if !p.nextTokenAndValidate(token.ASSIGN) {
 		return nil
 	}
 	p.nextToken()
 	value := p.parseExpression(NO_PRIORITY)
-	if !p.nextTokenAndValidate(token.SEMICOLON) {
+	if !p.validateStatementEnd() {
 		return nil
 	}
 	return ast.NewReassignment(curToken, name, value)
@@ -52,26 +55,119 @@ func isReturnStatement(p *Parser) bool {
 }
 
 func parseReturnStatement(p *Parser) *ast.ReturnStatement {
-	p.nextToken()
-	if p.curTokenIs(token.SEMICOLON) {
-		return ast.NewReturn(ast.NULL)
+	returnToken := p.curToken
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+		return ast.NewReturn(returnToken, ast.NULL)
 	}
+	if p.peekTokenIs(token.RIGHT_BRACE) || p.peekTokenIs(token.EOF) || p.peekTokenStartsNewLine() {
+		return ast.NewReturn(returnToken, ast.NULL)
+	}
+	p.nextToken()
 	returnValue := p.parseExpression(NO_PRIORITY)
-	if !p.nextTokenAndValidate(token.SEMICOLON) {
+	if !p.validateStatementEnd() {
+		return nil
+	}
+	return ast.NewReturn(returnToken, returnValue)
+}
+
+func isThrowStatement(p *Parser) bool {
+	return p.curTokenIs(token.THROW)
+}
+
+func parseThrowStatement(p *Parser) *ast.ThrowStatement {
+	throwToken := p.curToken
+	p.nextToken()
+	value := p.parseExpression(NO_PRIORITY)
+	if !p.validateStatementEnd() {
+		return nil
+	}
+	return ast.NewThrow(throwToken, value)
+}
+
+func isImportStatement(p *Parser) bool {
+	return p.curTokenIs(token.IMPORT)
+}
+
+func parseImportStatement(p *Parser) *ast.ImportStatement {
+	importToken := p.curToken
+	if p.braceDepth > 0 {
+		p.addDiagnostic(newParseError(importToken, "", "import is only allowed at the top level"))
+		return nil
+	}
+	if !p.nextTokenAndValidate(token.STRING) {
+		return nil
+	}
+	path := p.curToken.Literal
+	if !p.nextTokenAndValidate(token.AS) || !p.nextTokenAndValidate(token.IDENTIFIER) {
+		return nil
+	}
+	alias := ast.NewIdentifier(p.curToken)
+	if !p.validateStatementEnd() {
+		return nil
+	}
+	return ast.NewImport(importToken, path, alias)
+}
+
+func isExportStatement(p *Parser) bool {
+	return p.curTokenIs(token.EXPORT)
+}
+
+func parseExportStatement(p *Parser) *ast.VariableBindingStatement {
+	exportToken := p.curToken
+	if p.braceDepth > 0 {
+		p.addDiagnostic(newParseError(exportToken, "", "export is only allowed at the top level"))
+		return nil
+	}
+	p.nextToken()
+	if !isVariableBindingStatement(p) {
+		p.addDiagnostic(newParseError(p.curToken, "val or var", "export must be followed by a val or var declaration"))
 		return nil
 	}
-	return ast.NewReturn(returnValue)
+	statement := parseVariableBindingStatement(p)
+	if statement != nil {
+		statement.Exported = true
+	}
+	return statement
 }
 
 func parseBlockStatement(p *Parser) *ast.BlockStatement {
+	defer p.setNewlineEndsStatement(true)()
+	outerBlockDepth := p.blockDepth
+	p.blockDepth = p.braceDepth
+	defer func() { p.blockDepth = outerBlockDepth }()
+
 	var statements []ast.Statement
 	p.nextToken()
-	for !p.curTokenIs(token.RIGHT_BRACE) && !p.curTokenIs(token.EOF) {
+	for !p.curTokenClosesBlock() && !p.curTokenIs(token.EOF) {
 		stmt := p.parseStatement()
-		if stmt != nil {
+		if p.panicking {
+			p.synchronize()
+			if p.curTokenClosesBlock() {
+				break
+			}
+		} else if stmt != nil {
 			statements = append(statements, stmt)
 		}
 		p.nextToken()
 	}
 	return ast.NewBlock(statements)
 }
+
+// parseOptionalTypeAnnotation parses the type following a name or the parameters of a function, such as `: Int?`,
+// and returns nil when there is no colon. It reports false when the type is missing.
+func parseOptionalTypeAnnotation(p *Parser) (*ast.TypeAnnotation, bool) {
+	if !p.peekTokenIs(token.COLON) {
+		return nil, true
+	}
+	p.nextToken()
+	if !p.nextTokenAndValidate(token.IDENTIFIER) {
+		return nil, false
+	}
+	name := p.curToken
+	nullable := p.peekTokenIs(token.QUESTION)
+	if nullable {
+		p.nextToken()
+	}
+	return ast.NewTypeAnnotation(name, nullable), true
+}
@@ -0,0 +1,128 @@
+package repl
+
+import (
+	"fmt"
+	"io"
+	"yail/ast"
+	"yail/compiler"
+	"yail/environment"
+	"yail/evaluator"
+	"yail/object"
+	"yail/optimizer"
+	"yail/resolver"
+	"yail/types"
+	"yail/vm"
+)
+
+const (
+	EVALUATOR_ENGINE = "eval"
+	VM_ENGINE        = "vm"
+)
+
+// Engine runs programs, keeping the variables they declare for the next ones.
+type Engine interface {
+	// Run checks the program, then runs it.
+	Run(program *ast.Program) object.Object
+	// Check resolves the variables of the program and checks its types, returning the first error found.
+	Check(program *ast.Program) *object.Error
+	// RunChecked runs a program accepted by Check, which may have been rewritten since, without checking its types
+	// again.
+	RunChecked(program *ast.Program) object.Object
+}
+
+// NewEngine returns the engine with the given name: the tree-walking evaluator, or the bytecode virtual machine.
+func NewEngine(name string) (Engine, error) {
+	switch name {
+	case EVALUATOR_ENGINE:
+		return &evaluatorEngine{env: environment.NewEnvironment()}, nil
+	case VM_ENGINE:
+		return &vmEngine{compiler: compiler.New(), globals: vm.NewGlobals()}, nil
+	default:
+		return nil, fmt.Errorf("unknown engine: %s (expected %s or %s)", name, EVALUATOR_ENGINE, VM_ENGINE)
+	}
+}
+
+// NewOptimizingEngine returns an engine optimizing the programs before running them with the given engine.
+// The programs are checked before they are optimized, so that the optimizer does not change which programs are
+// accepted. When debug is not nil, the optimized programs are printed to it.
+func NewOptimizingEngine(engine Engine, debug io.Writer) Engine {
+	return &optimizingEngine{engine: engine, debug: debug}
+}
+
+type optimizingEngine struct {
+	engine Engine
+	debug  io.Writer
+}
+
+func (e *optimizingEngine) Run(program *ast.Program) object.Object {
+	if err := e.Check(program); err != nil {
+		return err
+	}
+	return e.RunChecked(program)
+}
+
+func (e *optimizingEngine) Check(program *ast.Program) *object.Error {
+	return e.engine.Check(program)
+}
+
+func (e *optimizingEngine) RunChecked(program *ast.Program) object.Object {
+	optimizer.Optimize(program)
+	if e.debug != nil {
+		io.WriteString(e.debug, program.String()+"\n")
+	}
+	return e.engine.RunChecked(program)
+}
+
+type evaluatorEngine struct {
+	env *environment.Environment
+}
+
+func (e *evaluatorEngine) Run(program *ast.Program) object.Object {
+	return evaluator.Eval(program, e.env)
+}
+
+func (e *evaluatorEngine) Check(program *ast.Program) *object.Error {
+	return evaluator.Check(program, e.env)
+}
+
+func (e *evaluatorEngine) RunChecked(program *ast.Program) object.Object {
+	return evaluator.EvalChecked(program, e.env)
+}
+
+type vmEngine struct {
+	compiler *compiler.Compiler
+	globals  *vm.Globals
+}
+
+func (e *vmEngine) Run(program *ast.Program) object.Object {
+	if err := e.Check(program); err != nil {
+		return err
+	}
+	return e.compileAndRun(program)
+}
+
+func (e *vmEngine) Check(program *ast.Program) *object.Error {
+	if errors := resolver.Resolve(program, e.globals); len(errors) > 0 {
+		return errors[0]
+	}
+	if errors := types.Check(program); len(errors) > 0 {
+		return errors[0]
+	}
+	return nil
+}
+
+func (e *vmEngine) RunChecked(program *ast.Program) object.Object {
+	if errors := resolver.Resolve(program, e.globals); len(errors) > 0 {
+		return errors[0]
+	}
+	return e.compileAndRun(program)
+}
+
+// compileAndRun compiles a resolved program and runs it.
+func (e *vmEngine) compileAndRun(program *ast.Program) object.Object {
+	bytecode, err := e.compiler.Compile(program)
+	if err != nil {
+		return object.NewError("failed to compile: %s", err)
+	}
+	return vm.New(bytecode, e.globals).Run()
+}
@@ -4,9 +4,8 @@ import (
 	"bufio"
 	"fmt"
 	"io"
-	"yail/environment"
-	"yail/evaluator"
 	"yail/lexer"
+	"yail/object"
 	"yail/parser"
 )
 
@@ -16,9 +15,8 @@ const (
 	QUIT   = "q"
 )
 
-func Run(in io.Reader, out io.Writer) {
+func Run(in io.Reader, out io.Writer, engine Engine) {
 	scanner := bufio.NewScanner(in)
-	env := environment.NewEnvironment()
 	for {
 		fmt.Printf(PROMPT)
 		scanned := scanner.Scan()
@@ -37,8 +35,13 @@ func Run(in io.Reader, out io.Writer) {
 			printParserErrors(out, p.Errors())
 			continue
 		}
+		printParserWarnings(out, p.Diagnostics())
 
-		evaluated := evaluator.Eval(program, env)
+		evaluated := engine.Run(program)
+		if err, ok := evaluated.(*object.Error); ok {
+			printTraceback(out, err)
+			continue
+		}
 		if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
 			io.WriteString(out, "\n")
@@ -46,9 +49,21 @@ func Run(in io.Reader, out io.Writer) {
 	}
 }
 
-func printParserErrors(out io.Writer, errors []string) {
+func printParserErrors(out io.Writer, errors []*parser.ParseError) {
 	io.WriteString(out, "Failed to execute the given source code for following reasons.\n")
-	for _, msg := range errors {
-		io.WriteString(out, "\t[ERROR] "+msg+"\n")
+	for _, err := range errors {
+		io.WriteString(out, "\t[ERROR] "+err.Error()+"\n")
+	}
+}
+
+func printParserWarnings(out io.Writer, diagnostics []*parser.ParseError) {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == parser.SEVERITY_WARNING {
+			io.WriteString(out, "[WARNING] "+diagnostic.Error()+"\n")
+		}
 	}
 }
+
+func printTraceback(out io.Writer, err *object.Error) {
+	io.WriteString(out, err.Traceback()+"\n")
+}
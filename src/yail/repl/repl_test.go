@@ -0,0 +1,34 @@
+package repl
+
+import (
+	"strings"
+	"testing"
+	"yail/utils"
+)
+
+func TestOptimizingEngine(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"if (false) { y = 1 }; 3", "NameError: identifier not found: 'y'"},
+		{`val x: String = if (true) { 1 } else { "a" }; x`, "1"},
+		{`val x: Int = if (true) { 1 } else { 2 }; x * 60 * 60`, "3600"},
+		{`val x: Int = "a"`, "TypeError: 'x' is declared as Int, but is given String"},
+	}
+
+	for _, tt := range tests {
+		for _, name := range []string{EVALUATOR_ENGINE, VM_ENGINE} {
+			for _, optimize := range []bool{false, true} {
+				engine, _ := NewEngine(name)
+				if optimize {
+					engine = NewOptimizingEngine(engine, nil)
+				}
+				var out strings.Builder
+				RunScript(tt.input, &out, engine)
+				lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+				utils.ValidateValue(lines[len(lines)-1], tt.expected, t)
+			}
+		}
+	}
+}
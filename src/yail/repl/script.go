@@ -0,0 +1,37 @@
+package repl
+
+import (
+	"io"
+	"yail/lexer"
+	"yail/object"
+	"yail/parser"
+)
+
+// RunScript runs a whole program with the engine and prints the value of its last statement, unless it is null.
+// Problems are printed to out as well, and RunScript reports whether the program ran without errors.
+func RunScript(source string, out io.Writer, engine Engine) bool {
+	return RunFile("", source, out, engine)
+}
+
+// RunFile runs a program read from the given file, like RunScript. The modules it imports are searched next to it.
+func RunFile(file, source string, out io.Writer, engine Engine) bool {
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+	program.File = file
+	if len(p.Errors()) != 0 {
+		printParserErrors(out, p.Errors())
+		return false
+	}
+	printParserWarnings(out, p.Diagnostics())
+
+	evaluated := engine.Run(program)
+	if err, ok := evaluated.(*object.Error); ok {
+		printTraceback(out, err)
+		return false
+	}
+	if evaluated != nil && evaluated != object.NULL {
+		io.WriteString(out, evaluated.Inspect())
+		io.WriteString(out, "\n")
+	}
+	return true
+}
@@ -0,0 +1,337 @@
+package resolver
+
+import (
+	"yail/ast"
+	"yail/object"
+)
+
+// Globals tells the resolver about the variables a program can use without declaring them.
+type Globals interface {
+	// IsDeclared reports whether a previous program declared the global variable.
+	IsDeclared(name string) bool
+	// IsBuiltin reports whether the name refers to a builtin function.
+	IsBuiltin(name string) bool
+}
+
+// Resolve computes where each variable of the program is stored, before the program runs.
+// Variables declared inside a function are kept in the slots of its scope, while the variables declared
+// outside of any function are global. As blocks do not create scopes, a variable declared anywhere in a function
+// belongs to the whole function, except in catch blocks, whose parameter and variables belong to the catch block.
+// Before its declaration, the name of a variable refers to the variables of the outer scopes.
+//
+// Resolve also reports the identifiers that are never declared and the variables declared twice in the same scope.
+func Resolve(program *ast.Program, globals Globals) []*object.Error {
+	r := &resolver{globals: globals}
+	r.scope = &scope{globalNames: map[string]bool{}, declared: map[string]bool{}}
+	declareBindings(program, func(name string) { r.scope.globalNames[name] = true })
+	for _, statement := range program.Statements {
+		r.resolveStatement(statement)
+	}
+	return r.errors
+}
+
+type resolver struct {
+	globals Globals
+	scope   *scope
+	errors  []*object.Error
+}
+
+// scope is a function, a catch block, or the program itself for the outermost scope.
+type scope struct {
+	// slots are the variables declared by the function or the catch block, which is nil for the program.
+	slots map[string]int
+	// globalNames are the variables declared by the program, which is nil for functions and catch blocks.
+	globalNames map[string]bool
+	// declared holds the variables that may have been declared at the point being resolved.
+	declared map[string]bool
+	// isCatch is true for a catch block, which hides the variables of the enclosing function with the same names.
+	isCatch  bool
+	numSlots int
+	outer    *scope
+}
+
+func (r *resolver) resolveStatement(node ast.Statement) {
+	switch node := node.(type) {
+	case *ast.ExpressionStatement:
+		r.resolveExpression(node.Expression)
+	case *ast.VariableBindingStatement:
+		r.resolveExpression(node.Value)
+		r.declare(node.Name)
+	case *ast.ReassignmentStatement:
+		r.resolveExpression(node.Value)
+		r.resolveReassignment(node.Name)
+	case *ast.ReturnStatement:
+		r.resolveExpression(node.ReturnValue)
+	case *ast.ThrowStatement:
+		r.resolveExpression(node.Value)
+	case *ast.BlockStatement:
+		r.resolveBlock(node)
+	case *ast.ImportStatement:
+		r.declare(node.Alias)
+	}
+}
+
+func (r *resolver) resolveBlock(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+	for _, statement := range block.Statements {
+		r.resolveStatement(statement)
+	}
+}
+
+func (r *resolver) resolveExpression(node ast.Expression) {
+	switch node := node.(type) {
+	case *ast.IdentifierExpression:
+		r.resolveIdentifier(node)
+	case *ast.TemplateLiteral:
+		r.resolveExpressions(node.Parts)
+	case *ast.PrefixExpression:
+		r.resolveExpression(node.RightNode)
+	case *ast.InfixExpression:
+		r.resolveExpression(node.LeftNode)
+		r.resolveExpression(node.RightNode)
+	case *ast.IfExpression:
+		r.resolveExpression(node.Condition)
+		before := r.scope.declared
+		r.scope.declared = copySet(before)
+		r.resolveBlock(node.Consequence)
+		consequence := r.scope.declared
+		r.scope.declared = copySet(before)
+		r.resolveBlock(node.Alternative)
+		r.scope.declared = union(consequence, r.scope.declared)
+	case *ast.TryExpression:
+		r.resolveTry(node)
+	case *ast.FunctionLiteral:
+		r.resolveFunction(node)
+	case *ast.CallExpression:
+		r.resolveExpression(node.Function)
+		r.resolveExpressions(node.Arguments)
+	case *ast.ArrayLiteral:
+		r.resolveExpressions(node.Elements)
+	case *ast.HashMapLiteral:
+		for _, pair := range node.Pairs {
+			r.resolveExpression(pair.Key)
+			r.resolveExpression(pair.Value)
+		}
+	case *ast.CollectionAccessExpression:
+		r.resolveExpression(node.Left)
+		r.resolveExpression(node.Index)
+	}
+}
+
+func (r *resolver) resolveExpressions(expressions []ast.Expression) {
+	for _, expression := range expressions {
+		r.resolveExpression(expression)
+	}
+}
+
+// resolveTry resolves the blocks of a try expression, where the catch block may run after any part of the try block,
+// and the finally block after any part of both.
+func (r *resolver) resolveTry(node *ast.TryExpression) {
+	r.resolveBlock(node.Block)
+	if node.Catch != nil {
+		catch := &scope{slots: map[string]int{}, declared: map[string]bool{}, isCatch: true, outer: r.scope}
+		node.CatchParameter.Slot = &ast.Slot{Depth: 0, Index: catch.declareSlot(node.CatchParameter.Value)}
+		catch.declared[node.CatchParameter.Value] = true
+		declareBindings(node.Catch, func(name string) {
+			if _, ok := catch.slots[name]; !ok {
+				catch.declareSlot(name)
+			}
+		})
+
+		r.scope = catch
+		r.resolveBlock(node.Catch)
+		r.scope = catch.outer
+		node.NumSlots = catch.numSlots
+	}
+	r.resolveBlock(node.Finally)
+}
+
+func (r *resolver) resolveFunction(node *ast.FunctionLiteral) {
+	function := &scope{slots: map[string]int{}, declared: map[string]bool{}, outer: r.scope}
+	for _, parameter := range node.Parameters {
+		parameter.Slot = nil
+		if _, ok := function.slots[parameter.Value]; ok {
+			continue // the first parameter with a name receives the argument
+		}
+		parameter.Slot = &ast.Slot{Depth: 0, Index: function.declareSlot(parameter.Value)}
+		function.declared[parameter.Value] = true
+	}
+	declareBindings(node.Body, func(name string) {
+		if _, ok := function.slots[name]; !ok {
+			function.declareSlot(name)
+		}
+	})
+
+	r.scope = function
+	r.resolveBlock(node.Body)
+	r.scope = function.outer
+	node.NumSlots = function.numSlots
+}
+
+func (r *resolver) resolveIdentifier(node *ast.IdentifierExpression) {
+	node.Slot = nil
+	depth := 0
+	// sameFunction is true while the scopes looked into belong to the function of the identifier
+	sameFunction := true
+	for s := r.scope; s != nil; s = s.outer {
+		// a variable of the function is not visible before its declaration, where the identifier refers
+		// to the variables of the outer scopes
+		visible := !sameFunction || s.declared[node.Value]
+		if slot, ok := s.slots[node.Value]; ok && visible {
+			node.Slot = &ast.Slot{Depth: depth, Index: slot}
+			return
+		}
+		if s.globalNames[node.Value] && (visible || r.globals.IsDeclared(node.Value)) {
+			return
+		}
+		sameFunction = sameFunction && s.isCatch
+		depth += 1
+	}
+	if !r.globals.IsDeclared(node.Value) && !r.globals.IsBuiltin(node.Value) {
+		r.addError(node, "identifier not found: %s", node.Value)
+	}
+}
+
+// resolveReassignment resolves the variable of a reassignment, which must be declared in the current function,
+// or in the catch blocks the reassignment is in.
+func (r *resolver) resolveReassignment(node *ast.IdentifierExpression) {
+	node.Slot = nil
+	s, depth := r.scope, 0
+	for s.isCatch && !s.has(node.Value) {
+		s, depth = s.outer, depth+1
+	}
+	if slot, ok := s.slots[node.Value]; ok {
+		node.Slot = &ast.Slot{Depth: depth, Index: slot}
+	}
+	isGlobal := s.slots == nil && r.globals.IsDeclared(node.Value)
+	if !s.declared[node.Value] && !isGlobal {
+		r.addError(node, "identifier not found: '%s'", node.Value)
+	}
+}
+
+func (r *resolver) declare(node *ast.IdentifierExpression) {
+	node.Slot = nil
+	isGlobal := r.scope.slots == nil && r.globals.IsDeclared(node.Value)
+	if r.scope.declared[node.Value] || isGlobal {
+		r.addError(node, "given identifier '%s' is already declared", node.Value)
+	}
+	r.scope.declared[node.Value] = true
+	if slot, ok := r.scope.slots[node.Value]; ok {
+		node.Slot = &ast.Slot{Depth: 0, Index: slot}
+	}
+}
+
+func (r *resolver) addError(node *ast.IdentifierExpression, format string, a ...interface{}) {
+	err := object.NewErrorWithKind(object.NAME_ERROR, format, a...)
+	err.Position = node.Token.Position
+	r.errors = append(r.errors, err)
+}
+
+// has reports whether the function or the catch block declares a variable with the given name.
+func (s *scope) has(name string) bool {
+	_, ok := s.slots[name]
+	return ok
+}
+
+func (s *scope) declareSlot(name string) int {
+	slot := s.numSlots
+	s.numSlots += 1
+	s.slots[name] = slot
+	return slot
+}
+
+// DeclaredNames returns the variables declared by a function body, a catch block or a program, in order and without
+// duplicates, looking into blocks but not into nested functions and catch blocks.
+func DeclaredNames(node ast.Node) []string {
+	var names []string
+	seen := map[string]bool{}
+	declareBindings(node, func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	})
+	return names
+}
+
+// declareBindings calls declare for every variable declaration of a function body, a catch block or a program,
+// looking into blocks but not into nested functions and catch blocks.
+func declareBindings(node ast.Node, declare func(name string)) {
+	switch node := node.(type) {
+	case *ast.Program:
+		for _, statement := range node.Statements {
+			declareBindings(statement, declare)
+		}
+	case *ast.BlockStatement:
+		if node == nil {
+			return
+		}
+		for _, statement := range node.Statements {
+			declareBindings(statement, declare)
+		}
+	case *ast.VariableBindingStatement:
+		declareBindings(node.Value, declare)
+		declare(node.Name.Value)
+	case *ast.ReassignmentStatement:
+		declareBindings(node.Value, declare)
+	case *ast.ExpressionStatement:
+		declareBindings(node.Expression, declare)
+	case *ast.ReturnStatement:
+		declareBindings(node.ReturnValue, declare)
+	case *ast.ThrowStatement:
+		declareBindings(node.Value, declare)
+	case *ast.ImportStatement:
+		declare(node.Alias.Value)
+	case *ast.IfExpression:
+		declareBindings(node.Condition, declare)
+		declareBindings(node.Consequence, declare)
+		declareBindings(node.Alternative, declare)
+	case *ast.TryExpression:
+		declareBindings(node.Block, declare)
+		declareBindings(node.Finally, declare)
+	case *ast.PrefixExpression:
+		declareBindings(node.RightNode, declare)
+	case *ast.InfixExpression:
+		declareBindings(node.LeftNode, declare)
+		declareBindings(node.RightNode, declare)
+	case *ast.CallExpression:
+		declareBindings(node.Function, declare)
+		for _, argument := range node.Arguments {
+			declareBindings(argument, declare)
+		}
+	case *ast.ArrayLiteral:
+		for _, element := range node.Elements {
+			declareBindings(element, declare)
+		}
+	case *ast.HashMapLiteral:
+		for _, pair := range node.Pairs {
+			declareBindings(pair.Key, declare)
+			declareBindings(pair.Value, declare)
+		}
+	case *ast.CollectionAccessExpression:
+		declareBindings(node.Left, declare)
+		declareBindings(node.Index, declare)
+	case *ast.TemplateLiteral:
+		for _, part := range node.Parts {
+			declareBindings(part, declare)
+		}
+	}
+}
+
+func copySet(set map[string]bool) map[string]bool {
+	copied := make(map[string]bool, len(set))
+	for name := range set {
+		copied[name] = true
+	}
+	return copied
+}
+
+func union(a, b map[string]bool) map[string]bool {
+	result := copySet(a)
+	for name := range b {
+		result[name] = true
+	}
+	return result
+}
@@ -0,0 +1,160 @@
+package resolver
+
+import (
+	"testing"
+	"yail/ast"
+	"yail/lexer"
+	"yail/parser"
+	"yail/token"
+	"yail/utils"
+)
+
+type globals map[string]bool
+
+func (g globals) IsDeclared(name string) bool {
+	return g[name]
+}
+
+func (g globals) IsBuiltin(name string) bool {
+	return name == "len"
+}
+
+func TestResolveSlots(t *testing.T) {
+	program := parse(t, "val f = func(a, a, b) { if (b) { val c = a }; val g = func() { c + b + f }; g }")
+	utils.ValidateValue(len(Resolve(program, globals{})), 0, t)
+
+	f := program.Statements[0].(*ast.VariableBindingStatement)
+	utils.ValidateValue(f.Name.Slot == nil, true, t)
+	function := f.Value.(*ast.FunctionLiteral)
+	utils.ValidateValue(*function.Parameters[0].Slot, ast.Slot{Depth: 0, Index: 0}, t)
+	utils.ValidateValue(function.Parameters[1].Slot == nil, true, t)
+	utils.ValidateValue(*function.Parameters[2].Slot, ast.Slot{Depth: 0, Index: 1}, t)
+	utils.ValidateValue(function.NumSlots, 4, t)
+
+	g := function.Body.Statements[1].(*ast.VariableBindingStatement)
+	utils.ValidateValue(*g.Name.Slot, ast.Slot{Depth: 0, Index: 3}, t)
+	sum := g.Value.(*ast.FunctionLiteral).Body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.InfixExpression)
+	c := sum.LeftNode.(*ast.InfixExpression).LeftNode.(*ast.IdentifierExpression)
+	b := sum.LeftNode.(*ast.InfixExpression).RightNode.(*ast.IdentifierExpression)
+	utils.ValidateValue(*c.Slot, ast.Slot{Depth: 1, Index: 2}, t)
+	utils.ValidateValue(*b.Slot, ast.Slot{Depth: 1, Index: 1}, t)
+	utils.ValidateValue(sum.RightNode.(*ast.IdentifierExpression).Slot == nil, true, t)
+}
+
+func TestResolveCatchParameter(t *testing.T) {
+	program := parse(t, "val e = 1; try { throw 1 } catch (e) { e }; e")
+	utils.ValidateValue(len(Resolve(program, globals{})), 0, t)
+
+	try := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.TryExpression)
+	utils.ValidateValue(*try.CatchParameter.Slot, ast.Slot{Depth: 0, Index: 0}, t)
+	inside := try.Catch.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IdentifierExpression)
+	utils.ValidateValue(*inside.Slot, ast.Slot{Depth: 0, Index: 0}, t)
+	outside := program.Statements[2].(*ast.ExpressionStatement).Expression.(*ast.IdentifierExpression)
+	utils.ValidateValue(outside.Slot == nil, true, t)
+}
+
+func TestResolveCatchScope(t *testing.T) {
+	program := parse(t, "val f = func(a) { try { throw a } catch (e) { val b = e; a + b } }")
+	utils.ValidateValue(len(Resolve(program, globals{})), 0, t)
+
+	function := program.Statements[0].(*ast.VariableBindingStatement).Value.(*ast.FunctionLiteral)
+	utils.ValidateValue(function.NumSlots, 1, t)
+	try := function.Body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.TryExpression)
+	utils.ValidateValue(try.NumSlots, 2, t)
+	utils.ValidateValue(*try.Catch.Statements[0].(*ast.VariableBindingStatement).Name.Slot, ast.Slot{Depth: 0, Index: 1}, t)
+	sum := try.Catch.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.InfixExpression)
+	utils.ValidateValue(*sum.LeftNode.(*ast.IdentifierExpression).Slot, ast.Slot{Depth: 1, Index: 0}, t)
+	utils.ValidateValue(*sum.RightNode.(*ast.IdentifierExpression).Slot, ast.Slot{Depth: 0, Index: 1}, t)
+}
+
+func TestResolveBeforeDeclaration(t *testing.T) {
+	program := parse(t, "val f = func(x) { val g = func() { val y = x; val x = 2; x } }")
+	utils.ValidateValue(len(Resolve(program, globals{})), 0, t)
+
+	f := program.Statements[0].(*ast.VariableBindingStatement).Value.(*ast.FunctionLiteral)
+	g := f.Body.Statements[0].(*ast.VariableBindingStatement).Value.(*ast.FunctionLiteral)
+	before := g.Body.Statements[0].(*ast.VariableBindingStatement).Value.(*ast.IdentifierExpression)
+	utils.ValidateValue(*before.Slot, ast.Slot{Depth: 1, Index: 0}, t)
+	after := g.Body.Statements[2].(*ast.ExpressionStatement).Expression.(*ast.IdentifierExpression)
+	utils.ValidateValue(*after.Slot, ast.Slot{Depth: 0, Index: 1}, t)
+}
+
+func TestResolveErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		declared globals
+		messages []string
+	}{
+		{"x", globals{}, []string{"identifier not found: x"}},
+		{"len([1]) + x", globals{"x": true}, nil},
+		{"val a = b; val c = d", globals{}, []string{"identifier not found: b", "identifier not found: d"}},
+		{"a; val a = 1", globals{}, []string{"identifier not found: a"}},
+		{"val f = func() { a }; val a = 1", globals{}, nil},
+		{"val f = func() { val y = x; val x = 1 }", globals{}, []string{"identifier not found: x"}},
+		{"val x = 0; val f = func() { val y = x; val x = 1 }", globals{}, nil},
+		{"val a = 1; var a = 2", globals{}, []string{"given identifier 'a' is already declared"}},
+		{"val a = 1", globals{"a": true}, []string{"given identifier 'a' is already declared"}},
+		{"val f = func(a) { val a = 1 }", globals{}, []string{"given identifier 'a' is already declared"}},
+		{"if (true) { val a = 1 } else { val a = 2 }", globals{}, nil},
+		{"if (true) { val a = 1 }; val a = 2", globals{}, []string{"given identifier 'a' is already declared"}},
+		{"try { throw 1 } catch (e) { val e = 1 }", globals{}, []string{"given identifier 'e' is already declared"}},
+		{"try { val a = 1 } catch (e) { val a = 2 }", globals{}, nil},
+		{"try { throw 1 } catch (e) { val a = 2 }; a", globals{}, []string{"identifier not found: a"}},
+		{"var x = 1; try { throw 1 } catch (e) { x = 2; try { 1 } catch (f) { x = 3 } }", globals{}, nil},
+		{"val f = func() { try { 1 } catch (e) { x = 2 }; var x = 1 }", globals{}, []string{"identifier not found: 'x'"}},
+		{"x = 1", globals{}, []string{"identifier not found: 'x'"}},
+		{"x = 1", globals{"x": true}, nil},
+		{"var x = 1; val f = func() { x = 2 }", globals{}, []string{"identifier not found: 'x'"}},
+		{"val f = func() { x = 2 }", globals{"x": true}, []string{"identifier not found: 'x'"}},
+		{"val f = func() { x = 1; var x = 2 }", globals{}, []string{"identifier not found: 'x'"}},
+	}
+
+	for _, tt := range tests {
+		errors := Resolve(parse(t, tt.input), tt.declared)
+		utils.ValidateValue(len(errors), len(tt.messages), t)
+		for i, err := range errors {
+			if i < len(tt.messages) {
+				utils.ValidateValue(err.Message, tt.messages[i], t)
+			}
+		}
+	}
+}
+
+func TestResolveErrorPosition(t *testing.T) {
+	errors := Resolve(parse(t, "val f = func() {\n  1 + missing\n}"), globals{})
+	utils.ValidateValue(len(errors), 1, t)
+	utils.ValidateValue(errors[0].Position, token.Position{Line: 2, Column: 7}, t)
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	utils.ValidateValue(len(p.Errors()), 0, t)
+	return program
+}
+
+func TestScope(t *testing.T) {
+	declared := func(v int) bool { return v > 0 }
+	program := NewScope[int](nil, false)
+	program.Add("x", 1)
+	function := NewScope(program, false)
+	function.Add("x", 0)
+	function.Add("y", 2)
+	catch := NewScope(function, true)
+	catch.Add("e", 3)
+	catch.Add("e", 4)
+
+	v, _ := catch.Lookup("x")
+	utils.ValidateValue(v, 0, t)
+	v, _ = catch.LookupDeclared("x", declared)
+	utils.ValidateValue(v, 1, t)
+	v, _ = catch.LookupInFunction("y")
+	utils.ValidateValue(v, 2, t)
+	_, ok := catch.LookupInFunction("z")
+	utils.ValidateValue(ok, false, t)
+	_, ok = function.LookupInFunction("e")
+	utils.ValidateValue(ok, false, t)
+	utils.ValidateValue(len(catch.Names()), 1, t)
+	v, _ = catch.Get("e")
+	utils.ValidateValue(v, 3, t)
+}
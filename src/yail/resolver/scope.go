@@ -0,0 +1,83 @@
+package resolver
+
+// Scope follows the scopes of a program the way the resolver does, for the passes that keep what they know about
+// each variable, such as its type: a scope is a function, the program itself for the outermost scope, or a catch
+// block. V is what the pass knows about a variable.
+type Scope[V any] struct {
+	Outer *Scope[V]
+	// IsCatch is true for the scope of a catch block, which belongs to the function enclosing it.
+	IsCatch   bool
+	variables map[string]V
+	names     []string
+}
+
+func NewScope[V any](outer *Scope[V], isCatch bool) *Scope[V] {
+	return &Scope[V]{Outer: outer, IsCatch: isCatch, variables: map[string]V{}}
+}
+
+// Add adds a variable to the scope, unless the scope already has a variable with the name.
+func (s *Scope[V]) Add(name string, v V) {
+	if _, ok := s.variables[name]; !ok {
+		s.Set(name, v)
+	}
+}
+
+// Set changes the variable of the scope with the name, adding it if the scope does not have it.
+func (s *Scope[V]) Set(name string, v V) {
+	if _, ok := s.variables[name]; !ok {
+		s.names = append(s.names, name)
+	}
+	s.variables[name] = v
+}
+
+// Get returns the variable of the scope itself with the name.
+func (s *Scope[V]) Get(name string) (V, bool) {
+	v, ok := s.variables[name]
+	return v, ok
+}
+
+// Names returns the names of the variables of the scope, in the order they were added.
+func (s *Scope[V]) Names() []string {
+	return s.names
+}
+
+// Lookup returns the variable a name refers to in the scope or the outer ones.
+func (s *Scope[V]) Lookup(name string) (V, bool) {
+	for ; s != nil; s = s.Outer {
+		if v, ok := s.variables[name]; ok {
+			return v, true
+		}
+	}
+	var none V
+	return none, false
+}
+
+// LookupDeclared returns the variable a name refers to at the point of the program being checked, where declared
+// tells whether the declaration of a variable was met. Like for the resolver, the variables of the current function
+// that are not declared yet are skipped in favour of the variables of the outer scopes.
+func (s *Scope[V]) LookupDeclared(name string, declared func(V) bool) (V, bool) {
+	sameFunction := true
+	for ; s != nil; s = s.Outer {
+		if v, ok := s.variables[name]; ok && (!sameFunction || declared(v)) {
+			return v, true
+		}
+		sameFunction = sameFunction && s.IsCatch
+	}
+	var none V
+	return none, false
+}
+
+// LookupInFunction returns the variable of the current function with the name, which may be in the catch blocks
+// the scope is in. These are the variables a reassignment can change.
+func (s *Scope[V]) LookupInFunction(name string) (V, bool) {
+	for ; s != nil; s = s.Outer {
+		if v, ok := s.variables[name]; ok {
+			return v, true
+		}
+		if !s.IsCatch {
+			break
+		}
+	}
+	var none V
+	return none, false
+}
@@ -0,0 +1,468 @@
+package stdlib
+
+import (
+	"math"
+	"yail/object"
+)
+
+const ARRAYS = "arrays"
+
+var _ = register(&Module{
+	Name: ARRAYS,
+	Doc:  "Creation, slicing, transformation and sorting of arrays.",
+	Functions: []*Function{
+		{Name: "range", MinArgs: 1, MaxArgs: 3, Doc: "range(start, end, step) returns the integers from start, included, to end, excluded, " +
+			"increasing by step. start is 0 and step is 1 when they are not given, as in range(end).", Fn: integerRange},
+		{Name: "concat", MinArgs: 0, MaxArgs: object.VARIADIC, Doc: "concat(arrays...) returns a new array with the elements of all the arrays.", Fn: concat},
+		{Name: "slice", MinArgs: 2, MaxArgs: 3, Doc: "slice(array, start, end) returns a new array with the elements from start, included, " +
+			"to end, excluded, or to the end of the array when end is not given. Negative indices count from the end.", Fn: slice},
+		{Name: "map", MinArgs: 2, MaxArgs: 2, Doc: "map(array, f) returns a new array with the results of f called with each element.", Fn: mapElements},
+		{Name: "filter", MinArgs: 2, MaxArgs: 2, Doc: "filter(array, predicate) returns a new array with the elements for which predicate returns true.", Fn: filter},
+		{Name: "reduce", MinArgs: 2, MaxArgs: 3, Doc: "reduce(array, f, initial) calls f with the result so far and each element, starting with initial, " +
+			"or with the first element when initial is not given, and returns the last result.", Fn: reduce},
+		{Name: "forEach", MinArgs: 2, MaxArgs: 2, Doc: "forEach(array, f) calls f with each element, and returns null.", Fn: forEach},
+		{Name: "any", MinArgs: 2, MaxArgs: 2, Doc: "any(array, predicate) returns whether predicate returns true for an element, stopping at the first one.", Fn: anyElement},
+		{Name: "all", MinArgs: 2, MaxArgs: 2, Doc: "all(array, predicate) returns whether predicate returns true for every element, stopping at the first false.", Fn: allElements},
+		{Name: "find", MinArgs: 2, MaxArgs: 2, Doc: "find(array, predicate) returns the first element for which predicate returns true, or null.", Fn: find},
+		{Name: "flatMap", MinArgs: 2, MaxArgs: 2, Doc: "flatMap(array, f) returns a new array with the elements of the arrays returned by f called with each element.", Fn: flatMap},
+		{Name: "zip", MinArgs: 2, MaxArgs: object.VARIADIC, Doc: "zip(arrays...) returns the arrays of the elements at the same index in each array, " +
+			"as long as the shortest array.", Fn: zip},
+		{Name: "enumerate", MinArgs: 1, MaxArgs: 1, Doc: "enumerate(array) returns the [index, element] arrays of the elements.", Fn: enumerate},
+		{Name: "groupBy", MinArgs: 2, MaxArgs: 2, Doc: "groupBy(array, key) returns a hash map from the results of key called with each element " +
+			"to the arrays of the elements giving them, in the order of their first elements.", Fn: groupBy},
+		{Name: "chunk", MinArgs: 2, MaxArgs: 2, Doc: "chunk(array, size) splits the array into arrays of size elements, the last one holding what is left.", Fn: chunk},
+		{Name: "distinct", MinArgs: 1, MaxArgs: 1, Doc: "distinct(array) returns a new array without the elements equal to a previous one.", Fn: distinct},
+		{Name: "flatten", MinArgs: 1, MaxArgs: 2, Doc: "flatten(array, depth) replaces the arrays in the array by their elements, " +
+			"down to depth levels of nesting, or one level when depth is not given.", Fn: flatten},
+		{Name: "sort", MinArgs: 1, MaxArgs: 1, Doc: "sort(array) returns a new array with the elements in ascending order. Integers come before strings, " +
+			"and strings before arrays, which are compared element by element. The sort is stable.", Fn: sorting(false, sortNatural)},
+		{Name: "sortBy", MinArgs: 2, MaxArgs: 2, Doc: "sortBy(array, key) returns a new array with the elements in the ascending order of the results " +
+			"of key called once with each element, compared like the elements of sort.", Fn: sorting(false, sortByKey)},
+		{Name: "sortWith", MinArgs: 2, MaxArgs: 2, Doc: "sortWith(array, comparator) returns a new array with the elements in the order of comparator, " +
+			"which returns a negative integer, zero or a positive integer as its first argument comes before, with or after the second one.", Fn: sorting(false, sortWithComparator)},
+		{Name: "reverse", MinArgs: 1, MaxArgs: 1, Doc: "reverse(array) returns a new array with the elements in reverse order.", Fn: sorting(false, reverseElements)},
+		{Name: "sortInPlace", MinArgs: 1, MaxArgs: 1, Doc: "sortInPlace(array) sorts the elements of the array like sort, and returns the array.", Fn: sorting(true, sortNatural)},
+		{Name: "sortByInPlace", MinArgs: 2, MaxArgs: 2, Doc: "sortByInPlace(array, key) sorts the elements of the array like sortBy, and returns the array.", Fn: sorting(true, sortByKey)},
+		{Name: "sortWithInPlace", MinArgs: 2, MaxArgs: 2, Doc: "sortWithInPlace(array, comparator) sorts the elements of the array like sortWith, and returns the array.", Fn: sorting(true, sortWithComparator)},
+		{Name: "reverseInPlace", MinArgs: 1, MaxArgs: 1, Doc: "reverseInPlace(array) reverses the order of the elements of the array, and returns the array.", Fn: sorting(true, reverseElements)},
+		{Name: "min", MinArgs: 1, MaxArgs: 1, Doc: "min(array) returns the first of the smallest elements, compared like the elements of sort.", Fn: extreme(-1, false)},
+		{Name: "max", MinArgs: 1, MaxArgs: 1, Doc: "max(array) returns the first of the largest elements, compared like the elements of sort.", Fn: extreme(1, false)},
+		{Name: "minBy", MinArgs: 2, MaxArgs: 2, Doc: "minBy(array, key) returns the first element with the smallest result of key.", Fn: extreme(-1, true)},
+		{Name: "maxBy", MinArgs: 2, MaxArgs: 2, Doc: "maxBy(array, key) returns the first element with the largest result of key.", Fn: extreme(1, true)},
+	},
+})
+
+func integerRange(call *Call) object.Object {
+	var bounds []int64
+	for i := range call.Args {
+		value, err := call.Integer(i)
+		if err != nil {
+			return err
+		}
+		bounds = append(bounds, value)
+	}
+	start, end, step := int64(0), bounds[0], int64(1)
+	if len(bounds) > 1 {
+		start, end = bounds[0], bounds[1]
+	}
+	if len(bounds) > 2 {
+		step = bounds[2]
+	}
+	if step == 0 {
+		return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: step must not be zero", call.Name)
+	}
+	elements := []object.Object{}
+	for n := start; step > 0 && n < end || step < 0 && n > end; n += step {
+		if err := call.Step(); err != nil {
+			return err
+		}
+		if err := call.Allocate(); err != nil {
+			return err
+		}
+		elements = append(elements, object.NewInteger(n))
+		// the next element would overflow, so it is past the end
+		if step > 0 && n > math.MaxInt64-step || step < 0 && n < math.MinInt64-step {
+			break
+		}
+	}
+	return object.NewArray(elements)
+}
+
+func concat(call *Call) object.Object {
+	elements := []object.Object{}
+	for i := range call.Args {
+		array, err := call.Array(i)
+		if err != nil {
+			return err
+		}
+		elements = append(elements, array.Elements...)
+	}
+	return object.NewArray(elements)
+}
+
+func slice(call *Call) object.Object {
+	array, err := call.Array(0)
+	if err != nil {
+		return err
+	}
+	length := int64(len(array.Elements))
+	start, err := call.Integer(1)
+	if err != nil {
+		return err
+	}
+	end := length
+	if len(call.Args) == 3 {
+		if end, err = call.Integer(2); err != nil {
+			return err
+		}
+	}
+	start, end = clampIndex(start, length), clampIndex(end, length)
+	elements := []object.Object{}
+	if start < end {
+		elements = append(elements, array.Elements[start:end]...)
+	}
+	return object.NewArray(elements)
+}
+
+// clampIndex turns a negative index into an index from the end, and keeps it within the bounds of the array.
+func clampIndex(index, length int64) int64 {
+	if index < 0 {
+		index += length
+	}
+	if index < 0 {
+		return 0
+	}
+	if index > length {
+		return length
+	}
+	return index
+}
+
+// arrayAndFunction returns the array and the function that the higher-order functions are given.
+func arrayAndFunction(call *Call) (*object.Array, object.Object, *object.Error) {
+	array, err := call.Array(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	fn, err := call.Function(1)
+	if err != nil {
+		return nil, nil, err
+	}
+	return array, fn, nil
+}
+
+// callFunction calls the function, returning its result apart from the error it raised.
+func callFunction(call *Call, fn object.Object, args ...object.Object) (object.Object, *object.Error) {
+	result := call.Apply(fn, args...)
+	if err, ok := result.(*object.Error); ok {
+		return nil, err
+	}
+	return result, nil
+}
+
+// test calls a predicate, which must return a boolean.
+func test(call *Call, predicate, element object.Object) (bool, *object.Error) {
+	result, err := callFunction(call, predicate, element)
+	if err != nil {
+		return false, err
+	}
+	boolean, ok := result.(*object.Boolean)
+	if !ok {
+		return false, object.NewErrorWithKind(object.TYPE_ERROR, "%s: the predicate returned %s instead of a boolean", call.Name, result.Type())
+	}
+	return boolean.Value, nil
+}
+
+func mapElements(call *Call) object.Object {
+	array, fn, err := arrayAndFunction(call)
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, 0, len(array.Elements))
+	for _, element := range array.Elements {
+		result, err := callFunction(call, fn, element)
+		if err != nil {
+			return err
+		}
+		elements = append(elements, result)
+	}
+	return object.NewArray(elements)
+}
+
+func filter(call *Call) object.Object {
+	array, predicate, err := arrayAndFunction(call)
+	if err != nil {
+		return err
+	}
+	elements := []object.Object{}
+	for _, element := range array.Elements {
+		keep, err := test(call, predicate, element)
+		if err != nil {
+			return err
+		}
+		if keep {
+			elements = append(elements, element)
+		}
+	}
+	return object.NewArray(elements)
+}
+
+func reduce(call *Call) object.Object {
+	array, fn, err := arrayAndFunction(call)
+	if err != nil {
+		return err
+	}
+	elements := array.Elements
+	var result object.Object
+	if len(call.Args) == 3 {
+		result = call.Args[2]
+	} else if len(elements) > 0 {
+		result, elements = elements[0], elements[1:]
+	} else {
+		return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: empty array without initial value", call.Name)
+	}
+	for _, element := range elements {
+		if result, err = callFunction(call, fn, result, element); err != nil {
+			return err
+		}
+	}
+	return result
+}
+
+func forEach(call *Call) object.Object {
+	array, fn, err := arrayAndFunction(call)
+	if err != nil {
+		return err
+	}
+	for _, element := range array.Elements {
+		if _, err := callFunction(call, fn, element); err != nil {
+			return err
+		}
+	}
+	return object.NULL
+}
+
+func anyElement(call *Call) object.Object {
+	index, err := findIndex(call)
+	if err != nil {
+		return err
+	}
+	return object.GetPooledBooleanObject(index >= 0)
+}
+
+func allElements(call *Call) object.Object {
+	array, predicate, err := arrayAndFunction(call)
+	if err != nil {
+		return err
+	}
+	for _, element := range array.Elements {
+		ok, err := test(call, predicate, element)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return object.FALSE
+		}
+	}
+	return object.TRUE
+}
+
+func find(call *Call) object.Object {
+	index, err := findIndex(call)
+	if err != nil {
+		return err
+	}
+	if index < 0 {
+		return object.NULL
+	}
+	return call.Args[0].(*object.Array).Elements[index]
+}
+
+// findIndex returns the index of the first element for which the predicate returns true, or -1.
+func findIndex(call *Call) (int, *object.Error) {
+	array, predicate, err := arrayAndFunction(call)
+	if err != nil {
+		return 0, err
+	}
+	for i, element := range array.Elements {
+		ok, err := test(call, predicate, element)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+func flatMap(call *Call) object.Object {
+	array, fn, err := arrayAndFunction(call)
+	if err != nil {
+		return err
+	}
+	elements := []object.Object{}
+	for _, element := range array.Elements {
+		result, err := callFunction(call, fn, element)
+		if err != nil {
+			return err
+		}
+		mapped, ok := result.(*object.Array)
+		if !ok {
+			return object.NewErrorWithKind(object.TYPE_ERROR, "%s: the function returned %s instead of an array", call.Name, result.Type())
+		}
+		elements = append(elements, mapped.Elements...)
+	}
+	return object.NewArray(elements)
+}
+
+func zip(call *Call) object.Object {
+	var arrays []*object.Array
+	length := -1
+	for i := range call.Args {
+		array, err := call.Array(i)
+		if err != nil {
+			return err
+		}
+		arrays = append(arrays, array)
+		if length < 0 || len(array.Elements) < length {
+			length = len(array.Elements)
+		}
+	}
+	elements := make([]object.Object, 0, length)
+	for i := 0; i < length; i++ {
+		tuple := make([]object.Object, 0, len(arrays))
+		for _, array := range arrays {
+			tuple = append(tuple, array.Elements[i])
+		}
+		elements = append(elements, object.NewArray(tuple))
+	}
+	return object.NewArray(elements)
+}
+
+func enumerate(call *Call) object.Object {
+	array, err := call.Array(0)
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, 0, len(array.Elements))
+	for i, element := range array.Elements {
+		elements = append(elements, object.NewArray([]object.Object{object.NewInteger(int64(i)), element}))
+	}
+	return object.NewArray(elements)
+}
+
+func groupBy(call *Call) object.Object {
+	array, fn, err := arrayAndFunction(call)
+	if err != nil {
+		return err
+	}
+	groups := object.NewHashMap()
+	for _, element := range array.Elements {
+		result, err := callFunction(call, fn, element)
+		if err != nil {
+			return err
+		}
+		key, err := call.key(result)
+		if err != nil {
+			return err
+		}
+		pair, ok := groups.Get(key)
+		if err := object.KeyError(key); err != nil {
+			return err
+		}
+		if !ok {
+			groups.Set(key, object.NewArray(nil))
+			pair, _ = groups.Get(key)
+		}
+		group := pair.Value.(*object.Array)
+		group.Elements = append(group.Elements, element)
+	}
+	return groups
+}
+
+func chunk(call *Call) object.Object {
+	array, err := call.Array(0)
+	if err != nil {
+		return err
+	}
+	size, err := call.Integer(1)
+	if err != nil {
+		return err
+	}
+	if size <= 0 {
+		return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: size must be positive, but is %d", call.Name, size)
+	}
+	chunks := []object.Object{}
+	for start := int64(0); start < int64(len(array.Elements)); start += size {
+		end := start + size
+		if end > int64(len(array.Elements)) {
+			end = int64(len(array.Elements))
+		}
+		chunks = append(chunks, object.NewArray(append([]object.Object{}, array.Elements[start:end]...)))
+	}
+	return object.NewArray(chunks)
+}
+
+func distinct(call *Call) object.Object {
+	array, err := call.Array(0)
+	if err != nil {
+		return err
+	}
+	seen := object.NewHashMap()
+	// the values that can not be hash keys are only equal to themselves
+	identities := map[object.Object]bool{}
+	elements := []object.Object{}
+	for _, element := range array.Elements {
+		if !object.IsKey(element) {
+			if !identities[element] {
+				identities[element] = true
+				elements = append(elements, element)
+			}
+			continue
+		}
+		key, err := call.key(element)
+		if err != nil {
+			return err
+		}
+		_, ok := seen.Get(key)
+		if err := object.KeyError(key); err != nil {
+			return err
+		}
+		if !ok {
+			if err := set(seen, key, object.TRUE); err != nil {
+				return err
+			}
+			elements = append(elements, element)
+		}
+	}
+	return object.NewArray(elements)
+}
+
+func flatten(call *Call) object.Object {
+	array, err := call.Array(0)
+	if err != nil {
+		return err
+	}
+	depth := int64(1)
+	if len(call.Args) == 2 {
+		if depth, err = call.Integer(1); err != nil {
+			return err
+		}
+		if depth < 0 {
+			return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: depth must not be negative, but is %d", call.Name, depth)
+		}
+	}
+	return object.NewArray(flattenElements(array.Elements, depth, []object.Object{}))
+}
+
+func flattenElements(elements []object.Object, depth int64, flattened []object.Object) []object.Object {
+	for _, element := range elements {
+		if nested, ok := element.(*object.Array); ok && depth > 0 {
+			flattened = flattenElements(nested.Elements, depth-1, flattened)
+		} else {
+			flattened = append(flattened, element)
+		}
+	}
+	return flattened
+}
@@ -0,0 +1,67 @@
+package stdlib
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"yail/object"
+)
+
+const IO = "io"
+
+var _ = register(&Module{
+	Name: IO,
+	Doc:  "Reading and writing the standard streams.",
+	Functions: []*Function{
+		{Name: "print", MinArgs: 0, MaxArgs: object.VARIADIC, Doc: "print(values...) writes the values separated by spaces.", Fn: printValues},
+		{Name: "println", MinArgs: 0, MaxArgs: object.VARIADIC, Doc: "println(values...) writes the values separated by spaces, and a new line.", Fn: printLine},
+		{Name: "readLine", MinArgs: 0, MaxArgs: 0, Doc: "readLine() returns the next line read, without its end, or null at the end of the input.", Fn: readLine},
+	},
+})
+
+// Stdout and Stdin are the streams of the io module, which embedders can replace.
+var (
+	Stdout io.Writer = os.Stdout
+	Stdin  io.Reader = os.Stdin
+)
+
+// stdin buffers Stdin, and is replaced when Stdin is.
+var stdin struct {
+	source io.Reader
+	reader *bufio.Reader
+}
+
+func printValues(call *Call) object.Object {
+	return write(call, "")
+}
+
+func printLine(call *Call) object.Object {
+	return write(call, "\n")
+}
+
+func write(call *Call, end string) object.Object {
+	var values []string
+	for _, arg := range call.Args {
+		values = append(values, object.ToString(arg))
+	}
+	if _, err := io.WriteString(Stdout, strings.Join(values, " ")+end); err != nil {
+		return object.NewError("%s: %s", call.Name, err)
+	}
+	return object.NULL
+}
+
+func readLine(call *Call) object.Object {
+	if stdin.source != Stdin {
+		stdin.source, stdin.reader = Stdin, bufio.NewReader(Stdin)
+	}
+	line, err := stdin.reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return object.NewError("%s: %s", call.Name, err)
+	}
+	if err == io.EOF && line == "" {
+		return object.NULL
+	}
+	line = strings.TrimSuffix(line, "\n")
+	return object.NewString(strings.TrimSuffix(line, "\r"))
+}
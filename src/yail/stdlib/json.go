@@ -0,0 +1,176 @@
+package stdlib
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"yail/object"
+)
+
+const JSON = "json"
+
+var _ = register(&Module{
+	Name: JSON,
+	Doc:  "Conversions between values and JSON text.",
+	Functions: []*Function{
+		{Name: "stringify", MinArgs: 1, MaxArgs: 1, Doc: "stringify(value) returns the JSON text of value, which may hold integers, strings, " +
+			"booleans, null, arrays and hash maps. The keys of hash maps are converted to strings.", Fn: stringify},
+		{Name: "parse", MinArgs: 1, MaxArgs: 1, Doc: "parse(text) returns the value of the JSON text. Objects become hash maps with string keys, " +
+			"and numbers must be integers.", Fn: parseJSON},
+	},
+})
+
+func stringify(call *Call) object.Object {
+	var out strings.Builder
+	if err := writeJSON(call, &out, call.Args[0], map[object.Object]bool{}); err != nil {
+		return err
+	}
+	return object.NewString(out.String())
+}
+
+// writeJSON writes the JSON text of the value, where visiting holds the collections the value is part of.
+func writeJSON(call *Call, out *strings.Builder, value object.Object, visiting map[object.Object]bool) *object.Error {
+	switch value := value.(type) {
+	case *object.Integer:
+		out.WriteString(strconv.FormatInt(value.Value, 10))
+	case *object.String:
+		writeJSONString(out, value.Value)
+	case *object.Boolean:
+		out.WriteString(value.Inspect())
+	case *object.Null:
+		out.WriteString("null")
+	case *object.Array:
+		if visiting[value] {
+			return cyclicValueError(call)
+		}
+		visiting[value] = true
+		defer delete(visiting, value)
+		return writeJSONArray(call, out, value.Elements, visiting)
+	case *object.Tuple:
+		return writeJSONArray(call, out, value.Elements, visiting)
+	case *object.HashMap:
+		if visiting[value] {
+			return cyclicValueError(call)
+		}
+		visiting[value] = true
+		defer delete(visiting, value)
+		out.WriteString("{")
+		for i, pair := range value.Pairs() {
+			if i > 0 {
+				out.WriteString(",")
+			}
+			writeJSONString(out, object.ToString(pair.Key))
+			out.WriteString(":")
+			if err := writeJSON(call, out, pair.Value, visiting); err != nil {
+				return err
+			}
+		}
+		out.WriteString("}")
+	default:
+		return object.NewErrorWithKind(object.TYPE_ERROR, object.INVALID_TYPE_EXCEPTION_MESSAGE, call.Name, value.Type())
+	}
+	return nil
+}
+
+func writeJSONArray(call *Call, out *strings.Builder, elements []object.Object, visiting map[object.Object]bool) *object.Error {
+	out.WriteString("[")
+	for i, element := range elements {
+		if i > 0 {
+			out.WriteString(",")
+		}
+		if err := writeJSON(call, out, element, visiting); err != nil {
+			return err
+		}
+	}
+	out.WriteString("]")
+	return nil
+}
+
+func writeJSONString(out *strings.Builder, s string) {
+	encoded, _ := json.Marshal(s) // strings are always encoded
+	out.Write(encoded)
+}
+
+func cyclicValueError(call *Call) *object.Error {
+	return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: the value contains itself", call.Name)
+}
+
+func parseJSON(call *Call) object.Object {
+	text, err := call.String(0)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	value, err := readJSON(call, decoder)
+	if err != nil {
+		return err
+	}
+	if _, tokenErr := decoder.Token(); tokenErr != io.EOF {
+		return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: invalid JSON: unexpected text after the value", call.Name)
+	}
+	return value
+}
+
+// readJSON reads the next value from the tokens of the decoder, keeping the keys of objects in order.
+func readJSON(call *Call, decoder *json.Decoder) (object.Object, *object.Error) {
+	token, err := decoder.Token()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: invalid JSON: %s", call.Name, err)
+	}
+	switch token := token.(type) {
+	case nil:
+		return object.NULL, nil
+	case bool:
+		return object.GetPooledBooleanObject(token), nil
+	case string:
+		return object.NewString(token), nil
+	case json.Number:
+		n, err := token.Int64()
+		if err != nil {
+			return nil, object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: unsupported number %s, only integers are supported", call.Name, token)
+		}
+		return object.NewInteger(n), nil
+	case json.Delim:
+		if token == '[' {
+			elements := []object.Object{}
+			for decoder.More() {
+				element, err := readJSON(call, decoder)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, element)
+			}
+			return object.NewArray(elements), readJSONEnd(call, decoder)
+		}
+		hashMap := object.NewHashMap()
+		for decoder.More() {
+			key, err := readJSON(call, decoder) // the decoder only gives strings as keys
+			if err != nil {
+				return nil, err
+			}
+			value, err := readJSON(call, decoder)
+			if err != nil {
+				return nil, err
+			}
+			hashMap.Set(key.(*object.String), value)
+		}
+		return hashMap, readJSONEnd(call, decoder)
+	}
+	return nil, object.NewError("%s: unexpected JSON token %v", call.Name, token)
+}
+
+// readJSONEnd reads the end of an array or object.
+func readJSONEnd(call *Call, decoder *json.Decoder) *object.Error {
+	if _, err := decoder.Token(); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: invalid JSON: %s", call.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package stdlib
+
+import "yail/object"
+
+const MAPS = "maps"
+
+var _ = register(&Module{
+	Name: MAPS,
+	Doc:  "Creation, inspection and transformation of hash maps. Pairs are listed in the order their keys were added.",
+	Functions: []*Function{
+		{Name: "fromEntries", MinArgs: 1, MaxArgs: 1, Doc: "fromEntries(entries) returns a hash map with the [key, value] arrays of entries. " +
+			"A key given twice keeps its last value.", Fn: fromEntries},
+		{Name: "fromKeys", MinArgs: 2, MaxArgs: 2, Doc: "fromKeys(keys, value) returns a hash map giving value to each of the keys.", Fn: fromKeys},
+		{Name: "keys", MinArgs: 1, MaxArgs: 1, Doc: "keys(map) returns the keys of the hash map.", Fn: listPairs(keysOnly)},
+		{Name: "values", MinArgs: 1, MaxArgs: 1, Doc: "values(map) returns the values of the hash map.", Fn: listPairs(valuesOnly)},
+		{Name: "entries", MinArgs: 1, MaxArgs: 1, Doc: "entries(map) returns the [key, value] arrays of the hash map.", Fn: listPairs(entry)},
+		{Name: "has", MinArgs: 2, MaxArgs: 2, Doc: "has(map, key) returns whether the hash map holds key.", Fn: has},
+		{Name: "getOrDefault", MinArgs: 3, MaxArgs: 3, Doc: "getOrDefault(map, key, default) returns the value of key, or default when the hash map " +
+			"does not hold key.", Fn: getOrDefault},
+		{Name: "delete", MinArgs: 2, MaxArgs: 2, Doc: "delete(map, key) removes key from the hash map, and returns whether it held key.", Fn: deleteKey},
+		{Name: "merge", MinArgs: 1, MaxArgs: object.VARIADIC, Doc: "merge(maps...) returns a new hash map with the pairs of all the hash maps. " +
+			"A key held by several of them keeps the value of the last one.", Fn: merge},
+		{Name: "mapValues", MinArgs: 2, MaxArgs: 2, Doc: "mapValues(map, f) returns a new hash map with the results of f called with each value.", Fn: mapValues},
+		{Name: "filterKeys", MinArgs: 2, MaxArgs: 2, Doc: "filterKeys(map, predicate) returns a new hash map with the pairs whose keys " +
+			"predicate returns true for.", Fn: filterKeys},
+	},
+})
+
+func fromEntries(call *Call) object.Object {
+	entries, err := call.Array(0)
+	if err != nil {
+		return err
+	}
+	hashMap := object.NewHashMap()
+	for _, entry := range entries.Elements {
+		pair, ok := entry.(*object.Array)
+		if !ok || len(pair.Elements) != 2 {
+			return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: entry %s is not a [key, value] array", call.Name, entry.Inspect())
+		}
+		key, err := call.key(pair.Elements[0])
+		if err != nil {
+			return err
+		}
+		if err := set(hashMap, key, pair.Elements[1]); err != nil {
+			return err
+		}
+	}
+	return hashMap
+}
+
+func fromKeys(call *Call) object.Object {
+	keys, err := call.Array(0)
+	if err != nil {
+		return err
+	}
+	hashMap := object.NewHashMap()
+	for _, element := range keys.Elements {
+		key, err := call.key(element)
+		if err != nil {
+			return err
+		}
+		if err := set(hashMap, key, call.Args[1]); err != nil {
+			return err
+		}
+	}
+	return hashMap
+}
+
+func keysOnly(pair *object.HashPair) object.Object {
+	return pair.Key
+}
+
+func valuesOnly(pair *object.HashPair) object.Object {
+	return pair.Value
+}
+
+func entry(pair *object.HashPair) object.Object {
+	return object.NewArray([]object.Object{pair.Key, pair.Value})
+}
+
+// listPairs returns the function of a module returning an array with the result of element for each pair.
+func listPairs(element func(pair *object.HashPair) object.Object) func(call *Call) object.Object {
+	return func(call *Call) object.Object {
+		hashMap, err := call.HashMap(0)
+		if err != nil {
+			return err
+		}
+		elements := []object.Object{}
+		for _, pair := range hashMap.Pairs() {
+			elements = append(elements, element(pair))
+		}
+		return object.NewArray(elements)
+	}
+}
+
+// lookup returns the hash map and the key given as second argument, with its pair, which is nil when it is missing.
+func lookup(call *Call) (*object.HashMap, object.Hashable, *object.HashPair, *object.Error) {
+	hashMap, err := call.HashMap(0)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	key, err := call.key(call.Args[1])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	pair, _ := hashMap.Get(key)
+	return hashMap, key, pair, object.KeyError(key)
+}
+
+func has(call *Call) object.Object {
+	_, _, pair, err := lookup(call)
+	if err != nil {
+		return err
+	}
+	return object.GetPooledBooleanObject(pair != nil)
+}
+
+func getOrDefault(call *Call) object.Object {
+	_, _, pair, err := lookup(call)
+	if err != nil {
+		return err
+	}
+	if pair == nil {
+		return call.Args[2]
+	}
+	return pair.Value
+}
+
+func deleteKey(call *Call) object.Object {
+	hashMap, key, pair, err := lookup(call)
+	if err != nil {
+		return err
+	}
+	if pair != nil {
+		hashMap.Delete(key)
+	}
+	return object.GetPooledBooleanObject(pair != nil)
+}
+
+func merge(call *Call) object.Object {
+	merged := object.NewHashMap()
+	for i := range call.Args {
+		hashMap, err := call.HashMap(i)
+		if err != nil {
+			return err
+		}
+		for _, pair := range hashMap.Pairs() {
+			if err := set(merged, pair.HashableKey(), pair.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return merged
+}
+
+func mapValues(call *Call) object.Object {
+	hashMap, err := call.HashMap(0)
+	if err != nil {
+		return err
+	}
+	fn, err := call.Function(1)
+	if err != nil {
+		return err
+	}
+	mapped := object.NewHashMap()
+	for _, pair := range hashMap.Pairs() {
+		value, err := callFunction(call, fn, pair.Value)
+		if err != nil {
+			return err
+		}
+		mapped.Set(pair.HashableKey(), value)
+	}
+	return mapped
+}
+
+func filterKeys(call *Call) object.Object {
+	hashMap, err := call.HashMap(0)
+	if err != nil {
+		return err
+	}
+	predicate, err := call.Function(1)
+	if err != nil {
+		return err
+	}
+	filtered := object.NewHashMap()
+	for _, pair := range hashMap.Pairs() {
+		keep, err := test(call, predicate, pair.Key)
+		if err != nil {
+			return err
+		}
+		if keep {
+			filtered.Set(pair.HashableKey(), pair.Value)
+		}
+	}
+	return filtered
+}
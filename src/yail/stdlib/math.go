@@ -0,0 +1,113 @@
+package stdlib
+
+import (
+	"math"
+	"yail/object"
+)
+
+const MATH = "math"
+
+var _ = register(&Module{
+	Name: MATH,
+	Doc:  "Arithmetic on integers.",
+	Functions: []*Function{
+		{Name: "abs", MinArgs: 1, MaxArgs: 1, Doc: "abs(n) returns n without its sign.", Fn: abs},
+		{Name: "sign", MinArgs: 1, MaxArgs: 1, Doc: "sign(n) returns -1, 0 or 1 when n is negative, zero or positive.", Fn: sign},
+		{Name: "clamp", MinArgs: 3, MaxArgs: 3, Doc: "clamp(n, low, high) returns the integer between low and high closest to n.", Fn: clamp},
+		{Name: "gcd", MinArgs: 2, MaxArgs: 2, Doc: "gcd(a, b) returns the greatest common divisor of a and b, which is never negative.", Fn: gcd},
+		{Name: "sqrt", MinArgs: 1, MaxArgs: 1, Doc: "sqrt(n) returns the square root of n rounded down.", Fn: sqrt},
+	},
+})
+
+func abs(call *Call) object.Object {
+	n, err := call.Integer(0)
+	if err != nil {
+		return err
+	}
+	if n == math.MinInt64 {
+		return object.NewErrorWithKind(object.ARITHMETIC_ERROR, "integer overflow: %s(%d)", call.Name, n)
+	}
+	if n < 0 {
+		n = -n
+	}
+	return object.NewInteger(n)
+}
+
+func sign(call *Call) object.Object {
+	n, err := call.Integer(0)
+	if err != nil {
+		return err
+	}
+	switch {
+	case n < 0:
+		return object.NewInteger(-1)
+	case n > 0:
+		return object.NewInteger(1)
+	default:
+		return object.NewInteger(0)
+	}
+}
+
+func clamp(call *Call) object.Object {
+	var bounds [3]int64
+	for i := range bounds {
+		value, err := call.Integer(i)
+		if err != nil {
+			return err
+		}
+		bounds[i] = value
+	}
+	n, low, high := bounds[0], bounds[1], bounds[2]
+	switch {
+	case low > high:
+		return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: low bound %d is greater than high bound %d", call.Name, low, high)
+	case n < low:
+		return object.NewInteger(low)
+	case n > high:
+		return object.NewInteger(high)
+	default:
+		return object.NewInteger(n)
+	}
+}
+
+func gcd(call *Call) object.Object {
+	a, err := call.Integer(0)
+	if err != nil {
+		return err
+	}
+	b, err := call.Integer(1)
+	if err != nil {
+		return err
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a == math.MinInt64 {
+		return object.NewErrorWithKind(object.ARITHMETIC_ERROR, "integer overflow: %s(%s, %s)", call.Name, call.Args[0].Inspect(), call.Args[1].Inspect())
+	}
+	if a < 0 {
+		a = -a
+	}
+	return object.NewInteger(a)
+}
+
+func sqrt(call *Call) object.Object {
+	n, err := call.Integer(0)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return object.NewErrorWithKind(object.ARITHMETIC_ERROR, "square root of a negative number: %d", n)
+	}
+	// binary search of the largest root whose square does not exceed n, without overflowing
+	low, high := int64(0), int64(3037000499) // the square root of the largest integer, rounded down
+	for low < high {
+		middle := low + (high-low+1)/2
+		if middle*middle <= n {
+			low = middle
+		} else {
+			high = middle - 1
+		}
+	}
+	return object.NewInteger(low)
+}
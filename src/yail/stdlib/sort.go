@@ -0,0 +1,238 @@
+package stdlib
+
+import (
+	"sort"
+	"yail/object"
+)
+
+// compare orders integers before strings, and strings before arrays. Integers are compared by value, strings
+// byte by byte, and arrays element by element, a shorter array coming first when it is the start of the other.
+// It returns false when the values are of other types, which can not be ordered.
+func compare(a, b object.Object) (int, bool) {
+	rankA, ok := rank(a)
+	if !ok {
+		return 0, false
+	}
+	rankB, ok := rank(b)
+	if !ok {
+		return 0, false
+	}
+	if rankA != rankB {
+		return rankA - rankB, true
+	}
+	switch a := a.(type) {
+	case *object.Integer:
+		return order(a.Value < b.(*object.Integer).Value, a.Value > b.(*object.Integer).Value), true
+	case *object.String:
+		return order(a.Value < b.(*object.String).Value, a.Value > b.(*object.String).Value), true
+	}
+	elementsA, elementsB := a.(*object.Array).Elements, b.(*object.Array).Elements
+	for i := 0; i < len(elementsA) && i < len(elementsB); i++ {
+		if result, ok := compare(elementsA[i], elementsB[i]); !ok || result != 0 {
+			return result, ok
+		}
+	}
+	return len(elementsA) - len(elementsB), true
+}
+
+func rank(value object.Object) (int, bool) {
+	switch value.(type) {
+	case *object.Integer:
+		return 0, true
+	case *object.String:
+		return 1, true
+	case *object.Array:
+		return 2, true
+	}
+	return 0, false
+}
+
+func order(less, greater bool) int {
+	if less {
+		return -1
+	}
+	if greater {
+		return 1
+	}
+	return 0
+}
+
+// comparisonError reports values that can not be ordered, naming the innermost ones for nested arrays.
+func comparisonError(call *Call, a, b object.Object) *object.Error {
+	if arrayA, ok := a.(*object.Array); ok {
+		if arrayB, ok := b.(*object.Array); ok {
+			for i := 0; i < len(arrayA.Elements) && i < len(arrayB.Elements); i++ {
+				if _, ok := compare(arrayA.Elements[i], arrayB.Elements[i]); !ok {
+					return comparisonError(call, arrayA.Elements[i], arrayB.Elements[i])
+				}
+			}
+		}
+	}
+	return object.NewErrorWithKind(object.COMPARISON_ERROR, "%s: can not compare %s and %s", call.Name, a.Type(), b.Type())
+}
+
+// comparator returns how two elements are ordered, or an error ending the sort.
+type comparator func(a, b object.Object) (int, *object.Error)
+
+// naturalOrder compares the elements themselves.
+func naturalOrder(call *Call) comparator {
+	return func(a, b object.Object) (int, *object.Error) {
+		result, ok := compare(a, b)
+		if !ok {
+			return 0, comparisonError(call, a, b)
+		}
+		return result, nil
+	}
+}
+
+// functionOrder compares the elements with a function returning a negative integer, zero or a positive integer,
+// as the first element comes before, with or after the second one.
+func functionOrder(call *Call, fn object.Object) comparator {
+	return func(a, b object.Object) (int, *object.Error) {
+		result, err := callFunction(call, fn, a, b)
+		if err != nil {
+			return 0, err
+		}
+		integer, ok := result.(*object.Integer)
+		if !ok {
+			return 0, object.NewErrorWithKind(object.TYPE_ERROR, "%s: the comparator returned %s instead of an integer", call.Name, result.Type())
+		}
+		return order(integer.Value < 0, integer.Value > 0), nil
+	}
+}
+
+// sortElements returns the elements sorted by a stable sort, which stops at the first error.
+func sortElements(elements []object.Object, cmp comparator) ([]object.Object, *object.Error) {
+	sorted := append([]object.Object{}, elements...)
+	var err *object.Error
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if err != nil {
+			return false
+		}
+		var result int
+		result, err = cmp(sorted[i], sorted[j])
+		return result < 0
+	})
+	return sorted, err
+}
+
+// keysOf returns the results of the function called once with each element.
+func keysOf(call *Call, array *object.Array, fn object.Object) ([]object.Object, *object.Error) {
+	keys := make([]object.Object, 0, len(array.Elements))
+	for _, element := range array.Elements {
+		key, err := callFunction(call, fn, element)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// sortedBy returns the elements sorted by their keys, which are compared like the elements of sort.
+func sortedBy(call *Call, array *object.Array, fn object.Object) ([]object.Object, *object.Error) {
+	keys, err := keysOf(call, array, fn)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]object.Object, 0, len(keys))
+	for i, key := range keys {
+		pairs = append(pairs, object.NewArray([]object.Object{key, array.Elements[i]}))
+	}
+	byKey := naturalOrder(call)
+	sorted, err := sortElements(pairs, func(a, b object.Object) (int, *object.Error) {
+		return byKey(a.(*object.Array).Elements[0], b.(*object.Array).Elements[0])
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, pair := range sorted {
+		sorted[i] = pair.(*object.Array).Elements[1]
+	}
+	return sorted, nil
+}
+
+// sorting returns the function of a module sorting arrays, which replaces the elements of the array when inPlace
+// is true, and returns a new array otherwise.
+func sorting(inPlace bool, sortArray func(call *Call, array *object.Array) ([]object.Object, *object.Error)) func(call *Call) object.Object {
+	return func(call *Call) object.Object {
+		array, err := call.Array(0)
+		if err != nil {
+			return err
+		}
+		sorted, err := sortArray(call, array)
+		if err != nil {
+			return err
+		}
+		if !inPlace {
+			return object.NewArray(sorted)
+		}
+		copy(array.Elements, sorted)
+		return array
+	}
+}
+
+func sortNatural(call *Call, array *object.Array) ([]object.Object, *object.Error) {
+	return sortElements(array.Elements, naturalOrder(call))
+}
+
+func sortByKey(call *Call, array *object.Array) ([]object.Object, *object.Error) {
+	fn, err := call.Function(1)
+	if err != nil {
+		return nil, err
+	}
+	return sortedBy(call, array, fn)
+}
+
+func sortWithComparator(call *Call, array *object.Array) ([]object.Object, *object.Error) {
+	fn, err := call.Function(1)
+	if err != nil {
+		return nil, err
+	}
+	return sortElements(array.Elements, functionOrder(call, fn))
+}
+
+func reverseElements(_ *Call, array *object.Array) ([]object.Object, *object.Error) {
+	reversed := make([]object.Object, len(array.Elements))
+	for i, element := range array.Elements {
+		reversed[len(reversed)-1-i] = element
+	}
+	return reversed, nil
+}
+
+// extreme returns the function of a module returning the first of the smallest elements when sign is -1,
+// or the first of the largest elements when sign is 1. The elements are compared by the results of the
+// function given as second argument when byKey is true.
+func extreme(sign int, byKey bool) func(call *Call) object.Object {
+	return func(call *Call) object.Object {
+		array, err := call.Array(0)
+		if err != nil {
+			return err
+		}
+		keys := array.Elements
+		if byKey {
+			fn, err := call.Function(1)
+			if err != nil {
+				return err
+			}
+			if keys, err = keysOf(call, array, fn); err != nil {
+				return err
+			}
+		}
+		if len(keys) == 0 {
+			return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: empty array", call.Name)
+		}
+		cmp := naturalOrder(call)
+		best := 0
+		for i := 1; i < len(keys); i++ {
+			result, err := cmp(keys[i], keys[best])
+			if err != nil {
+				return err
+			}
+			if result*sign > 0 {
+				best = i
+			}
+		}
+		return array.Elements[best]
+	}
+}
@@ -0,0 +1,161 @@
+// Package stdlib holds the modules of the standard library, which programs import by name instead of by path,
+// such as `import "math" as math;`. Each module is declared by a table of its functions.
+package stdlib
+
+import (
+	"sort"
+	"yail/environment"
+	"yail/object"
+)
+
+// Module is a module of the standard library.
+type Module struct {
+	Name      string
+	Doc       string
+	Functions []*Function
+}
+
+// Function is a function of a module, declared with the numbers of arguments it accepts, which are checked
+// before Fn is called. MaxArgs is object.VARIADIC for the functions accepting any number of arguments.
+type Function struct {
+	Name    string
+	MinArgs int
+	MaxArgs int
+	// Doc starts with the parameters of the function, such as `clamp(n, low, high)`, followed by what it does.
+	Doc string
+	Fn  func(call *Call) object.Object
+}
+
+var modules = map[string]*Module{}
+
+func register(module *Module) *Module {
+	modules[module.Name] = module
+	return module
+}
+
+// Lookup returns the module of the standard library with the given name.
+func Lookup(name string) (*Module, bool) {
+	module, ok := modules[name]
+	return module, ok
+}
+
+// Names returns the names of the modules of the standard library, in alphabetical order.
+func Names() []string {
+	var names []string
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Builtin returns the function of the module as a builtin function, named after the module, such as `math.abs`.
+func (m *Module) Builtin(function *Function) *object.Builtin {
+	var builtin *object.Builtin
+	builtin = object.NewBuiltin(m.Name+"."+function.Name, function.MinArgs, function.MaxArgs, func(apply object.Applier, args ...object.Object) object.Object {
+		if !builtin.Accepts(len(args)) {
+			return builtin.ArgumentCountError(len(args))
+		}
+		return function.Fn(&Call{Name: m.Name + "." + function.Name, Args: args, apply: apply})
+	})
+	return builtin
+}
+
+// Call holds the arguments given to a function of the standard library, and checks their types.
+type Call struct {
+	// Name is the name of the function, including the name of its module.
+	Name  string
+	Args  []object.Object
+	apply object.Applier
+}
+
+// Apply calls a function given to the function of the standard library.
+func (c *Call) Apply(fn object.Object, args ...object.Object) object.Object {
+	return c.apply.Apply(fn, args...)
+}
+
+// Step counts a step of a loop of the function of the standard library, so that long loops respect the limits
+// and the deadline of the program.
+func (c *Call) Step() *object.Error {
+	if c.apply == nil {
+		return nil
+	}
+	return c.apply.Step()
+}
+
+// Allocate counts an object created by the function of the standard library.
+func (c *Call) Allocate() *object.Error {
+	if c.apply == nil {
+		return nil
+	}
+	return c.apply.Allocate()
+}
+
+// Integer returns the argument at the given index, which must be an integer.
+func (c *Call) Integer(i int) (int64, *object.Error) {
+	if integer, ok := c.Args[i].(*object.Integer); ok {
+		return integer.Value, nil
+	}
+	return 0, c.invalidType(i)
+}
+
+// String returns the argument at the given index, which must be a string.
+func (c *Call) String(i int) (string, *object.Error) {
+	if str, ok := c.Args[i].(*object.String); ok {
+		return str.Value, nil
+	}
+	return "", c.invalidType(i)
+}
+
+// Strings returns the first n arguments, which must be strings.
+func (c *Call) Strings(n int) ([]string, *object.Error) {
+	values := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		value, err := c.String(i)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// Array returns the argument at the given index, which must be an array.
+func (c *Call) Array(i int) (*object.Array, *object.Error) {
+	if array, ok := c.Args[i].(*object.Array); ok {
+		return array, nil
+	}
+	return nil, c.invalidType(i)
+}
+
+// Function returns the argument at the given index, which must be a function.
+func (c *Call) Function(i int) (object.Object, *object.Error) {
+	switch c.Args[i].Type() {
+	case environment.FUNCTION_OBJ, object.BUILTIN_OBJ:
+		return c.Args[i], nil
+	}
+	return nil, c.invalidType(i)
+}
+
+// HashMap returns the argument at the given index, which must be a hash map.
+func (c *Call) HashMap(i int) (*object.HashMap, *object.Error) {
+	if hashMap, ok := c.Args[i].(*object.HashMap); ok {
+		return hashMap, nil
+	}
+	return nil, c.invalidType(i)
+}
+
+func (c *Call) invalidType(i int) *object.Error {
+	return object.NewErrorWithKind(object.TYPE_ERROR, object.INVALID_TYPE_EXCEPTION_MESSAGE, c.Name, c.Args[i].Type())
+}
+
+// key returns the value as a key of hash maps, calling the functions of records through the call.
+func (c *Call) key(value object.Object) (object.Hashable, *object.Error) {
+	return object.AsKey(value, c.apply)
+}
+
+// set gives the value to the key of the hash map, and returns the error raised while comparing the key.
+func set(hashMap *object.HashMap, key object.Hashable, value object.Object) *object.Error {
+	hashMap.Set(key, value)
+	return object.KeyError(key)
+}
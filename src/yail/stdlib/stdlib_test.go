@@ -0,0 +1,447 @@
+package stdlib
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+	"yail/object"
+	"yail/utils"
+)
+
+func TestModules(t *testing.T) {
+	utils.ValidateValue(strings.Join(Names(), " "), "arrays io json maps math strings time", t)
+	for _, name := range Names() {
+		module, _ := Lookup(name)
+		utils.ValidateValue(module.Doc != "", true, t)
+		for _, function := range module.Functions {
+			// the documentation starts with the parameters, from which the arity can be told
+			utils.ValidateValue(strings.HasPrefix(function.Doc, function.Name+"("), true, t)
+			utils.ValidateValue(function.MinArgs <= function.MaxArgs || function.MaxArgs == object.VARIADIC, true, t)
+		}
+	}
+	_, ok := Lookup("lib/math.yail")
+	utils.ValidateValue(ok, false, t)
+}
+
+func TestFunctions(t *testing.T) {
+	tests := []struct {
+		module   string
+		function string
+		args     []object.Object
+		expected string
+	}{
+		{MATH, "abs", integers(-5), "5"},
+		{MATH, "sign", integers(-5), "-1"},
+		{MATH, "sign", integers(0), "0"},
+		{MATH, "clamp", integers(12, 0, 10), "10"},
+		{MATH, "clamp", integers(-2, 0, 10), "0"},
+		{MATH, "gcd", integers(-12, 18), "6"},
+		{MATH, "sqrt", integers(17), "4"},
+		{MATH, "sqrt", integers(9223372036854775807), "3037000499"},
+		{STRINGS, "toString", []object.Object{object.NewArray(integers(1, 2))}, "[1, 2]"},
+		{STRINGS, "parseInt", texts("-42"), "-42"},
+		{STRINGS, "parseInt", []object.Object{object.NewString("ff"), object.NewInteger(16)}, "255"},
+		{STRINGS, "split", texts("a,b,,c", ","), "[a, b, , c]"},
+		{STRINGS, "split", texts("héllo", ""), "[h, é, l, l, o]"},
+		{STRINGS, "join", []object.Object{object.NewArray(texts("a", "b")), object.NewString(", ")}, "a, b"},
+		{STRINGS, "join", []object.Object{object.NewArray(texts("a", "b"))}, "ab"},
+		{STRINGS, "trim", texts(" \t é \n"), "é"},
+		{STRINGS, "trim", texts("éxhixé", "xé"), "hi"},
+		{STRINGS, "trimStart", texts("  a  "), "a  "},
+		{STRINGS, "trimEnd", texts("  a  "), "  a"},
+		{STRINGS, "upper", texts("héllo wörld"), "HÉLLO WÖRLD"},
+		{STRINGS, "lower", texts("ÉCOLE"), "école"},
+		{STRINGS, "contains", texts("héllo", "él"), "true"},
+		{STRINGS, "startsWith", texts("héllo", "hé"), "true"},
+		{STRINGS, "endsWith", texts("héllo", "hé"), "false"},
+		{STRINGS, "indexOf", texts("héllo", "l"), "2"},
+		{STRINGS, "indexOf", texts("héllo", "x"), "-1"},
+		{STRINGS, "replace", texts("aaa", "a", "é"), "ééé"},
+		{STRINGS, "replace", []object.Object{object.NewString("aaa"), object.NewString("a"), object.NewString("b"), object.NewInteger(2)}, "bba"},
+		{STRINGS, "repeat", []object.Object{object.NewString("é"), object.NewInteger(3)}, "ééé"},
+		{STRINGS, "repeat", []object.Object{object.NewString("é"), object.NewInteger(0)}, ""},
+		{STRINGS, "padStart", []object.Object{object.NewString("7"), object.NewInteger(3), object.NewString("0")}, "007"},
+		{STRINGS, "padStart", []object.Object{object.NewString("é"), object.NewInteger(2)}, " é"},
+		{STRINGS, "padEnd", []object.Object{object.NewString("é"), object.NewInteger(4), object.NewString("àb")}, "éàbà"},
+		{STRINGS, "padEnd", []object.Object{object.NewString("long"), object.NewInteger(2)}, "long"},
+		{STRINGS, "substring", []object.Object{object.NewString("héllo"), object.NewInteger(1), object.NewInteger(3)}, "él"},
+		{STRINGS, "substring", []object.Object{object.NewString("héllo"), object.NewInteger(-2)}, "lo"},
+		{STRINGS, "substring", []object.Object{object.NewString("héllo"), object.NewInteger(3), object.NewInteger(1)}, ""},
+		{STRINGS, "chars", texts("hé!"), "[h, é, !]"},
+		{STRINGS, "chars", texts(""), "[]"},
+		{ARRAYS, "range", integers(3), "[0, 1, 2]"},
+		{ARRAYS, "range", integers(5, 0, -2), "[5, 3, 1]"},
+		{ARRAYS, "range", integers(3, 1), "[]"},
+		{ARRAYS, "range", integers(math.MaxInt64-7, math.MaxInt64, 3), "[9223372036854775800, 9223372036854775803, 9223372036854775806]"},
+		{ARRAYS, "range", integers(math.MinInt64+7, math.MinInt64, -3), "[-9223372036854775801, -9223372036854775804, -9223372036854775807]"},
+		{ARRAYS, "concat", []object.Object{object.NewArray(integers(1)), object.NewArray(nil), object.NewArray(integers(2, 3))}, "[1, 2, 3]"},
+		{ARRAYS, "concat", nil, "[]"},
+		{ARRAYS, "slice", []object.Object{object.NewArray(integers(1, 2, 3, 4)), object.NewInteger(1), object.NewInteger(3)}, "[2, 3]"},
+		{ARRAYS, "slice", []object.Object{object.NewArray(integers(1, 2, 3, 4)), object.NewInteger(-1)}, "[4]"},
+		{ARRAYS, "slice", []object.Object{object.NewArray(integers(1, 2)), object.NewInteger(5)}, "[]"},
+		{MAPS, "fromEntries", []object.Object{object.NewArray([]object.Object{object.NewArray(append(texts("a"), object.NewInteger(1)))})}, "{a: 1}"},
+		{MAPS, "fromKeys", []object.Object{object.NewArray(texts("a")), object.TRUE}, "{a: true}"},
+		{TIME, "format", integers(0), "1970-01-01T00:00:00.000Z"},
+		{JSON, "stringify", []object.Object{object.NewArray([]object.Object{object.NewInteger(1), object.NewString("a\n"), object.TRUE, object.NULL})}, `[1,"a\n",true,null]`},
+		{JSON, "stringify", []object.Object{hashMap(object.NewString("b"), object.NewInteger(1), object.NewInteger(2), object.NewArray(nil))}, `{"b":1,"2":[]}`},
+		{JSON, "parse", texts(` [1, "a", true, null, {"b": -2}] `), "[1, a, true, null, {b: -2}]"},
+		{JSON, "parse", texts(`{"z": {}, "a": [], "m": {"y": 1, "x": 2}}`), "{z: {}, a: [], m: {y: 1, x: 2}}"},
+	}
+
+	for _, tt := range tests {
+		utils.ValidateValue(call(tt.module, tt.function, tt.args...).Inspect(), tt.expected, t)
+	}
+
+	// the limit of the strings counts bytes, whatever the size of their characters
+	atLimit := []object.Object{
+		call(STRINGS, "repeat", object.NewString("ab"), object.NewInteger(1<<23)),
+		call(STRINGS, "padEnd", object.NewString("ab"), object.NewInteger(1<<23+1), object.NewString("é")),
+	}
+	for _, result := range atLimit {
+		if s, ok := result.(*object.String); !ok || len(s.Value) != MAX_STRING_BYTES {
+			t.Errorf("expected a string of %d bytes, got %s", MAX_STRING_BYTES, result.Type())
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	cyclic := object.NewArray(nil)
+	cyclic.Elements = append(cyclic.Elements, cyclic)
+
+	tests := []struct {
+		module          string
+		function        string
+		args            []object.Object
+		expectedKind    string
+		expectedMessage string
+	}{
+		{MATH, "abs", nil, object.ARGUMENT_ERROR, "wrong number of arguments: expected 1, but received 0"},
+		{MATH, "abs", texts("a"), object.TYPE_ERROR, "math.abs(STRING) not supported"},
+		{MATH, "clamp", integers(1, 5, 0), object.ARGUMENT_ERROR, "math.clamp: low bound 5 is greater than high bound 0"},
+		{MATH, "sqrt", integers(-4), object.ARITHMETIC_ERROR, "square root of a negative number: -4"},
+		{MATH, "abs", integers(math.MinInt64), object.ARITHMETIC_ERROR, "integer overflow: math.abs(-9223372036854775808)"},
+		{MATH, "gcd", integers(math.MinInt64, 0), object.ARITHMETIC_ERROR, "integer overflow: math.gcd(-9223372036854775808, 0)"},
+		{STRINGS, "parseInt", texts("1x"), object.ARGUMENT_ERROR, `strings.parseInt: invalid integer "1x"`},
+		{STRINGS, "parseInt", []object.Object{object.NewString("1"), object.NewInteger(1)}, object.ARGUMENT_ERROR, "strings.parseInt: invalid base 1"},
+		{STRINGS, "upper", integers(1), object.TYPE_ERROR, "strings.upper(INTEGER) not supported"},
+		{STRINGS, "split", []object.Object{object.NewString("a"), object.NewInteger(1)}, object.TYPE_ERROR, "strings.split(INTEGER) not supported"},
+		{STRINGS, "join", []object.Object{object.NewArray(integers(1))}, object.TYPE_ERROR, "strings.join(INTEGER) not supported"},
+		{STRINGS, "replace", []object.Object{object.NewString("a"), object.NewString("a"), object.NewString("b"), object.NewInteger(-1)},
+			object.ARGUMENT_ERROR, "strings.replace: count must not be negative, but is -1"},
+		{STRINGS, "repeat", []object.Object{object.NewString("a"), object.NewInteger(-1)}, object.ARGUMENT_ERROR, "strings.repeat: count must not be negative, but is -1"},
+		{STRINGS, "repeat", []object.Object{object.NewString("ab"), object.NewInteger(1 << 40)}, object.ARGUMENT_ERROR, "strings.repeat: the string would be longer than 16777216 bytes"},
+		{STRINGS, "repeat", []object.Object{object.NewString("ab"), object.NewInteger(1<<23 + 1)}, object.ARGUMENT_ERROR, "strings.repeat: the string would be longer than 16777216 bytes"},
+		{STRINGS, "padStart", []object.Object{object.NewString("a"), object.NewInteger(1<<24 + 2)}, object.ARGUMENT_ERROR, "strings.padStart: the string would be longer than 16777216 bytes"},
+		{STRINGS, "padEnd", []object.Object{object.NewString("ab"), object.NewInteger(1<<23 + 2), object.NewString("é")}, object.ARGUMENT_ERROR, "strings.padEnd: the string would be longer than 16777216 bytes"},
+		{STRINGS, "padStart", []object.Object{object.NewString("é"), object.NewInteger(1<<23 + 1), object.NewString("a€")}, object.ARGUMENT_ERROR, "strings.padStart: the string would be longer than 16777216 bytes"},
+		{STRINGS, "padStart", []object.Object{object.NewString("a"), object.NewInteger(3), object.NewString("")}, object.ARGUMENT_ERROR, "strings.padStart: padding must not be empty"},
+		{STRINGS, "substring", texts("a", "b"), object.TYPE_ERROR, "strings.substring(STRING) not supported"},
+		{ARRAYS, "range", integers(1, 2, 3, 4), object.ARGUMENT_ERROR, "wrong number of arguments: expected 1 to 3, but received 4"},
+		{ARRAYS, "range", integers(0, 5, 0), object.ARGUMENT_ERROR, "arrays.range: step must not be zero"},
+		{ARRAYS, "concat", []object.Object{object.NewArray(nil), object.NewInteger(1)}, object.TYPE_ERROR, "arrays.concat(INTEGER) not supported"},
+		{MAPS, "fromEntries", []object.Object{object.NewArray(integers(1))}, object.ARGUMENT_ERROR, "maps.fromEntries: entry 1 is not a [key, value] array"},
+		{MAPS, "fromKeys", []object.Object{object.NewArray([]object.Object{object.NULL}), object.NULL}, object.TYPE_ERROR, "unusable as hash key: NULL"},
+		{JSON, "stringify", []object.Object{cyclic}, object.ARGUMENT_ERROR, "json.stringify: the value contains itself"},
+		{JSON, "stringify", []object.Object{object.NewArray([]object.Object{object.NewBuiltin("f", 0, 0, nil)})}, object.TYPE_ERROR, "json.stringify(BUILTIN) not supported"},
+		{JSON, "parse", texts("[1.5]"), object.ARGUMENT_ERROR, "json.parse: unsupported number 1.5, only integers are supported"},
+		{JSON, "parse", texts("[1"), object.ARGUMENT_ERROR, "json.parse: invalid JSON: unexpected end of JSON input"},
+		{JSON, "parse", texts("1 2"), object.ARGUMENT_ERROR, "json.parse: invalid JSON: unexpected text after the value"},
+	}
+
+	for _, tt := range tests {
+		err, ok := call(tt.module, tt.function, tt.args...).(*object.Error)
+		utils.ValidateValue(ok, true, t)
+		if ok {
+			utils.ValidateValue(err.Kind, tt.expectedKind, t)
+			utils.ValidateValue(err.Message, tt.expectedMessage, t)
+		}
+	}
+}
+
+func TestHigherOrderFunctions(t *testing.T) {
+	xs := object.NewArray(integers(1, 2, 3, 4, 5))
+	double := function(func(x int64) object.Object { return object.NewInteger(x * 2) })
+	even := function(func(x int64) object.Object { return object.GetPooledBooleanObject(x%2 == 0) })
+	pair := function(func(x int64) object.Object { return object.NewArray(integers(x, x)) })
+	sum := object.NewBuiltin("sum", 2, 2, func(_ object.Applier, args ...object.Object) object.Object {
+		return object.NewInteger(args[0].(*object.Integer).Value + args[1].(*object.Integer).Value)
+	})
+
+	tests := []struct {
+		function string
+		args     []object.Object
+		expected string
+	}{
+		{"map", []object.Object{xs, double}, "[2, 4, 6, 8, 10]"},
+		{"filter", []object.Object{xs, even}, "[2, 4]"},
+		{"reduce", []object.Object{xs, sum}, "15"},
+		{"reduce", []object.Object{object.NewArray(nil), sum, object.NewInteger(7)}, "7"},
+		{"forEach", []object.Object{xs, double}, "null"},
+		{"any", []object.Object{xs, even}, "true"},
+		{"all", []object.Object{xs, even}, "false"},
+		{"all", []object.Object{object.NewArray(nil), even}, "true"},
+		{"find", []object.Object{xs, even}, "2"},
+		{"find", []object.Object{object.NewArray(integers(1)), even}, "null"},
+		{"flatMap", []object.Object{object.NewArray(integers(1, 2)), pair}, "[1, 1, 2, 2]"},
+		{"zip", []object.Object{xs, object.NewArray(texts("a", "b")), object.NewArray(integers(7, 8, 9))}, "[[1, a, 7], [2, b, 8]]"},
+		{"enumerate", []object.Object{object.NewArray(texts("a", "b"))}, "[[0, a], [1, b]]"},
+		{"groupBy", []object.Object{object.NewArray(integers(1, 3)), even}, "{false: [1, 3]}"},
+		{"groupBy", []object.Object{object.NewArray(integers(1, 2, 1)), pair}, "{(1, 1): [1, 1], (2, 2): [2]}"},
+		{"chunk", []object.Object{xs, object.NewInteger(2)}, "[[1, 2], [3, 4], [5]]"},
+		{"chunk", []object.Object{object.NewArray(nil), object.NewInteger(2)}, "[]"},
+		{"distinct", []object.Object{object.NewArray(append(integers(1, 2, 1), texts("1", "1")...))}, "[1, 2, 1]"},
+		{"distinct", []object.Object{object.NewArray([]object.Object{object.NewTuple(integers(1, 2)), object.NewTuple(integers(2, 1)), object.NewTuple(integers(1, 2))})}, "[(1, 2), (2, 1)]"},
+		{"distinct", []object.Object{object.NewArray([]object.Object{object.NULL, object.NewArray(integers(1)), object.NULL, object.NewArray(integers(1))})}, "[null, [1]]"},
+		{"flatten", []object.Object{object.NewArray([]object.Object{object.NewInteger(1), object.NewArray([]object.Object{object.NewArray(integers(2))})})}, "[1, [2]]"},
+		{"flatten", []object.Object{object.NewArray([]object.Object{object.NewArray([]object.Object{object.NewArray(integers(2))})}), object.NewInteger(2)}, "[2]"},
+	}
+
+	for _, tt := range tests {
+		utils.ValidateValue(call(ARRAYS, tt.function, tt.args...).Inspect(), tt.expected, t)
+	}
+
+	errors := []struct {
+		function        string
+		args            []object.Object
+		expectedKind    string
+		expectedMessage string
+	}{
+		{"map", []object.Object{xs, object.NewInteger(1)}, object.TYPE_ERROR, "arrays.map(INTEGER) not supported"},
+		{"map", []object.Object{object.NewArray(texts("a")), double}, object.TYPE_ERROR, "expected an integer"},
+		{"filter", []object.Object{xs, double}, object.TYPE_ERROR, "arrays.filter: the predicate returned INTEGER instead of a boolean"},
+		{"reduce", []object.Object{object.NewArray(nil), sum}, object.ARGUMENT_ERROR, "arrays.reduce: empty array without initial value"},
+		{"flatMap", []object.Object{xs, double}, object.TYPE_ERROR, "arrays.flatMap: the function returned INTEGER instead of an array"},
+		{"groupBy", []object.Object{xs, function(func(x int64) object.Object { return object.NewArray([]object.Object{object.NULL}) })},
+			object.TYPE_ERROR, "unusable as hash key: NULL"},
+		{"chunk", []object.Object{xs, object.NewInteger(0)}, object.ARGUMENT_ERROR, "arrays.chunk: size must be positive, but is 0"},
+		{"flatten", []object.Object{xs, object.NewInteger(-1)}, object.ARGUMENT_ERROR, "arrays.flatten: depth must not be negative, but is -1"},
+	}
+
+	for _, tt := range errors {
+		err, ok := call(ARRAYS, tt.function, tt.args...).(*object.Error)
+		utils.ValidateValue(ok, true, t)
+		if ok {
+			utils.ValidateValue(err.Kind, tt.expectedKind, t)
+			utils.ValidateValue(err.Message, tt.expectedMessage, t)
+		}
+	}
+}
+
+func TestSorting(t *testing.T) {
+	mixed := func() *object.Array {
+		return object.NewArray([]object.Object{object.NewInteger(3), object.NewString("b"), object.NewArray(integers(1, 2)),
+			object.NewInteger(1), object.NewString("a"), object.NewArray(integers(1)), object.NewInteger(2)})
+	}
+	negate := function(func(x int64) object.Object { return object.NewInteger(-x) })
+	parity := function(func(x int64) object.Object { return object.NewInteger(x % 2) })
+	descending := object.NewBuiltin("descending", 2, 2, func(_ object.Applier, args ...object.Object) object.Object {
+		return object.NewInteger(args[1].(*object.Integer).Value - args[0].(*object.Integer).Value)
+	})
+
+	tests := []struct {
+		function string
+		args     []object.Object
+		expected string
+	}{
+		{"sort", []object.Object{mixed()}, "[1, 2, 3, a, b, [1], [1, 2]]"},
+		{"sort", []object.Object{object.NewArray(nil)}, "[]"},
+		{"sortBy", []object.Object{object.NewArray(integers(1, 3, 2)), negate}, "[3, 2, 1]"},
+		{"sortBy", []object.Object{object.NewArray(integers(4, 1, 2, 3)), parity}, "[4, 2, 1, 3]"},
+		{"sortWith", []object.Object{object.NewArray(integers(1, 3, 2)), descending}, "[3, 2, 1]"},
+		{"reverse", []object.Object{object.NewArray(integers(1, 2, 3))}, "[3, 2, 1]"},
+		{"min", []object.Object{mixed()}, "1"},
+		{"max", []object.Object{mixed()}, "[1, 2]"},
+		{"min", []object.Object{object.NewArray([]object.Object{object.TRUE})}, "true"},
+		{"minBy", []object.Object{object.NewArray(integers(1, 3, 2)), negate}, "3"},
+		{"maxBy", []object.Object{object.NewArray(integers(4, 1, 3)), parity}, "1"},
+	}
+
+	for _, tt := range tests {
+		utils.ValidateValue(call(ARRAYS, tt.function, tt.args...).Inspect(), tt.expected, t)
+	}
+
+	array := object.NewArray(integers(2, 3, 1))
+	sorted := call(ARRAYS, "sort", array)
+	utils.ValidateValue(sorted != object.Object(array), true, t)
+	utils.ValidateValue(array.Inspect(), "[2, 3, 1]", t)
+	for _, function := range []string{"sortInPlace", "reverseInPlace"} {
+		utils.ValidateValue(call(ARRAYS, function, array) == object.Object(array), true, t)
+	}
+	utils.ValidateValue(array.Inspect(), "[3, 2, 1]", t)
+	utils.ValidateValue(call(ARRAYS, "sortWithInPlace", array, descending).Inspect(), "[3, 2, 1]", t)
+	utils.ValidateValue(call(ARRAYS, "sortByInPlace", array, parity).Inspect(), "[2, 3, 1]", t)
+
+	errors := []struct {
+		function        string
+		args            []object.Object
+		expectedKind    string
+		expectedMessage string
+	}{
+		{"sort", []object.Object{object.NewArray([]object.Object{object.NewInteger(1), object.NULL})}, object.COMPARISON_ERROR, "arrays.sort: can not compare NULL and INTEGER"},
+		{"sort", []object.Object{object.NewArray([]object.Object{object.NewArray(integers(1, 2)), object.NewArray([]object.Object{object.NewInteger(1), object.TRUE})})},
+			object.COMPARISON_ERROR, "arrays.sort: can not compare BOOLEAN and INTEGER"},
+		{"sortBy", []object.Object{object.NewArray(integers(1, 2)), function(func(x int64) object.Object { return object.TRUE })},
+			object.COMPARISON_ERROR, "arrays.sortBy: can not compare BOOLEAN and BOOLEAN"},
+		{"sortWith", []object.Object{object.NewArray(texts("a", "b")), object.NewBuiltin("cmp", 2, 2, func(_ object.Applier, args ...object.Object) object.Object { return object.TRUE })},
+			object.TYPE_ERROR, "arrays.sortWith: the comparator returned BOOLEAN instead of an integer"},
+		{"max", []object.Object{object.NewArray(nil)}, object.ARGUMENT_ERROR, "arrays.max: empty array"},
+		{"minBy", []object.Object{object.NewArray(integers(1, 2)), object.NewInteger(1)}, object.TYPE_ERROR, "arrays.minBy(INTEGER) not supported"},
+	}
+
+	for _, tt := range errors {
+		err, ok := call(ARRAYS, tt.function, tt.args...).(*object.Error)
+		utils.ValidateValue(ok, true, t)
+		if ok {
+			utils.ValidateValue(err.Kind, tt.expectedKind, t)
+			utils.ValidateValue(err.Message, tt.expectedMessage, t)
+		}
+	}
+
+	unsorted := object.NewArray([]object.Object{object.NewInteger(2), object.TRUE, object.NewInteger(1)})
+	_, ok := call(ARRAYS, "sortInPlace", unsorted).(*object.Error)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(unsorted.Inspect(), "[2, true, 1]", t)
+}
+
+func TestMaps(t *testing.T) {
+	h := func() *object.HashMap {
+		return hashMap(object.NewString("b"), object.NewInteger(2), object.NewInteger(10), object.NewString("ten"),
+			object.TRUE, object.NewInteger(1), object.NewString("a"), object.NewInteger(1), object.FALSE, object.NewInteger(0),
+			object.NewInteger(-3), object.NewString("minus three"))
+	}
+	double := function(func(x int64) object.Object { return object.NewInteger(x * 2) })
+	positive := function(func(x int64) object.Object { return object.GetPooledBooleanObject(x > 0) })
+
+	tests := []struct {
+		function string
+		args     []object.Object
+		expected string
+	}{
+		{"keys", []object.Object{h()}, "[b, 10, true, a, false, -3]"},
+		{"values", []object.Object{h()}, "[2, ten, 1, 1, 0, minus three]"},
+		{"entries", []object.Object{hashMap(object.NewString("y"), object.NULL, object.NewString("x"), object.NewInteger(1))}, "[[y, null], [x, 1]]"},
+		{"entries", []object.Object{hashMap()}, "[]"},
+		{"has", []object.Object{h(), object.NewString("a")}, "true"},
+		{"has", []object.Object{h(), object.NewInteger(1)}, "false"},
+		{"getOrDefault", []object.Object{h(), object.NewInteger(10), object.NULL}, "ten"},
+		{"getOrDefault", []object.Object{h(), object.NewInteger(11), object.NULL}, "null"},
+		{"keys", []object.Object{call(MAPS, "merge", hashMap(object.NewInteger(1), object.NewInteger(1), object.NewInteger(2), object.NewInteger(2)),
+			hashMap(object.NewInteger(2), object.NewInteger(3)))}, "[1, 2]"},
+		{"values", []object.Object{call(MAPS, "merge", hashMap(object.NewInteger(1), object.NewInteger(1), object.NewInteger(2), object.NewInteger(2)),
+			hashMap(object.NewInteger(2), object.NewInteger(3)))}, "[1, 3]"},
+		{"entries", []object.Object{call(MAPS, "mapValues", hashMap(object.NewString("a"), object.NewInteger(1), object.NewString("b"), object.NewInteger(2)), double)},
+			"[[a, 2], [b, 4]]"},
+		{"keys", []object.Object{call(MAPS, "filterKeys", hashMap(object.NewInteger(-1), object.NULL, object.NewInteger(1), object.NULL), positive)}, "[1]"},
+	}
+
+	for _, tt := range tests {
+		utils.ValidateValue(call(MAPS, tt.function, tt.args...).Inspect(), tt.expected, t)
+	}
+
+	deleted := h()
+	utils.ValidateValue(call(MAPS, "delete", deleted, object.NewString("a")) == object.TRUE, true, t)
+	utils.ValidateValue(call(MAPS, "delete", deleted, object.NewString("a")) == object.FALSE, true, t)
+	utils.ValidateValue(call(MAPS, "keys", deleted).Inspect(), "[b, 10, true, false, -3]", t)
+
+	errors := []struct {
+		function        string
+		args            []object.Object
+		expectedKind    string
+		expectedMessage string
+	}{
+		{"keys", []object.Object{object.NewArray(nil)}, object.TYPE_ERROR, "maps.keys(ARRAY) not supported"},
+		{"has", []object.Object{h(), double}, object.TYPE_ERROR, "unusable as hash key: BUILTIN"},
+		{"merge", []object.Object{h(), object.NULL}, object.TYPE_ERROR, "maps.merge(NULL) not supported"},
+		{"mapValues", []object.Object{h(), double}, object.TYPE_ERROR, "expected an integer"},
+		{"filterKeys", []object.Object{hashMap(object.NewInteger(1), object.NULL), double}, object.TYPE_ERROR,
+			"maps.filterKeys: the predicate returned INTEGER instead of a boolean"},
+	}
+
+	for _, tt := range errors {
+		err, ok := call(MAPS, tt.function, tt.args...).(*object.Error)
+		utils.ValidateValue(ok, true, t)
+		if ok {
+			utils.ValidateValue(err.Kind, tt.expectedKind, t)
+			utils.ValidateValue(err.Message, tt.expectedMessage, t)
+		}
+	}
+}
+
+func TestIO(t *testing.T) {
+	var out strings.Builder
+	stdout, stdin := Stdout, Stdin
+	Stdout, Stdin = &out, strings.NewReader("first\r\nsecond")
+	defer func() { Stdout, Stdin = stdout, stdin }()
+
+	call(IO, "print", object.NewString("a"), object.NewInteger(1))
+	call(IO, "println", object.NewArray(integers(2)))
+	call(IO, "println")
+	utils.ValidateValue(out.String(), "a 1[2]\n\n", t)
+
+	utils.ValidateValue(call(IO, "readLine").Inspect(), "first", t)
+	utils.ValidateValue(call(IO, "readLine").Inspect(), "second", t)
+	utils.ValidateObject(call(IO, "readLine"), object.NULL, t)
+}
+
+func TestTime(t *testing.T) {
+	now = func() time.Time { return time.UnixMilli(5000) }
+	defer func() { now = time.Now }()
+
+	utils.ValidateValue(call(TIME, "now").Inspect(), "5000", t)
+	utils.ValidateValue(call(TIME, "since", object.NewInteger(1500)).Inspect(), "3500", t)
+}
+
+func call(moduleName, functionName string, args ...object.Object) object.Object {
+	module, _ := Lookup(moduleName)
+	for _, function := range module.Functions {
+		if function.Name == functionName {
+			return module.Builtin(function).Fn(object.ApplierFunc(apply), args...)
+		}
+	}
+	return object.NewError("unknown function: %s.%s", moduleName, functionName)
+}
+
+func integers(values ...int64) []object.Object {
+	var objects []object.Object
+	for _, value := range values {
+		objects = append(objects, object.NewInteger(value))
+	}
+	return objects
+}
+
+func texts(values ...string) []object.Object {
+	var objects []object.Object
+	for _, value := range values {
+		objects = append(objects, object.NewString(value))
+	}
+	return objects
+}
+
+// hashMap returns a hash map of the keys and values, given one after the other.
+func hashMap(keysAndValues ...object.Object) *object.HashMap {
+	hashMap := object.NewHashMap()
+	for i := 0; i < len(keysAndValues); i += 2 {
+		hashMap.Set(keysAndValues[i].(object.Hashable), keysAndValues[i+1])
+	}
+	return hashMap
+}
+
+// function returns a builtin function of one integer, which raises an error for other arguments.
+func function(fn func(x int64) object.Object) *object.Builtin {
+	return object.NewBuiltin("f", 1, 1, func(_ object.Applier, args ...object.Object) object.Object {
+		x, ok := args[0].(*object.Integer)
+		if !ok {
+			return object.NewErrorWithKind(object.TYPE_ERROR, "expected an integer")
+		}
+		return fn(x.Value)
+	})
+}
+
+// apply calls the builtin functions given to the functions of the tests.
+func apply(fn object.Object, args ...object.Object) object.Object {
+	if builtin, ok := fn.(*object.Builtin); ok {
+		return builtin.Fn(object.ApplierFunc(apply), args...)
+	}
+	return object.NewErrorWithKind(object.TYPE_ERROR, "failed to invoke %s as a function", fn.Type())
+}
@@ -0,0 +1,286 @@
+package stdlib
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"yail/object"
+)
+
+const STRINGS = "strings"
+
+// MAX_STRING_BYTES is the size in bytes, not in characters, of the longest string repeat, padStart and padEnd
+// create, so that a single call can not exhaust the memory.
+const MAX_STRING_BYTES = 1 << 24
+
+var _ = register(&Module{
+	Name: STRINGS,
+	Doc:  "Conversions between strings and other values, and manipulation of strings. Indices count characters, not bytes.",
+	Functions: []*Function{
+		{Name: "toString", MinArgs: 1, MaxArgs: 1, Doc: "toString(value) returns the text of value, as inserted by string templates.", Fn: toString},
+		{Name: "parseInt", MinArgs: 1, MaxArgs: 2, Doc: "parseInt(s, base) reads the integer written in s, in base 10 unless a base from 2 to 36 is given.", Fn: parseInt},
+		{Name: "split", MinArgs: 2, MaxArgs: 2, Doc: "split(s, separator) returns the strings between the separators in s, " +
+			"or the characters of s when separator is empty.", Fn: split},
+		{Name: "join", MinArgs: 1, MaxArgs: 2, Doc: "join(strings, separator) returns the strings of the array separated by separator, " +
+			"or by nothing when it is not given.", Fn: join},
+		{Name: "trim", MinArgs: 1, MaxArgs: 2, Doc: "trim(s, characters) returns s without the characters at its start and end, " +
+			"which are the white space characters when they are not given.", Fn: trim(strings.TrimFunc)},
+		{Name: "trimStart", MinArgs: 1, MaxArgs: 2, Doc: "trimStart(s, characters) returns s without the characters at its start, like trim.", Fn: trim(strings.TrimLeftFunc)},
+		{Name: "trimEnd", MinArgs: 1, MaxArgs: 2, Doc: "trimEnd(s, characters) returns s without the characters at its end, like trim.", Fn: trim(strings.TrimRightFunc)},
+		{Name: "upper", MinArgs: 1, MaxArgs: 1, Doc: "upper(s) returns s with its letters in upper case.", Fn: mapString(strings.ToUpper)},
+		{Name: "lower", MinArgs: 1, MaxArgs: 1, Doc: "lower(s) returns s with its letters in lower case.", Fn: mapString(strings.ToLower)},
+		{Name: "contains", MinArgs: 2, MaxArgs: 2, Doc: "contains(s, substring) returns whether substring is in s.", Fn: testStrings(strings.Contains)},
+		{Name: "startsWith", MinArgs: 2, MaxArgs: 2, Doc: "startsWith(s, prefix) returns whether s starts with prefix.", Fn: testStrings(strings.HasPrefix)},
+		{Name: "endsWith", MinArgs: 2, MaxArgs: 2, Doc: "endsWith(s, suffix) returns whether s ends with suffix.", Fn: testStrings(strings.HasSuffix)},
+		{Name: "indexOf", MinArgs: 2, MaxArgs: 2, Doc: "indexOf(s, substring) returns the index of the first character of substring in s, or -1.", Fn: indexOf},
+		{Name: "replace", MinArgs: 3, MaxArgs: 4, Doc: "replace(s, old, new, count) returns s with the first count occurrences of old replaced by new, " +
+			"or all of them when count is not given.", Fn: replace},
+		{Name: "repeat", MinArgs: 2, MaxArgs: 2, Doc: "repeat(s, count) returns count copies of s one after the other. " +
+			"The result can not be longer than 16777216 bytes.", Fn: repeat},
+		{Name: "padStart", MinArgs: 2, MaxArgs: 3, Doc: "padStart(s, length, padding) returns s preceded by as much of padding repeated as needed " +
+			"for length characters. padding is a space when it is not given. The result can not be longer than 16777216 bytes.", Fn: pad(true)},
+		{Name: "padEnd", MinArgs: 2, MaxArgs: 3, Doc: "padEnd(s, length, padding) returns s followed by padding, like padStart.", Fn: pad(false)},
+		{Name: "substring", MinArgs: 2, MaxArgs: 3, Doc: "substring(s, start, end) returns the characters of s from start, included, to end, excluded, " +
+			"or to the end of s when end is not given. Negative indices count from the end.", Fn: substring},
+		{Name: "chars", MinArgs: 1, MaxArgs: 1, Doc: "chars(s) returns the characters of s, each one as a string.", Fn: chars},
+	},
+})
+
+func toString(call *Call) object.Object {
+	return object.NewString(object.ToString(call.Args[0]))
+}
+
+func parseInt(call *Call) object.Object {
+	s, err := call.String(0)
+	if err != nil {
+		return err
+	}
+	base := int64(10)
+	if len(call.Args) == 2 {
+		if base, err = call.Integer(1); err != nil {
+			return err
+		}
+		if base < 2 || base > 36 {
+			return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: invalid base %d", call.Name, base)
+		}
+	}
+	n, parseErr := strconv.ParseInt(s, int(base), 64)
+	if parseErr != nil {
+		return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: invalid integer %q", call.Name, s)
+	}
+	return object.NewInteger(n)
+}
+
+func split(call *Call) object.Object {
+	args, err := call.Strings(2)
+	if err != nil {
+		return err
+	}
+	var elements []object.Object
+	for _, part := range strings.Split(args[0], args[1]) {
+		elements = append(elements, object.NewString(part))
+	}
+	return object.NewArray(elements)
+}
+
+func join(call *Call) object.Object {
+	array, err := call.Array(0)
+	if err != nil {
+		return err
+	}
+	separator := ""
+	if len(call.Args) == 2 {
+		if separator, err = call.String(1); err != nil {
+			return err
+		}
+	}
+	var values []string
+	for _, element := range array.Elements {
+		value, ok := element.(*object.String)
+		if !ok {
+			return object.NewErrorWithKind(object.TYPE_ERROR, object.INVALID_TYPE_EXCEPTION_MESSAGE, call.Name, element.Type())
+		}
+		values = append(values, value.Value)
+	}
+	return object.NewString(strings.Join(values, separator))
+}
+
+// trim returns the function of a module trimming strings with the given function of package strings.
+func trim(trimFunc func(s string, f func(rune) bool) string) func(call *Call) object.Object {
+	return func(call *Call) object.Object {
+		args, err := call.Strings(len(call.Args))
+		if err != nil {
+			return err
+		}
+		isTrimmed := unicode.IsSpace
+		if len(args) == 2 {
+			isTrimmed = func(r rune) bool { return strings.ContainsRune(args[1], r) }
+		}
+		return object.NewString(trimFunc(args[0], isTrimmed))
+	}
+}
+
+// mapString returns the function of a module returning the string given by f for its argument.
+func mapString(f func(s string) string) func(call *Call) object.Object {
+	return func(call *Call) object.Object {
+		s, err := call.String(0)
+		if err != nil {
+			return err
+		}
+		return object.NewString(f(s))
+	}
+}
+
+// testStrings returns the function of a module returning the result of f for its two arguments.
+func testStrings(f func(s, substring string) bool) func(call *Call) object.Object {
+	return func(call *Call) object.Object {
+		args, err := call.Strings(2)
+		if err != nil {
+			return err
+		}
+		return object.GetPooledBooleanObject(f(args[0], args[1]))
+	}
+}
+
+func indexOf(call *Call) object.Object {
+	args, err := call.Strings(2)
+	if err != nil {
+		return err
+	}
+	index := strings.Index(args[0], args[1])
+	if index > 0 {
+		index = utf8.RuneCountInString(args[0][:index])
+	}
+	return object.NewInteger(int64(index))
+}
+
+func replace(call *Call) object.Object {
+	args, err := call.Strings(3)
+	if err != nil {
+		return err
+	}
+	count := int64(-1)
+	if len(call.Args) == 4 {
+		if count, err = call.Integer(3); err != nil {
+			return err
+		}
+		if count < 0 {
+			return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: count must not be negative, but is %d", call.Name, count)
+		}
+	}
+	return object.NewString(strings.Replace(args[0], args[1], args[2], int(count)))
+}
+
+func repeat(call *Call) object.Object {
+	s, err := call.String(0)
+	if err != nil {
+		return err
+	}
+	count, err := call.Integer(1)
+	if err != nil {
+		return err
+	}
+	if count < 0 {
+		return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: count must not be negative, but is %d", call.Name, count)
+	}
+	if len(s) > 0 && count > MAX_STRING_BYTES/int64(len(s)) {
+		return tooLong(call)
+	}
+	var builder strings.Builder
+	builder.Grow(len(s) * int(count))
+	for i := int64(0); i < count; i++ {
+		if err := call.Step(); err != nil {
+			return err
+		}
+		builder.WriteString(s)
+	}
+	return object.NewString(builder.String())
+}
+
+// pad returns the function of a module padding strings at their start, or at their end.
+func pad(atStart bool) func(call *Call) object.Object {
+	return func(call *Call) object.Object {
+		s, err := call.String(0)
+		if err != nil {
+			return err
+		}
+		length, err := call.Integer(1)
+		if err != nil {
+			return err
+		}
+		padding := " "
+		if len(call.Args) == 3 {
+			if padding, err = call.String(2); err != nil {
+				return err
+			}
+			if padding == "" {
+				return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: padding must not be empty", call.Name)
+			}
+		}
+		missing := length - int64(utf8.RuneCountInString(s))
+		if missing <= 0 {
+			return call.Args[0]
+		}
+		// every character takes a byte at least, which bounds the size computed below
+		if missing > MAX_STRING_BYTES {
+			return tooLong(call)
+		}
+		runes := []rune(padding)
+		repeats, rest := missing/int64(len(runes)), missing%int64(len(runes))
+		if int64(len(s))+repeats*int64(len(string(runes)))+int64(len(string(runes[:rest]))) > MAX_STRING_BYTES {
+			return tooLong(call)
+		}
+		var filler strings.Builder
+		for i := int64(0); i < missing; i++ {
+			if err := call.Step(); err != nil {
+				return err
+			}
+			filler.WriteRune(runes[i%int64(len(runes))])
+		}
+		if atStart {
+			return object.NewString(filler.String() + s)
+		}
+		return object.NewString(s + filler.String())
+	}
+}
+
+func tooLong(call *Call) *object.Error {
+	return object.NewErrorWithKind(object.ARGUMENT_ERROR, "%s: the string would be longer than %d bytes", call.Name, MAX_STRING_BYTES)
+}
+
+func substring(call *Call) object.Object {
+	s, err := call.String(0)
+	if err != nil {
+		return err
+	}
+	runes := []rune(s)
+	length := int64(len(runes))
+	start, err := call.Integer(1)
+	if err != nil {
+		return err
+	}
+	end := length
+	if len(call.Args) == 3 {
+		if end, err = call.Integer(2); err != nil {
+			return err
+		}
+	}
+	start, end = clampIndex(start, length), clampIndex(end, length)
+	if start >= end {
+		return object.NewString("")
+	}
+	return object.NewString(string(runes[start:end]))
+}
+
+func chars(call *Call) object.Object {
+	s, err := call.String(0)
+	if err != nil {
+		return err
+	}
+	elements := []object.Object{}
+	for _, r := range s {
+		elements = append(elements, object.NewString(string(r)))
+	}
+	return object.NewArray(elements)
+}
@@ -0,0 +1,41 @@
+package stdlib
+
+import (
+	"time"
+	"yail/object"
+)
+
+const TIME = "time"
+
+// now is replaced by the tests.
+var now = time.Now
+
+var _ = register(&Module{
+	Name: TIME,
+	Doc:  "The current time, as milliseconds since January 1, 1970 UTC.",
+	Functions: []*Function{
+		{Name: "now", MinArgs: 0, MaxArgs: 0, Doc: "now() returns the current time in milliseconds.", Fn: currentTime},
+		{Name: "since", MinArgs: 1, MaxArgs: 1, Doc: "since(start) returns the milliseconds elapsed since the time start.", Fn: since},
+		{Name: "format", MinArgs: 1, MaxArgs: 1, Doc: "format(time) returns the time in the RFC 3339 format, in UTC, such as 2024-05-01T12:30:00.000Z.", Fn: formatTime},
+	},
+})
+
+func currentTime(call *Call) object.Object {
+	return object.NewInteger(now().UnixMilli())
+}
+
+func since(call *Call) object.Object {
+	start, err := call.Integer(0)
+	if err != nil {
+		return err
+	}
+	return object.NewInteger(now().UnixMilli() - start)
+}
+
+func formatTime(call *Call) object.Object {
+	milliseconds, err := call.Integer(0)
+	if err != nil {
+		return err
+	}
+	return object.NewString(time.UnixMilli(milliseconds).UTC().Format("2006-01-02T15:04:05.000Z07:00"))
+}
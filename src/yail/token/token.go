@@ -1,5 +1,7 @@
 package token
 
+import "fmt"
+
 type TokenType string
 
 const (
@@ -9,6 +11,7 @@ const (
 	IDENTIFIER = "IDENTIFIER"      // x, y, ...
 	INTEGER    = "INTEGER_LITERAL" // 1, 2, 10, ...
 	STRING     = "STRING"
+	TEMPLATE   = "TEMPLATE" // string embedding expressions like "${x}"
 
 	// Operators
 	ASSIGN           = "="
@@ -24,10 +27,20 @@ const (
 	NOT_EQUAL        = "!="
 	LESS_OR_EQUAL    = "<="
 	GREATER_OR_EQUAL = ">="
+	BIT_AND          = "&"
+	BIT_OR           = "|"
+	BIT_XOR          = "^"
+	BIT_NOT          = "~"
+	SHIFT_LEFT       = "<<" // also composes functions
+	SHIFT_RIGHT      = ">>" // also composes functions
+	POWER            = "**"
+	PIPE             = "|>"
 
 	// Delimiters
 	COMMA             = ","
 	COLON             = ":"
+	DOT               = "."
+	QUESTION          = "?" // marks a nullable type
 	SEMICOLON         = ";"
 	LEFT_PARENTHESIS  = "("
 	RIGHT_PARENTHESIS = ")"
@@ -46,11 +59,39 @@ const (
 	ELSE     = "else"
 	RETURN   = "return"
 	NULL     = "null"
+	TRY      = "try"
+	CATCH    = "catch"
+	FINALLY  = "finally"
+	THROW    = "throw"
+	IMPORT   = "import"
+	EXPORT   = "export"
+	AS       = "as"
 )
 
 type Token struct {
-	Type    TokenType
-	Literal string
+	Type     TokenType
+	Literal  string
+	Position Position
+
+	// NewlineBefore reports whether a line break separates this token from the previous one.
+	NewlineBefore bool
+}
+
+// Position is the location of the first character of a token, counted from 1.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Comment is a line comment, from `//` to the end of the line.
+type Comment struct {
+	Position Position
+	// Text is the content of the comment, without the leading slashes and surrounding spaces.
+	Text string
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
 }
 
 var (
@@ -63,6 +104,8 @@ var (
 	FUNCTION_TOKEN         = New(FUNCTION)
 	RETURN_TOKEN           = New(RETURN)
 	NULL_TOKEN             = New(NULL)
+	TRY_TOKEN              = New(TRY)
+	THROW_TOKEN            = New(THROW)
 )
 
 var keywords = map[string]Token{
@@ -75,6 +118,13 @@ var keywords = map[string]Token{
 	ELSE:     New(ELSE),
 	RETURN:   RETURN_TOKEN,
 	NULL:     NULL_TOKEN,
+	TRY:      TRY_TOKEN,
+	CATCH:    New(CATCH),
+	FINALLY:  New(FINALLY),
+	THROW:    THROW_TOKEN,
+	IMPORT:   New(IMPORT),
+	EXPORT:   New(EXPORT),
+	AS:       New(AS),
 }
 
 var SingleCharacterTokens = map[string]Token{
@@ -85,10 +135,16 @@ var SingleCharacterTokens = map[string]Token{
 	MULTIPLY:          New(MULTIPLY),
 	DIVIDE:            New(DIVIDE),
 	MODULO:            New(MODULO),
+	BIT_AND:           New(BIT_AND),
+	BIT_OR:            New(BIT_OR),
+	BIT_XOR:           New(BIT_XOR),
+	BIT_NOT:           New(BIT_NOT),
 	LESS_THAN:         New(LESS_THAN),
 	GREATER_THAN:      New(GREATER_THAN),
 	COMMA:             New(COMMA),
 	COLON:             New(COLON),
+	DOT:               New(DOT),
+	QUESTION:          New(QUESTION),
 	SEMICOLON:         New(SEMICOLON),
 	LEFT_PARENTHESIS:  LEFT_PARENTHESIS_TOKEN,
 	RIGHT_PARENTHESIS: New(RIGHT_PARENTHESIS),
@@ -103,6 +159,10 @@ var TwoCharacterTokens = map[string]Token{
 	NOT_EQUAL:        New(NOT_EQUAL),
 	LESS_OR_EQUAL:    New(LESS_OR_EQUAL),
 	GREATER_OR_EQUAL: New(GREATER_OR_EQUAL),
+	SHIFT_LEFT:       New(SHIFT_LEFT),
+	SHIFT_RIGHT:      New(SHIFT_RIGHT),
+	POWER:            New(POWER),
+	PIPE:             New(PIPE),
 }
 
 func New(tokenType TokenType) Token {
@@ -117,6 +177,10 @@ func NewString(literal string) Token {
 	return Token{Type: STRING, Literal: literal}
 }
 
+func NewTemplate(literal string) Token {
+	return Token{Type: TEMPLATE, Literal: literal}
+}
+
 func NewKeywordOrIdentifier(literal string) Token {
 	if tok, ok := keywords[literal]; ok {
 		return tok
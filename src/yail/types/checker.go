@@ -0,0 +1,420 @@
+package types
+
+import (
+	"yail/ast"
+	"yail/object"
+	"yail/resolver"
+	"yail/token"
+)
+
+// Check infers the type of every expression of the program from its literals and its type annotations,
+// and reports the values given to an annotated variable, parameter or result that do not match the annotation,
+// as well as the operations on annotated values that can only fail, such as `a + "s"` where `a` is an Int.
+//
+// Variables declared with `var` and parameters without annotation have the type Any, which matches every type,
+// while variables declared with `val` without annotation have the type of their value.
+// Problems that do not involve an annotation are left to the checks made when the program runs,
+// so that code without annotations behaves as before.
+func Check(program *ast.Program) []*object.Error {
+	c := &checker{narrowed: map[*variable]*Type{}}
+	c.enterScope(resolver.DeclaredNames(program), false)
+	c.checkStatements(program.Statements)
+	return c.errors
+}
+
+type checker struct {
+	scope *resolver.Scope[*variable]
+	// result is the annotated result of the function being checked, which is nil when there is no annotation.
+	result *Type
+	// narrowed are the types of the nullable variables that are not null in the branch being checked.
+	narrowed map[*variable]*Type
+	errors   []*object.Error
+}
+
+type variable struct {
+	// typ is nil until the declaration of the variable is checked, as the variable may not be set before.
+	typ       *Type
+	annotated bool
+}
+
+func (c *checker) enterScope(names []string, isCatch bool) {
+	c.scope = resolver.NewScope(c.scope, isCatch)
+	for _, name := range names {
+		c.scope.Add(name, &variable{})
+	}
+}
+
+func (c *checker) leaveScope() {
+	c.scope = c.scope.Outer
+}
+
+// lookup returns the variable an identifier read at the point being checked refers to, or nil if there is none.
+func (c *checker) lookup(name string) *variable {
+	v, _ := c.scope.LookupDeclared(name, func(v *variable) bool { return v.typ != nil })
+	return v
+}
+
+// checkStatements checks the statements of a program or a block and returns the type of the value of the last one,
+// which is nil when the block always leaves with a return or a throw.
+func (c *checker) checkStatements(statements []ast.Statement) *Type {
+	result := Null
+	for _, statement := range statements {
+		result = c.checkStatement(statement)
+	}
+	return result
+}
+
+func (c *checker) checkStatement(node ast.Statement) *Type {
+	switch node := node.(type) {
+	case *ast.ExpressionStatement:
+		return c.infer(node.Expression)
+	case *ast.VariableBindingStatement:
+		c.checkBinding(node)
+	case *ast.ReassignmentStatement:
+		c.checkReassignment(node)
+	case *ast.ReturnStatement:
+		c.checkResult(c.infer(node.ReturnValue), node.Token.Position)
+		return nil
+	case *ast.ThrowStatement:
+		c.infer(node.Value)
+		return nil
+	case *ast.BlockStatement:
+		return c.checkBlock(node)
+	}
+	return Null
+}
+
+func (c *checker) checkBlock(block *ast.BlockStatement) *Type {
+	if block == nil {
+		return Null
+	}
+	return c.checkStatements(block.Statements)
+}
+
+func (c *checker) checkBinding(node *ast.VariableBindingStatement) {
+	value := c.infer(node.Value)
+	v, ok := c.scope.Get(node.Name.Value)
+	if !ok {
+		return
+	}
+	typ := value
+	if node.Type != nil {
+		typ = c.annotation(node.Type)
+		if !value.AssignableTo(typ) {
+			c.addError(node.Name.Token.Position, "'%s' is declared as %s, but is given %s", node.Name.Value, typ, value)
+		}
+	} else if node.Token.Type == token.VAR {
+		typ = Any
+	}
+	if v.typ != nil && !equal(v.typ, typ) {
+		typ = Any // declared in several branches
+	}
+	v.typ, v.annotated = typ, node.Type != nil
+}
+
+func (c *checker) checkReassignment(node *ast.ReassignmentStatement) {
+	value := c.infer(node.Value)
+	v, _ := c.scope.LookupInFunction(node.Name.Value)
+	if v != nil && v.annotated && !value.AssignableTo(v.typ) {
+		c.addError(node.Name.Token.Position, "'%s' is declared as %s, but is given %s", node.Name.Value, v.typ, value)
+	}
+	delete(c.narrowed, v) // the variable may be null again
+}
+
+// nullCheck returns the nullable variable an if condition compares with null, such as `x == null` or `null != x`,
+// and whether the variable is not null in the consequence, as with `!=`, instead of the alternative.
+// The variable is nil when the condition is not such a comparison.
+func (c *checker) nullCheck(condition ast.Expression) (*variable, bool) {
+	infix, ok := condition.(*ast.InfixExpression)
+	if !ok || (infix.Token.Type != token.EQUAL && infix.Token.Type != token.NOT_EQUAL) {
+		return nil, false
+	}
+	operand := infix.LeftNode
+	if _, ok := operand.(*ast.NullExpression); ok {
+		operand = infix.RightNode
+	} else if _, ok := infix.RightNode.(*ast.NullExpression); !ok {
+		return nil, false
+	}
+	identifier, ok := operand.(*ast.IdentifierExpression)
+	if !ok {
+		return nil, false
+	}
+	v := c.lookup(identifier.Value)
+	if v == nil || v.typ == nil || !v.typ.Nullable {
+		return nil, false
+	}
+	return v, infix.Token.Type == token.NOT_EQUAL
+}
+
+// checkNarrowed checks a branch of an if expression in which the variable, unless it is nil, is not null.
+func (c *checker) checkNarrowed(block *ast.BlockStatement, v *variable) *Type {
+	if v == nil {
+		return c.checkBlock(block)
+	}
+	previous, ok := c.narrowed[v]
+	c.narrowed[v] = nonNullable(v.typ)
+	result := c.checkBlock(block)
+	if ok {
+		c.narrowed[v] = previous
+	} else {
+		delete(c.narrowed, v)
+	}
+	return result
+}
+
+// checkResult checks a value returned by the function being checked against its annotated result.
+func (c *checker) checkResult(value *Type, position token.Position) {
+	if c.result != nil && value != nil && !value.AssignableTo(c.result) {
+		c.addError(position, "the function is declared to return %s, but returns %s", c.result, value)
+	}
+}
+
+// infer returns the type of an expression, after checking the expressions it contains.
+func (c *checker) infer(node ast.Expression) *Type {
+	switch node := node.(type) {
+	case *ast.IntegerLiteralExpression:
+		return Int
+	case *ast.StringLiteralExpression:
+		return String
+	case *ast.TemplateLiteral:
+		c.inferAll(node.Parts)
+		return String
+	case *ast.BooleanExpression:
+		return Bool
+	case *ast.NullExpression:
+		return Null
+	case *ast.ArrayLiteral:
+		c.inferAll(node.Elements)
+		return Array
+	case *ast.HashMapLiteral:
+		for _, pair := range node.Pairs {
+			c.infer(pair.Key)
+			c.infer(pair.Value)
+		}
+		return Map
+	case *ast.IdentifierExpression:
+		if v := c.lookup(node.Value); v != nil && v.typ != nil {
+			if narrowed, ok := c.narrowed[v]; ok {
+				return narrowed
+			}
+			return v.typ
+		}
+		return Any // builtin functions, variables of previous programs and variables not set yet
+	case *ast.PrefixExpression:
+		return c.inferPrefix(node)
+	case *ast.InfixExpression:
+		return c.inferInfix(node)
+	case *ast.IfExpression:
+		c.infer(node.Condition)
+		v, notNullInConsequence := c.nullCheck(node.Condition)
+		var consequence, alternative *Type
+		if notNullInConsequence {
+			consequence, alternative = c.checkNarrowed(node.Consequence, v), c.checkBlock(node.Alternative)
+		} else {
+			consequence, alternative = c.checkBlock(node.Consequence), c.checkNarrowed(node.Alternative, v)
+		}
+		if node.Alternative == nil {
+			return joinBranches(consequence, Null)
+		}
+		return joinBranches(consequence, alternative)
+	case *ast.TryExpression:
+		result := c.checkBlock(node.Block)
+		if node.Catch != nil {
+			c.enterScope(resolver.DeclaredNames(node.Catch), true)
+			c.scope.Set(node.CatchParameter.Value, &variable{typ: Error})
+			result = joinBranches(result, c.checkBlock(node.Catch))
+			c.leaveScope()
+		}
+		c.checkBlock(node.Finally)
+		if result == nil {
+			return Any
+		}
+		return result
+	case *ast.FunctionLiteral:
+		return c.inferFunction(node)
+	case *ast.CallExpression:
+		return c.inferCall(node)
+	case *ast.CollectionAccessExpression:
+		return c.inferIndex(node)
+	}
+	return Any
+}
+
+func (c *checker) inferAll(expressions []ast.Expression) []*Type {
+	var types []*Type
+	for _, expression := range expressions {
+		types = append(types, c.infer(expression))
+	}
+	return types
+}
+
+// inferPrefix checks the operand of `-` and `~`, which must be an Int, while `!` accepts any value.
+func (c *checker) inferPrefix(node *ast.PrefixExpression) *Type {
+	right := c.infer(node.RightNode)
+	if node.Token.Type == token.NOT {
+		return annotate(Bool, right.Annotated)
+	}
+	if right.Annotated && !right.Is(ANY) && !right.Is(INT) {
+		c.addError(node.Token.Position, "unknown operator: %s%s", node.Token.Literal, right)
+	}
+	return annotate(Int, right.Annotated)
+}
+
+func (c *checker) inferInfix(node *ast.InfixExpression) *Type {
+	left := c.infer(node.LeftNode)
+	right := c.infer(node.RightNode)
+	operator := node.Token.Type
+	annotated := left.Annotated || right.Annotated
+	switch {
+	case operator == token.EQUAL || operator == token.NOT_EQUAL:
+		return annotate(Bool, annotated)
+	case left.Is(ANY) && right.Is(ANY):
+		return Any
+	case left.Is(ANY):
+		return annotate(infixResult(operator, right), annotated)
+	case right.Is(ANY):
+		return annotate(infixResult(operator, left), annotated)
+	}
+
+	result := Any
+	if left.Name == right.Name {
+		result = infixResult(operator, left)
+	}
+	if result.Is(ANY) && annotated {
+		if left.Name == right.Name {
+			c.addError(node.Token.Position, "unknown operator: %s %s %s", left, node.Token.Literal, right)
+		} else {
+			c.addError(node.Token.Position, "type mismatch: %s %s %s", left, node.Token.Literal, right)
+		}
+	}
+	return annotate(result, annotated)
+}
+
+// infixResult returns the type of the result of an operator applied to values of the given type,
+// or Any when the operator does not support the type.
+func infixResult(operator token.TokenType, operand *Type) *Type {
+	switch {
+	case operand.Is(INT):
+		switch operator {
+		case token.LESS_THAN, token.GREATER_THAN, token.LESS_OR_EQUAL, token.GREATER_OR_EQUAL:
+			return Bool
+		}
+		return Int
+	case operand.Is(STRING) && operator == token.PLUS:
+		return String
+	case operand.Is(FUNC) && (operator == token.SHIFT_LEFT || operator == token.SHIFT_RIGHT):
+		return Func
+	}
+	return Any
+}
+
+// inferFunction checks the body of a function literal, where the parameters and the variables declared anywhere
+// in the body hide the variables of the outer scopes.
+func (c *checker) inferFunction(node *ast.FunctionLiteral) *Type {
+	var parameters []*Type
+	for i := range node.Parameters {
+		parameter := Any
+		if annotation := node.ParameterType(i); annotation != nil {
+			parameter = c.annotation(annotation)
+		}
+		parameters = append(parameters, parameter)
+	}
+	result := Any
+	if node.ReturnType != nil {
+		result = c.annotation(node.ReturnType)
+	}
+	annotated := node.ParameterTypes != nil || node.ReturnType != nil
+
+	c.enterScope(resolver.DeclaredNames(node.Body), false)
+	for i, parameter := range node.Parameters {
+		if v, ok := c.scope.Get(parameter.Value); !ok || v.typ == nil { // the first parameter with a name wins
+			c.scope.Set(parameter.Value, &variable{typ: parameters[i], annotated: node.ParameterType(i) != nil})
+		}
+	}
+	outerResult := c.result
+	c.result = nil
+	if node.ReturnType != nil {
+		c.result = result
+	}
+	value := c.checkBlock(node.Body)
+	if statements := node.Body.Statements; len(statements) > 0 {
+		c.checkResult(value, ast.PositionOf(statements[len(statements)-1]))
+	} else {
+		c.checkResult(value, node.Token.Position)
+	}
+	c.result = outerResult
+	c.leaveScope()
+	return NewFunction(parameters, result, annotated)
+}
+
+func (c *checker) inferCall(node *ast.CallExpression) *Type {
+	function := c.infer(node.Function)
+	arguments := c.inferAll(node.Arguments)
+	if !function.Annotated || function.Is(ANY) {
+		return Any
+	}
+	if !function.Is(FUNC) {
+		c.addError(node.Token.Position, "failed to invoke %s as a function", function)
+		return Any
+	}
+	if function.Parameters == nil {
+		return Any
+	}
+	if len(arguments) != len(function.Parameters) {
+		c.addErrorWithKind(object.ARGUMENT_ERROR, node.Token.Position, "wrong number of arguments: expected %d, but received %d", len(function.Parameters), len(arguments))
+		return function.Result
+	}
+	for i, argument := range arguments {
+		if !argument.AssignableTo(function.Parameters[i]) {
+			c.addError(node.Token.Position, "argument %d of %s expects %s, but is given %s", i+1, node.Function, function.Parameters[i], argument)
+		}
+	}
+	return function.Result
+}
+
+func (c *checker) inferIndex(node *ast.CollectionAccessExpression) *Type {
+	left := c.infer(node.Left)
+	index := c.infer(node.Index)
+	switch {
+	case !left.Annotated && !index.Annotated:
+	case left.Is(ANY) || left.Is(MAP):
+	case left.Is(ARRAY) && (index.Is(INT) || index.Is(ANY)):
+	case left.Is(ERROR) && (index.Is(STRING) || index.Is(ANY)):
+	default:
+		c.addError(node.Token.Position, "unsupported operation: %s[%s]", left, index)
+	}
+	return Any
+}
+
+// annotation returns the type written in an annotation, reporting the names that are not types.
+func (c *checker) annotation(node *ast.TypeAnnotation) *Type {
+	t, ok := Lookup(node.Name, node.Nullable)
+	if !ok {
+		c.addError(node.Token.Position, "unknown type: %s", node.Name)
+		return Any
+	}
+	return t
+}
+
+func (c *checker) addError(position token.Position, format string, a ...interface{}) {
+	c.addErrorWithKind(object.TYPE_ERROR, position, format, a...)
+}
+
+func (c *checker) addErrorWithKind(kind string, position token.Position, format string, a ...interface{}) {
+	err := object.NewErrorWithKind(kind, format, a...)
+	err.Position = position
+	c.errors = append(c.errors, err)
+}
+
+// joinBranches returns the type of a value given by either of two branches, where nil is a branch that never
+// gives a value as it leaves with a return or a throw.
+func joinBranches(a, b *Type) *Type {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	default:
+		return join(a, b)
+	}
+}
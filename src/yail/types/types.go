@@ -0,0 +1,155 @@
+package types
+
+import (
+	"strings"
+	"yail/token"
+)
+
+const (
+	INT    = "Int"
+	STRING = "String"
+	BOOL   = "Bool"
+	NULL   = "Null"
+	ARRAY  = "Array"
+	MAP    = "Map"
+	FUNC   = "Func"
+	ERROR  = "Error" // the value of a catch parameter
+	// ANY is the type of the values that are only known when the program runs, which is compatible with every type.
+	ANY = "Any"
+)
+
+// Type is the static type of an expression.
+type Type struct {
+	Name string
+	// Nullable is true when the value may be null as well.
+	Nullable bool
+	// Parameters and Result describe the signature of a function literal, and are nil for the other types.
+	Parameters []*Type
+	Result     *Type
+	// Annotated is true for the types written in a type annotation, and for the types of the values computed from
+	// annotated values. The checker only reports the problems involving annotated types.
+	Annotated bool
+}
+
+var (
+	Int    = &Type{Name: INT}
+	String = &Type{Name: STRING}
+	Bool   = &Type{Name: BOOL}
+	Null   = &Type{Name: NULL}
+	Array  = &Type{Name: ARRAY}
+	Map    = &Type{Name: MAP}
+	Func   = &Type{Name: FUNC}
+	Error  = &Type{Name: ERROR}
+	Any    = &Type{Name: ANY}
+)
+
+var named = map[string]*Type{
+	INT:    Int,
+	STRING: String,
+	BOOL:   Bool,
+	NULL:   Null,
+	ARRAY:  Array,
+	MAP:    Map,
+	FUNC:   Func,
+	ERROR:  Error,
+	ANY:    Any,
+}
+
+// Lookup returns the type with the given name, as written in a type annotation.
+func Lookup(name string, nullable bool) (*Type, bool) {
+	t, ok := named[name]
+	if !ok {
+		return nil, false
+	}
+	return &Type{Name: t.Name, Nullable: nullable && t != Null && t != Any, Annotated: true}, true
+}
+
+// NewFunction returns the type of a function literal with the given parameters and result.
+func NewFunction(parameters []*Type, result *Type, annotated bool) *Type {
+	return &Type{Name: FUNC, Parameters: parameters, Result: result, Annotated: annotated}
+}
+
+// annotate returns the type marked as annotated when the annotated flag is set.
+func annotate(t *Type, annotated bool) *Type {
+	if !annotated || t.Annotated {
+		return t
+	}
+	copied := *t
+	copied.Annotated = true
+	return &copied
+}
+
+// equal reports whether two types are the same, ignoring where they come from.
+func equal(a, b *Type) bool {
+	return a.Name == b.Name && a.Nullable == b.Nullable && a.Parameters == nil && b.Parameters == nil
+}
+
+func (t *Type) String() string {
+	if t.Nullable {
+		return t.Name + token.QUESTION
+	}
+	if t.Parameters == nil {
+		return t.Name
+	}
+	var parameters []string
+	for _, parameter := range t.Parameters {
+		parameters = append(parameters, parameter.String())
+	}
+	return t.Name + "(" + strings.Join(parameters, ", ") + "): " + t.Result.String()
+}
+
+// Is reports whether the type has the given name, ignoring whether it is nullable.
+func (t *Type) Is(name string) bool {
+	return t.Name == name
+}
+
+// AssignableTo reports whether a value of the type can be used where the target type is expected.
+// Any is assignable to every type and every type is assignable to Any, so that code without annotations
+// is left to the checks made when the program runs.
+func (t *Type) AssignableTo(target *Type) bool {
+	switch {
+	case t.Is(ANY) || target.Is(ANY):
+		return true
+	case t.Is(NULL):
+		return target.Is(NULL) || target.Nullable
+	case t.Nullable && !target.Nullable:
+		return false
+	default:
+		return t.Name == target.Name
+	}
+}
+
+// join returns the type of a value that has either of the two types.
+func join(a, b *Type) *Type {
+	switch {
+	case a == b:
+		return a
+	case equal(a, b):
+		return annotate(a, b.Annotated)
+	case a.Is(ANY) || b.Is(ANY):
+		return Any
+	case a.Is(NULL):
+		return annotate(nullable(b), a.Annotated)
+	case b.Is(NULL):
+		return annotate(nullable(a), b.Annotated)
+	case a.Name == b.Name:
+		// functions lose their signatures
+		return &Type{Name: a.Name, Nullable: a.Nullable || b.Nullable, Annotated: a.Annotated || b.Annotated}
+	default:
+		return Any
+	}
+}
+
+func nullable(t *Type) *Type {
+	if t.Nullable || t.Is(NULL) || t.Is(ANY) {
+		return t
+	}
+	return &Type{Name: t.Name, Nullable: true, Annotated: t.Annotated}
+}
+
+func nonNullable(t *Type) *Type {
+	if !t.Nullable {
+		return t
+	}
+	return &Type{Name: t.Name, Annotated: t.Annotated}
+}
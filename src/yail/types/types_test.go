@@ -0,0 +1,121 @@
+package types
+
+import (
+	"testing"
+	"yail/ast"
+	"yail/lexer"
+	"yail/parser"
+	"yail/utils"
+)
+
+func TestAnnotatedPrograms(t *testing.T) {
+	inputs := []string{
+		"val x: Int = 5; var s: String = \"a\"; s = \"b\"",
+		"val f = func(a: String, b: Int?): Bool { b == null }; f(\"a\", 1); f(\"a\", null)",
+		"val add = func(a: Int, b: Int): Int { a + b }; val sum: Int = add(1, 2) * 3",
+		"val f = func(s: String?): Int { if (s == null) { return 0 }; 1 }",
+		"val f = func(n: Int): Int { if (n > 0) { n } else { throw \"negative\" } }",
+		"val n: Int? = if (true) { 1 }; val a: Any = n; val b: Int = a",
+		"val f = func(g: Func): Func { g >> g }",
+		"try { 1 } catch (e) { val message: String = e.message; message }",
+		"val x: Int = 1; val y = x; val z: Int = y + 1",
+		"val s: String = \"a${1 + 2}\"",
+		"val b: Bool = true; !b; val n: Int = 1; !n",
+		"val f = func(x: Int?): Int { if (x == null) { 0 } else { x } }",
+		"val f = func(x: Int?): Int { if (null != x) { x + 1 } else { 0 } }",
+		"val f = func(x: Int?, y: Int?): Int { if (x != null) { if (y != null) { x + y } else { x } } else { 0 } }",
+	}
+
+	for _, input := range inputs {
+		errors := Check(parse(t, input))
+		if len(errors) > 0 {
+			t.Errorf("%q: unexpected error: %s", input, errors[0].Inspect())
+		}
+	}
+}
+
+func TestUnannotatedProgramsAreNotChecked(t *testing.T) {
+	inputs := []string{
+		"1 + \"a\"",
+		"val x = 1; x + \"a\"",
+		"-true; !5; 1[0]",
+		"val f = func(a) { a }; f(1, 2)",
+		"try { 1 + \"a\" } catch (e) { e.kind }",
+		"var x = 1; x = \"a\"; x + \"b\"",
+		"val f = func(a, b) { a + b }; f(1, \"a\")",
+	}
+
+	for _, input := range inputs {
+		errors := Check(parse(t, input))
+		if len(errors) > 0 {
+			t.Errorf("%q: unexpected error: %s", input, errors[0].Inspect())
+		}
+	}
+}
+
+func TestTypeErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`val x: Int = "a"`, "TypeError: 'x' is declared as Int, but is given String (line 1, column 5)"},
+		{"val x: Int = null", "TypeError: 'x' is declared as Int, but is given Null (line 1, column 5)"},
+		{"val n: Int? = null; val m: Int = n", "TypeError: 'm' is declared as Int, but is given Int? (line 1, column 25)"},
+		{"val x: Int = if (true) { 1 }", "TypeError: 'x' is declared as Int, but is given Int? (line 1, column 5)"},
+		{`var n: Int? = 1; n = null; n = "s"`, "TypeError: 'n' is declared as Int?, but is given String (line 1, column 28)"},
+		{`val a: Int = 1; a + "s"`, "TypeError: type mismatch: Int + String (line 1, column 19)"},
+		{`val a: String = "a"; a - "b"`, "TypeError: unknown operator: String - String (line 1, column 24)"},
+		{"val b: Bool = true; -b", "TypeError: unknown operator: -Bool (line 1, column 21)"},
+		{"val a: Int = 1; val b = a * 2; b + true", "TypeError: type mismatch: Int + Bool (line 1, column 34)"},
+		{"val a: Array = []; a[\"x\"]", "TypeError: unsupported operation: Array[String] (line 1, column 21)"},
+		{"val a: Int = 1; a()", "TypeError: failed to invoke Int as a function (line 1, column 18)"},
+		{`val add = func(a: Int, b: Int): Int { a + b }; add(1, "2")`, "TypeError: argument 2 of add expects Int, but is given String (line 1, column 51)"},
+		{"val f = func(a: Int) { a }; f()", "ArgumentError: wrong number of arguments: expected 1, but received 0 (line 1, column 30)"},
+		{"val f = func(): String { 1 }", "TypeError: the function is declared to return String, but returns Int (line 1, column 26)"},
+		{"val f = func(x: Int): Bool { if (x > 0) { return 1 }; true }", "TypeError: the function is declared to return Bool, but returns Int (line 1, column 43)"},
+		{"val f = func(): Int { val x = 1 }", "TypeError: the function is declared to return Int, but returns Null (line 1, column 23)"},
+		{"val x: Foo = 1", "TypeError: unknown type: Foo (line 1, column 8)"},
+		{"val f = func(x: Int?): Int { if (x == null) { x } else { 0 } }", "TypeError: the function is declared to return Int, but returns Int? (line 1, column 30)"},
+		{"val f = func(x: Int?): Int { if (x != null) { x = null; x } else { 0 } }", "TypeError: the function is declared to return Int, but returns Int? (line 1, column 30)"},
+		{"val n: Int = 1; ~n; val s: String = \"a\"; ~s", "TypeError: unknown operator: ~String (line 1, column 42)"},
+		{"val f = func(e: Int) { try { 1 } catch (e) { val n: Int = e } }", "TypeError: 'n' is declared as Int, but is given Error (line 1, column 50)"},
+	}
+
+	for _, tt := range tests {
+		errors := Check(parse(t, tt.input))
+		utils.ValidateValue(len(errors), 1, t)
+		if len(errors) > 0 {
+			utils.ValidateValue(errors[0].Kind+": "+errors[0].Message+" ("+errors[0].Position.String()+")", tt.expected, t)
+		}
+	}
+}
+
+func TestAssignableTo(t *testing.T) {
+	nullableInt, _ := Lookup(INT, true)
+	tests := []struct {
+		value    *Type
+		target   *Type
+		expected bool
+	}{
+		{Int, Int, true},
+		{Int, nullableInt, true},
+		{Null, nullableInt, true},
+		{nullableInt, Int, false},
+		{Null, Int, false},
+		{Any, Int, true},
+		{String, Any, true},
+		{String, Int, false},
+		{NewFunction([]*Type{Int}, Int, true), Func, true},
+	}
+
+	for _, tt := range tests {
+		utils.ValidateValue(tt.value.AssignableTo(tt.target), tt.expected, t)
+	}
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	utils.ValidateValue(len(p.Errors()), 0, t)
+	return program
+}
@@ -0,0 +1,378 @@
+package utils
+
+import "yail/object"
+
+// Program is a program that both engines run, with the output expected from it.
+type Program struct {
+	Input    string
+	Expected string
+}
+
+// Output returns the text of the result of a program compared with Program.Expected: the traceback of an error,
+// or the value as inspected, where no value is null.
+func Output(result object.Object) string {
+	if result == nil {
+		return object.NULL.Inspect()
+	}
+	if err, ok := result.(*object.Error); ok {
+		return err.Traceback()
+	}
+	return result.Inspect()
+}
+
+// Programs are run by the tests of the evaluator and of the virtual machine, so that the engines give the same
+// results, down to the positions of the errors.
+var Programs = []Program{
+	// expressions, statements, functions and errors
+	{`5`, `5`},
+	{`15`, `15`},
+	{`-10`, `-10`},
+	{`-0`, `0`},
+	{`1 + 2`, `3`},
+	{`1 - 2`, `-1`},
+	{`1 + 2 * 3`, `7`},
+	{`1 + 3 / 2`, `2`},
+	{`1 + 10 % 4`, `3`},
+	{`(1 + 2) * (5 - 2)`, `9`},
+	{`12 & 10`, `8`},
+	{`12 | 10`, `14`},
+	{`12 ^ 10`, `6`},
+	{`~0`, `-1`},
+	{`~5 & 7`, `2`},
+	{`1 << 10`, `1024`},
+	{`-1 << 63`, `-9223372036854775808`},
+	{`1024 >> 3`, `128`},
+	{`-16 >> 2`, `-4`},
+	{`2 ** 10`, `1024`},
+	{`0xFF`, `255`},
+	{`0XfF`, `255`},
+	{`0o755`, `493`},
+	{`0b1010`, `10`},
+	{`1_000_000`, `1000000`},
+	{`0b1111_0000 | 0x0F`, `255`},
+	{`-0x7FFF_FFFF_FFFF_FFFF - 1`, `-9223372036854775808`},
+	{`9223372036854775807`, `9223372036854775807`},
+	{`2 ** 3 ** 2`, `512`},
+	{`-2 ** 2`, `-4`},
+	{`(-2) ** 3`, `-8`},
+	{`5 ** 0`, `1`},
+	{`0 ** 0`, `1`},
+	{`2 ** 62`, `4611686018427387904`},
+	{`(-2) ** 63`, `-9223372036854775808`},
+	{`1 + 2 ** 3 * 2`, `17`},
+	{`240 | 1 << 2 & 12`, `244`},
+	{`"Hello World!"`, `Hello World!`},
+	{`"Hello" + "World"`, `HelloWorld`},
+	{`""`, ``},
+	{`"C:\Users\n\t\\d"`, `C:\Users\n\t\\d`},
+	{`val name = "Yail"; "Hello $name!"`, `Hello Yail!`},
+	{`val user = {"name": "Kim"}; val count = 3; "Hello ${user.name}, you have $count items"`, `Hello Kim, you have 3 items`},
+	{`"${1 + 2} = ${[1, 2][0] + 2}"`, `3 = 3`},
+	{`"${true}, ${null}, ${[1, "a"]}"`, `true, null, [1, a]`},
+	{`val f = func(x) { x * 2; }; "${f(2)}${"-"}${f(3)}"`, `4-6`},
+	{`"costs $5 or \${price}"`, `costs $5 or ${price}`},
+	{"val name = \"Yail\"\nval greeting = \"Hi $name\"\ngreeting", `Hi Yail`},
+	{`"""say "hi" \n $name"""`, `say "hi" \n $name`},
+	{"val query = \"\"\"\n    SELECT *\n      FROM t\n  \"\"\"\nquery", "SELECT *\n  FROM t"},
+	{`val map = {"a": {"b": "c"}}; map.a.b`, `c`},
+	{`true`, `true`},
+	{`false`, `false`},
+	{`!true`, `false`},
+	{`!false`, `true`},
+	{`10 > 5`, `true`},
+	{`10 < 5`, `false`},
+	{`5 < 5`, `false`},
+	{`5 == 5`, `true`},
+	{`5 == true`, `false`},
+	{`true == true`, `true`},
+	{`true == false`, `false`},
+	{`5 != 5`, `false`},
+	{`5 != true`, `true`},
+	{`true != true`, `false`},
+	{`true != false`, `true`},
+	{`5 <= 5`, `true`},
+	{`5 <= 6`, `true`},
+	{`5 >= 5`, `true`},
+	{`5 >= 6`, `false`},
+	{`"Hello" == "Hello"`, `false`},
+	{`"Hello" != "Hello"`, `true`},
+	{`null;`, `null`},
+	{`val x = null; x;`, `null`},
+	{`if (false) { 10 }`, `null`},
+	{`var y = 10; val z = if (true) { y = 15; }; z;`, `null`},
+	{`var a = 5; a;`, `5`},
+	{`var a = 5; val b = a; b;`, `5`},
+	{`var a = 5; a = 10; a;`, `10`},
+	{`var a = 5; val b = 15; a = b; a`, `15`},
+	{`return 10; 9;`, `10`},
+	{`9; return 2 * 5; 9;`, `10`},
+	{`if (10 > 1) { return 10; }`, `10`},
+	{`if (10 > 1) { if (10 > 1) { return 10; } return 1;}`, `10`},
+	{`val f = func(x) { return x; x + 10; }; f(10);`, `10`},
+	{`val f = func(x) { val result = x + 10; return result; return 10;}; f(10);`, `20`},
+	{`if (true) { 10 }`, `10`},
+	{`if (1 < 2) { 10 }`, `10`},
+	{`if (1 > 2) { 10 }`, `null`},
+	{`if (1 > 2) { 10 } else { 20 }`, `20`},
+	{`if (1 < 2) { 10 } else { 20 }`, `10`},
+	{`val identity = func(x) { x; }; identity(5);`, `5`},
+	{`val identity = func(x) { return x; }; identity(5);`, `5`},
+	{`val double = func(x) { x * 2; }; double(5);`, `10`},
+	{`val add = func(x, y) { x + y; }; add(5, 5);`, `10`},
+	{`val add = func(x, y) { x + y; }; add(5 + 5, add(5, 5));`, `20`},
+	{`val callTwoTimes = func(x, f) { f(f(x)); }; callTwoTimes(2, func(x) { x * x; });`, `16`},
+	{`val callTwoTimes = func(x, f) { f(f(x)); }; callTwoTimes(3, func(x) { x * x; });`, `81`},
+	{`val callTwoTimes = func(x, f) { f(f(x)); }; callTwoTimes(1, func(x) { x + 10; });`, `21`},
+	{`val double = func(x) { x * 2; }; 5 |> double;`, `10`},
+	{`val add = func(x, y) { x + y; }; 5 |> add(3);`, `8`},
+	{`val add = func(x, y) { x + y; }; val double = func(x) { x * 2; }; 1 |> add(2) |> double;`, `6`},
+	{`val double = func(x) { x * 2; }; 1 + 2 |> double == 6;`, `true`},
+	{`[1, 2, 3] |> len;`, `3`},
+	{`val arr = [1, 2]; arr |> push(3); arr;`, `[1, 2, 3]`},
+	{`5 |> func(x) { x - 1; };`, `4`},
+	{`val inc = func(x) { x + 1; }; val double = func(x) { x * 2; }; (inc >> double)(5);`, `12`},
+	{`val inc = func(x) { x + 1; }; val double = func(x) { x * 2; }; (inc << double)(5);`, `11`},
+	{`val inc = func(x) { x + 1; }; val f = inc >> inc >> inc; f(0);`, `3`},
+	{`val inc = func(x) { x + 1; }; val lenPlusOne = len >> inc; lenPlusOne([1, 2]);`, `3`},
+	{`val inc = func(x) { x + 1; }; 5 |> inc >> func(x) { x * 10; };`, `60`},
+	{`val inc = func(x) { x + 1; }; val f = inc >> inc; f == f;`, `true`},
+	{"\n\t\t\tval sum = func(x, y) {\n\t\t\t  val result = x +\n\t\t\t    y\n\t\t\t  return result\n\t\t\t}\n\t\t\tvar total = sum(1, 2)\n\t\t\ttotal = total |> sum(\n\t\t\t  10\n\t\t\t)\n\t\t\ttotal", `13`},
+	{"\n\t\t\tvar i = 5; \n\t\t\tval useLocalVariableI = func() { return i; }; \n\t\t\tval useLocalVariableInsideFunction = func() { val i = 15; return i; };\n\t\t\tval returnParameterI = func(i) { return i; }; useLocalVariableI();", `5`},
+	{"\n\t\t\tvar i = 5; \n\t\t\tval useLocalVariableI = func() { return i; }; \n\t\t\tval useLocalVariableInsideFunction = func() { val i = 15; return i; };\n\t\t\tval returnParameterI = func(i) { return i; }; i = 30; useLocalVariableI();", `30`},
+	{"\n\t\t\tvar i = 5; \n\t\t\tval useLocalVariableI = func() { return i; }; \n\t\t\tval useLocalVariableInsideFunction = func() { val i = 15; return i; };\n\t\t\tval returnParameterI = func(i) { return i; }; useLocalVariableInsideFunction();", `15`},
+	{"\n\t\t\tvar i = 5; \n\t\t\tval useLocalVariableI = func() { return i; }; \n\t\t\tval useLocalVariableInsideFunction = func() { val i = 15; return i; };\n\t\t\tval returnParameterI = func(i) { return i; }; i = 30; useLocalVariableInsideFunction();", `15`},
+	{"\n\t\t\tvar i = 5; \n\t\t\tval useLocalVariableI = func() { return i; }; \n\t\t\tval useLocalVariableInsideFunction = func() { val i = 15; return i; };\n\t\t\tval returnParameterI = func(i) { return i; }; returnParameterI(10); i;", `5`},
+	{"\n\t\t\tvar i = 5; \n\t\t\tval useLocalVariableI = func() { return i; }; \n\t\t\tval useLocalVariableInsideFunction = func() { val i = 15; return i; };\n\t\t\tval returnParameterI = func(i) { return i; }; i = 30; returnParameterI(10); i;", `30`},
+	{"\n\t\t\tvar i = 5; \n\t\t\tval useLocalVariableI = func() { return i; }; \n\t\t\tval useLocalVariableInsideFunction = func() { val i = 15; return i; };\n\t\t\tval returnParameterI = func(i) { return i; }; returnParameterI(10);", `10`},
+	{"\n\t\t\tvar i = 5; \n\t\t\tval useLocalVariableI = func() { return i; }; \n\t\t\tval useLocalVariableInsideFunction = func() { val i = 15; return i; };\n\t\t\tval returnParameterI = func(i) { return i; }; i = 30; returnParameterI(10);", `10`},
+	{`val x = 1; val f = func() { if (true) { val x = 2 }; x }; f()`, `2`},
+	{`val x = 1; val f = func(c) { if (c) { val x = 2 }; x }; f(false)`, "Traceback (most recent call last):\n  line 1, column 58, in <main>\n  line 1, column 52, in f\nNameError: identifier not found: x"},
+	{`val f = func() { try { throw 1 } catch (e) { func() { e.value } } }; f()()`, `1`},
+	{`val f = func() { var x = 1; try { throw 2 } catch (e) { val y = e.value; x = x + y }; x }; f()`, `3`},
+	{`try { throw 1 } catch (e) { val leaked = 5; 1 }; leaked`, "Traceback (most recent call last):\n  line 1, column 50, in <main>\nNameError: identifier not found: leaked"},
+	{`val x = 1; val f = func() { val y = x; val x = 2; y }; f()`, `1`},
+	{`val x = 1; val f = func() { val y = x; val x = 2; y + x }; f()`, `3`},
+	{`val x = 1; val f = func() { try { throw 2 } catch (e) { val y = x; val x = e.value; y * 10 + x } }; f()`, `12`},
+	{`val f = func() { val y = z; val z = 2; y }; f()`, "Traceback (most recent call last):\n  line 1, column 26, in <main>\nNameError: identifier not found: z"},
+	{`val f = func() { val x = 1; if (true) { var x = 2 } }`, "Traceback (most recent call last):\n  line 1, column 45, in <main>\nNameError: given identifier 'x' is already declared"},
+	{`val g = func() { try { 1 } catch (e) { val e = 2 } }`, "Traceback (most recent call last):\n  line 1, column 44, in <main>\nNameError: given identifier 'e' is already declared"},
+	{`val f = func() { g() }; val g = func() { 3 }; f()`, `3`},
+	{"\n\t\t\tval newAdder = func(x) {\n\t\t\t  func(y) { x + y };\n\t\t\t};\n\t\t\tval addTwo = newAdder(2);\n\t\t\taddTwo(5);", `7`},
+	{`[1, 2 * 2, 3 + 3]`, `[1, 4, 6]`},
+	{`[1, 2, 3][0]`, `1`},
+	{`[1, 2, 3][1]`, `2`},
+	{`[1, 2, 3][2]`, `3`},
+	{`val i = 0; [1][i];`, `1`},
+	{`[1, 2, 3][1 + 1];`, `3`},
+	{`val arr = [1, 2, 3]; arr[2];`, `3`},
+	{`val arr = [1, 2, 3]; arr[0] + arr[1] + arr[2];`, `6`},
+	{`val arr = [1, 2, 3]; val i = arr[0]; arr[i]`, `2`},
+	{`[1, 2, 3][3]`, `null`},
+	{`[1, 2, 3][-1]`, `null`},
+	{"val two = \"two\";\n\t{\n\t\t\"one\": 10 - 9,\n\t\ttwo: 1 + 1,\n\t\t\"thr\" + \"ee\": 6 / 2,\n\t\t4: 4,\n\t\ttrue: 5,\n\t\tfalse: 6\n\t}", `{one: 1, two: 2, three: 3, 4: 4, true: 5, false: 6}`},
+	{`{"foo": 5}["bar"]`, `null`},
+	{`{}["foo"]`, `null`},
+	{`{"foo": 5}["foo"]`, `5`},
+	{`val key = "foo"; {"foo": 5}[key]`, `5`},
+	{`{100: { "a": 1, "b": 2 }}[100]["a"]`, `1`},
+	{`{true: [10, 20, 30]}[true][0]`, `10`},
+	{`{3: 5}[len([1, 2, 3])]`, `5`},
+	{`{1: 5, "1": 6, true: 7}["1"]`, `6`},
+	{`{0: 5, false: 6}[0]`, `5`},
+	{`{[1, 2]: 5}[[1, 2]]`, `5`},
+	{`{[1, 2]: 5}[[2, 1]]`, `null`},
+	{`{[1, [2]]: 5}[tuple(1, tuple(2))]`, `5`},
+	{`val key = [1]; val h = {key: 5}; push(key, 2); h[key]`, `null`},
+	{`val key = [1]; val h = {key: 5}; push(key, 2); h[[1]]`, `5`},
+	{`tuple(1, 2)[1]`, `2`},
+	{`len(tuple())`, `0`},
+	{`tuple(1, 2) == tuple(1, 2)`, `true`},
+	{`tuple(1, 2) != tuple(1, 2)`, `false`},
+	{`tuple(1, 2) == tuple(2, 1)`, `false`},
+	{`tuple(1) == tuple(1, 2)`, `false`},
+	{`tuple("a", [1, tuple(true)]) == tuple("a", tuple(1, [true]))`, `true`},
+	{`tuple(null, 1) == tuple(null, 1)`, `true`},
+	{`tuple({}) == tuple({})`, `false`},
+	{`val t = tuple(1); t == t`, `true`},
+	{`tuple(1) == 1`, `false`},
+	{`import "maps" as maps; maps.keys({[1, 2]: 3})[0] == tuple(1, 2)`, `true`},
+	{`val a = [1]; push(a, a); tuple(a) == tuple(a)`, `true`},
+	{`import "arrays" as arrays; val a = [1]; push(a, a); len(arrays.distinct([a, [1], a, [1]]))`, `2`},
+	{"val point = func(x, y) {\n\t\t{\"x\": x, \"y\": y, \"hash\": func(p) { p[\"x\"] * 31 + p[\"y\"] }, \"equals\": func(p, q) { if (p[\"x\"] == q[\"x\"]) { p[\"y\"] == q[\"y\"] } else { false } }}\n\t};\n\tval names = {point(0, 0): \"origin\", point(1, 2): \"a\", point(1, 2): \"b\"};\n\t[names[point(1, 2)], names[point(2, 1)], names[[point(0, 0)][0]], len(names), names[tuple(point(0, 0))]]", `[b, null, origin, 2, null]`},
+	{"import \"arrays\" as arrays;\n\tval point = func(x, y) {\n\t\t{\"x\": x, \"y\": y, \"hash\": func(p) { 0 }, \"equals\": func(p, q) { if (p[\"x\"] == q[\"x\"]) { p[\"y\"] == q[\"y\"] } else { false } }}\n\t};\n\tarrays.map(arrays.distinct([point(1, 2), point(2, 1), point(1, 2), point(2, 1)]), func(p) { p[\"x\"] * 10 + p[\"y\"] })", `[12, 21]`},
+	{`len("")`, `0`},
+	{`len("Hello World")`, `11`},
+	{`len("héllo, 世界")`, `9`},
+	{`import "strings" as strings; val s = "héllo"; strings.indexOf(strings.substring(s, 1, len(s)), "o")`, `3`},
+	{`len([])`, `0`},
+	{`len(["a", "b"])`, `2`},
+	{`len({})`, `0`},
+	{`len({"a": 1, 2: [3, 4]})`, `2`},
+	{`head([1, 2, 3])`, `1`},
+	{`head([])`, `null`},
+	{`tail([1, 2, 3])`, `3`},
+	{`tail([])`, `null`},
+	{`val arr = [1, 2, 3]; push(arr, 10); arr;`, `[1, 2, 3, 10]`},
+	{`val arr = [1, 2, 3]; pushleft(arr, 10); arr;`, `[10, 1, 2, 3]`},
+	{`val arr = [1, 2, 3]; pop(arr); arr;`, `[1, 2]`},
+	{`val arr = [1, 2, 3]; popleft(arr); arr;`, `[2, 3]`},
+	{`x`, "Traceback (most recent call last):\n  line 1, column 1, in <main>\nNameError: identifier not found: x"},
+	{`fn(10, 20)`, "Traceback (most recent call last):\n  line 1, column 1, in <main>\nNameError: identifier not found: fn"},
+	{`val a = 5; a = 10;`, "Traceback (most recent call last):\n  line 1, column 12, in <main>\nRuntimeError: can not reassign variables declared with 'val'"},
+	{`5 + true; 10;`, "Traceback (most recent call last):\n  line 1, column 3, in <main>\nTypeError: type mismatch: INTEGER + BOOLEAN"},
+	{`5; -true; 10;`, "Traceback (most recent call last):\n  line 1, column 4, in <main>\nTypeError: unknown operator: -BOOLEAN"},
+	{`true + false; 5;`, "Traceback (most recent call last):\n  line 1, column 6, in <main>\nTypeError: unknown operator: BOOLEAN + BOOLEAN"},
+	{`!5; 10;`, "Traceback (most recent call last):\n  line 1, column 1, in <main>\nTypeError: unknown operator: !INTEGER"},
+	{`var i = 5; val reassignFunc = func() { i = 10; }; reassignFunc();`, "Traceback (most recent call last):\n  line 1, column 40, in <main>\nNameError: identifier not found: 'i'"},
+	{`"Hello" - "World"`, "Traceback (most recent call last):\n  line 1, column 9, in <main>\nTypeError: unknown operator: STRING - STRING"},
+	{`[1, 2, 3]["wrong_index_format"];`, "Traceback (most recent call last):\n  line 1, column 10, in <main>\nTypeError: unsupported operation: ARRAY[STRING]"},
+	{`{"key": "abc"}[func(x) {x}];`, "Traceback (most recent call last):\n  line 1, column 15, in <main>\nTypeError: unusable as hash key: FUNCTION"},
+	{`{[1, null]: 2}`, "Traceback (most recent call last):\n  line 1, column 1, in <main>\nTypeError: unusable as hash key: NULL"},
+	{`val a = [1]; push(a, a); {a: 1}`, "Traceback (most recent call last):\n  line 1, column 26, in <main>\nTypeError: unusable as hash key: ARRAY containing itself"},
+	{`val a = [1]; val t = tuple(a); push(a, t); try { {t: 1} } catch (e) { throw e.message }`, "Traceback (most recent call last):\n  line 1, column 71, in <main>\nError: unusable as hash key: TUPLE containing itself"},
+	{`{{"hash": func(r) { "a" }, "equals": func(r, s) { true }}: 1}`, "Traceback (most recent call last):\n  line 1, column 1, in <main>\nTypeError: the hash function of a record returned STRING instead of an integer"},
+	{`val r = func() { {"hash": func(r) { 1 }, "equals": func(r, s) { 1 }} }; {r(): 1}[r()]`, "Traceback (most recent call last):\n  line 1, column 81, in <main>\nTypeError: the equals function of a record returned INTEGER instead of a boolean"},
+	{`len(1)`, "Traceback (most recent call last):\n  line 1, column 4, in <main>\nTypeError: len(INTEGER) not supported"},
+	{`len("one", "two")`, "Traceback (most recent call last):\n  line 1, column 4, in <main>\nArgumentError: wrong number of arguments: expected 1, but received 2"},
+	{`2 ** 63`, "Traceback (most recent call last):\n  line 1, column 3, in <main>\nArithmeticError: integer overflow: 2 ** 63"},
+	{`3 ** 40`, "Traceback (most recent call last):\n  line 1, column 3, in <main>\nArithmeticError: integer overflow: 3 ** 40"},
+	{`2 ** -1`, "Traceback (most recent call last):\n  line 1, column 3, in <main>\nArithmeticError: negative exponent: 2 ** -1"},
+	{`1 << -1`, "Traceback (most recent call last):\n  line 1, column 3, in <main>\nArithmeticError: negative shift count: 1 << -1"},
+	{`1 << 64`, "Traceback (most recent call last):\n  line 1, column 3, in <main>\nArithmeticError: shift count too large: 1 << 64"},
+	{`-16 >> 70`, "Traceback (most recent call last):\n  line 1, column 5, in <main>\nArithmeticError: shift count too large: -16 >> 70"},
+	{`9223372036854775807 + 1`, "Traceback (most recent call last):\n  line 1, column 21, in <main>\nArithmeticError: integer overflow: 9223372036854775807 + 1"},
+	{`-9223372036854775807 - 2`, "Traceback (most recent call last):\n  line 1, column 22, in <main>\nArithmeticError: integer overflow: -9223372036854775807 - 2"},
+	{`4611686018427387904 * 2`, "Traceback (most recent call last):\n  line 1, column 21, in <main>\nArithmeticError: integer overflow: 4611686018427387904 * 2"},
+	{`val min = -9223372036854775807 - 1; min / -1`, "Traceback (most recent call last):\n  line 1, column 41, in <main>\nArithmeticError: integer overflow: -9223372036854775808 / -1"},
+	{`val min = -9223372036854775807 - 1; -min`, "Traceback (most recent call last):\n  line 1, column 37, in <main>\nArithmeticError: integer overflow: -(-9223372036854775808)"},
+	{`~true`, "Traceback (most recent call last):\n  line 1, column 1, in <main>\nTypeError: unknown operator: ~BOOLEAN"},
+	{`true & false`, "Traceback (most recent call last):\n  line 1, column 6, in <main>\nTypeError: unknown operator: BOOLEAN & BOOLEAN"},
+	{`"a" | 1`, "Traceback (most recent call last):\n  line 1, column 5, in <main>\nTypeError: type mismatch: STRING | INTEGER"},
+	{`"Hello ${name}"`, "Traceback (most recent call last):\n  line 1, column 1, in <main>\nNameError: identifier not found: name"},
+	{`"${1 + true}"`, "Traceback (most recent call last):\n  line 1, column 3, in <main>\nTypeError: type mismatch: INTEGER + BOOLEAN"},
+	{`10 / 0`, "Traceback (most recent call last):\n  line 1, column 4, in <main>\nArithmeticError: division by zero: 10 / 0"},
+	{`10 % 0`, "Traceback (most recent call last):\n  line 1, column 4, in <main>\nArithmeticError: division by zero: 10 % 0"},
+	{`val add = func(x, y) { x + y; }; add(1);`, "Traceback (most recent call last):\n  line 1, column 37, in <main>\nArgumentError: wrong number of arguments: expected 2, but received 1"},
+	{`val inc = func(x) { x + 1; }; (inc >> 5)(1);`, "Traceback (most recent call last):\n  line 1, column 36, in <main>\nTypeError: type mismatch: FUNCTION >> INTEGER"},
+	{`val add = func(x, y) { x + y; }; 1 |> add;`, "Traceback (most recent call last):\n  line 1, column 36, in <main>\nArgumentError: wrong number of arguments: expected 2, but received 1"},
+	{`try { 1 } catch (e) { 2 }`, `1`},
+	{`try { 1 / 0 } catch (e) { 2 }`, `2`},
+	{`var x = 0; try { x = 1; throw 5; x = 2; } catch (e) { x = x + e.value; }; x`, `6`},
+	{`var x = 0; try { 1 / 0 } catch (e) { 2 } finally { x = 10 }; x`, `10`},
+	{`var x = 0; val r = try { 1 } finally { x = 10 }; r + x`, `11`},
+	{`val f = func() { try { return 1; } finally { return 2; } }; f()`, `2`},
+	{`val f = func() { try { throw 1; } catch (e) { return 3; }; 4 }; f()`, `3`},
+	{`val e = 7; try { throw 1 } catch (e) { e.value }; e`, `7`},
+	{`try { try { throw 1 } catch (e) { throw e.value + 1 } } catch (e) { e.value }`, `2`},
+	{`try { try { throw 1 } finally { 2 } } catch (e) { e.value + 10 }`, `11`},
+	{`try { } catch (e) { 1 }`, `null`},
+	{`try { throw "boom" } catch (e) { e.message }`, `boom`},
+	{`try { throw "boom" } catch (e) { e.kind }`, `Error`},
+	{`try { throw error("ValidationError", "bad input") } catch (e) { e.kind + ": " + e.message }`, `ValidationError: bad input`},
+	{`val f = func(c) { if (c) { val x = 1 }; x }; try { f(false) } catch (e) { e.kind }`, `NameError`},
+	{`try { 1 + "a" } catch (e) { e.kind }`, `TypeError`},
+	{`try { 1 % 0 } catch (e) { e.kind }`, `ArithmeticError`},
+	{`try { len(1, 2) } catch (e) { e.kind }`, `ArgumentError`},
+	{`try { val a = 1; a = 2; } catch (e) { e.kind }`, `RuntimeError`},
+	{`try { 1 / 0 } catch (e) { "$e" }`, `ArithmeticError: division by zero: 1 / 0`},
+	{`val inner = func() { throw "x" }; val outer = func() { inner(); 1 }; try { outer() } catch (e) { "${e.stack}" }`, `[inner, outer]`},
+	{`try { func() { 1 / 0 }() } catch (e) { "${e.stack}" }`, `[<anonymous>]`},
+	{`try { try { throw "a" } catch (e) { throw e } } catch (e) { e.message }`, `a`},
+	{`throw "boom"; 5`, "Traceback (most recent call last):\n  line 1, column 1, in <main>\nError: boom"},
+	{`throw [1, 2]`, "Traceback (most recent call last):\n  line 1, column 1, in <main>\nError: [1, 2]"},
+	{`try { 1 / 0 } finally { 5 }`, "Traceback (most recent call last):\n  line 1, column 9, in <main>\nArithmeticError: division by zero: 1 / 0"},
+	{`try { 1 } catch (e) { 2 } finally { throw "final" }`, "Traceback (most recent call last):\n  line 1, column 37, in <main>\nError: final"},
+	{`try { throw "a" } catch (e) { e.unknown }`, "Traceback (most recent call last):\n  line 1, column 32, in <main>\nNameError: unknown error field: unknown"},
+	{`error(1)`, "Traceback (most recent call last):\n  line 1, column 6, in <main>\nTypeError: error(INTEGER) not supported"},
+	{`error()`, "Traceback (most recent call last):\n  line 1, column 6, in <main>\nArgumentError: wrong number of arguments: expected 1 to 2, but received 0"},
+	{`error("a", "b", "c")`, "Traceback (most recent call last):\n  line 1, column 6, in <main>\nArgumentError: wrong number of arguments: expected 1 to 2, but received 3"},
+	{"val inner = func(x) {\n  x / 0\n}\nval outer = func() {\n  val result = [1, 2] |> len |> inner\n  result\n}\nouter()", "Traceback (most recent call last):\n  line 8, column 6, in <main>\n  line 5, column 30, in outer\n  line 2, column 5, in inner\nArithmeticError: division by zero: 2 / 0"},
+	{"val x = 1\nx + y", "Traceback (most recent call last):\n  line 2, column 5, in <main>\nNameError: identifier not found: y"},
+	{"val f = func() { throw 1 }\n\nf()", "Traceback (most recent call last):\n  line 3, column 2, in <main>\n  line 1, column 18, in f\nError: 1"},
+	{"val f = func(g) { g() + 1 }\nf(func() { len(1) })", "Traceback (most recent call last):\n  line 2, column 2, in <main>\n  line 1, column 20, in f\n  line 2, column 15, in <anonymous>\nTypeError: len(INTEGER) not supported"},
+	{"val f = func(g) { g() }\nf(func() { len(1) })", "Traceback (most recent call last):\n  line 2, column 2, in <main>\n  line 2, column 15, in <anonymous>\nTypeError: len(INTEGER) not supported"},
+	{"val f = func() { try { 1 / 0 } catch (e) { throw e } }\nf()", "Traceback (most recent call last):\n  line 2, column 2, in <main>\n  line 1, column 26, in f\nArithmeticError: division by zero: 1 / 0"},
+	{`val sum = func(n, acc) { if (n == 0) { acc } else { sum(n - 1, acc + n) } }; sum(100000, 0)`, `5000050000`},
+	{`val count = func(n) { if (n == 0) { return 0; }; return count(n - 1); }; count(100000)`, `0`},
+	{`val loop = func(n) { if (n > 0) { return n |> dec |> loop; }; n }; val dec = func(n) { n - 1 }; loop(50000)`, `0`},
+	{`val isEven = func(n) { if (n == 0) { true } else { isOdd(n - 1) } }; val isOdd = func(n) { if (n == 0) { false } else { isEven(n - 1) } }; isEven(100001)`, `false`},
+	{`val f = func(n) { if (n == 0) { len([1, 2]) } else { f(n - 1) } }; f(20000)`, `2`},
+	{`val f = func(n) { try { if (n == 0) { throw 1 } else { f(n - 1) } } catch (e) { e.value + n } }; f(3)`, `1`},
+	{`val f = func(n) { if (n == 0) { 1 } else { f(n - 1, 2) } }; f(3)`, "Traceback (most recent call last):\n  line 1, column 62, in <main>\n  line 1, column 45, in f\nArgumentError: wrong number of arguments: expected 1, but received 2"},
+	{`val f = func(n) { 1 + f(n + 1) }; f(0)`, "Traceback (most recent call last):\n  line 1, column 36, in <main>\n  line 1, column 24, in f\n  line 1, column 24, in f\n  line 1, column 24, in f\n  [previous line repeated 9997 more times]\nStackOverflowError: stack overflow: maximum call depth of 10000 exceeded"},
+	{`import "math" as math; import "arrays" as arrays; arrays.range(math.abs(-3))`, `[0, 1, 2]`},
+	{`import "math" as m; import "math" as again; m == again`, `true`},
+	{`import "math" as math; math.pi`, "Traceback (most recent call last):\n  line 1, column 28, in <main>\nNameError: module math does not export 'pi'"},
+	// the standard library and records, calling back into the engines
+	{`val min = -9223372036854775807 - 1; [min * 1, -9223372036854775807 - 1 + 1]`, `[-9223372036854775808, -9223372036854775807]`},
+	{`"say \"hi\"\tnow"`, "Traceback (most recent call last):\n  line 1, column 8, in <main>\nNameError: identifier not found: hi"},
+	{`try { undefined } catch (e) { e.kind }`, "Traceback (most recent call last):\n  line 1, column 7, in <main>\nNameError: identifier not found: undefined"},
+	{`import "arrays" as a; a.map([1, 2, 3], func(x) { x * 2 })`, `[2, 4, 6]`},
+	{`import "arrays" as a; a.reduce(a.filter(a.range(10), func(x) { x % 3 == 0 }), func(sum, x) { sum + x })`, `18`},
+	{`import "arrays" as a; val f = func(x) { if (x == 2) { throw x }; x }; try { a.map([1, 2], f) } catch (e) { e.value }`, `2`},
+	{`import "arrays" as a; a.any([1], func(x) { 1 })`, "Traceback (most recent call last):\n  line 1, column 28, in <main>\nTypeError: arrays.any: the predicate returned INTEGER instead of a boolean"},
+	{`import "arrays" as a; a.map([[1], [2, 3]], len >> func(n) { n * 10 })`, `[10, 20]`},
+	{`import "arrays" as a; a.sortWith(a.sortBy(["bb", "a", "ccc"], len), func(x, y) { len(y) - len(x) })`, `[ccc, bb, a]`},
+	{`import "arrays" as a; try { a.sort([2, null]) } catch (e) { e.kind }`, `ComparisonError`},
+	{`import "strings" as s; s.join(s.split(s.upper(s.trim(" héllo, wörld ")), ", "), s.padStart("|", 2, "é"))`, `HÉLLOé|WÖRLD`},
+	{`import "maps" as m; val h = {"b": 1, "a": 2, 3: 4}; m.delete(h, 3); [m.entries(m.mapValues(h, func(v) { v * 10 })), len(h)]`, `[[[b, 10], [a, 20]], 2]`},
+	{`val h = {"b": 1, "a": 2, 3: [4], true: {}}; [h, h[3]]`, `[{b: 1, a: 2, 3: [4], true: {}}, [4]]`},
+	{`import "arrays" as a; a.groupBy([3, 1, 4, 2, 5], func(x) { x % 2 == 0 })`, `{false: [3, 1, 5], true: [4, 2]}`},
+	{`val grid = {[0, 1]: "a", tuple(1, 0): "b"}; [grid[[0, 1]], grid[tuple(1, 0)], grid[[1]], len(tuple(1, 2)), tuple(1, 2)[1], grid]`, `[a, b, null, 2, 2, {(0, 1): a, (1, 0): b}]`},
+	{`val point = func(x, y) { {"x": x, "y": y, "hash": func(p) { p["x"] }, "equals": func(p, q) { if (p["x"] == q["x"]) { p["y"] == q["y"] } else { false } }} }; val h = {point(1, 2): "a", point(1, 3): "b"}; [h[point(1, 3)], h[point(2, 2)], len(h)]`, `[b, null, 2]`},
+	{`val bad = {"hash": func(p) { "x" }, "equals": func(p, q) { true }}; val h = {bad: 1}`, "Traceback (most recent call last):\n  line 1, column 69, in <main>\nTypeError: the hash function of a record returned STRING instead of an integer"},
+	{`val h = {[1, func() {}]: 1}`, "Traceback (most recent call last):\n  line 1, column 1, in <main>\nTypeError: unusable as hash key: FUNCTION"},
+	// the scopes of functions, blocks and catch blocks
+	{`var i = 5; val get = func() { return i; }; i = 30; get()`, `30`},
+	{`var i = 5; val get = func() { val i = 15; return i; }; get() + i`, `20`},
+	{`var i = 5; val get = func(i) { return i; }; get(10) + i`, `15`},
+	{`var i = 5; val set = func() { i = 10; }; set(); i`, "Traceback (most recent call last):\n  line 1, column 31, in <main>\nNameError: identifier not found: 'i'"},
+	{`val f = func(a, a) { a }; f(1, 2)`, `1`},
+	{`val f = func(a) { var a = 2; a }; f(1)`, "Traceback (most recent call last):\n  line 1, column 23, in <main>\nNameError: given identifier 'a' is already declared"},
+	{`val f = func(a) { a = a + 1; a }; f(1)`, `2`},
+	{`val x = 1; val f = func() { if (false) { val x = 2 }; x }; f()`, "Traceback (most recent call last):\n  line 1, column 61, in <main>\n  line 1, column 55, in f\nNameError: identifier not found: x"},
+	{`val x = 1; val f = func(c) { if (c) { val x = 2 }; x }; f(true) + f(false)`, "Traceback (most recent call last):\n  line 1, column 68, in <main>\n  line 1, column 52, in f\nNameError: identifier not found: x"},
+	{`val f = func() { if (true) { val y = 3 }; y }; f()`, `3`},
+	{`val f = func() { val y = 1; if (true) { val y = 3 }; y }; f()`, "Traceback (most recent call last):\n  line 1, column 45, in <main>\nNameError: given identifier 'y' is already declared"},
+	{`val counter = func() { var n = 0; func() { n = n + 1; n } }; val c = counter(); c(); c(); c()`, "Traceback (most recent call last):\n  line 1, column 44, in <main>\nNameError: identifier not found: 'n'"},
+	{`val outer = func() { val x = 1; val inner = func() { x = 2 }; inner() }; outer()`, "Traceback (most recent call last):\n  line 1, column 54, in <main>\nNameError: identifier not found: 'x'"},
+	{`val f = func() { try { throw 1 } catch (e) { val e = 2 } }; f()`, "Traceback (most recent call last):\n  line 1, column 50, in <main>\nNameError: given identifier 'e' is already declared"},
+	{`val f = func() { try { throw 1 } catch (e) { e = 2 } }; f()`, "Traceback (most recent call last):\n  line 1, column 58, in <main>\n  line 1, column 46, in f\nRuntimeError: can not reassign variables declared with 'val'"},
+	{`val f = func() { val e = 5; try { throw 1 } catch (e) { e.value }; e }; f()`, `5`},
+	{`val x = 1; try { throw 2 } catch (e) { val x = e.value; try { throw 3 } catch (f) { x + f.value } }`, `5`},
+	{`val f = func() { try { throw 1 } catch (e) { val g = func() { e.value + 1 }; g() } }; f()`, `2`},
+	{`try { throw 1 } catch (e) { try { throw 2 } catch (e) { e.value } }`, `2`},
+	{`val e = 5; val f = func() { try { throw 1 } catch (e) { return func() { e } } }; f()()`, `Error: 1`},
+	{`try { try { throw 1 } catch (e) { throw func() { e } } } catch (g) { g.value() }`, `Error: 1`},
+	{`try { throw 1 } catch (e) { try { throw 2 } catch (x) { e.value } }`, `1`},
+	{`try { throw 1 } catch (e) { val y = e.value }; y`, "Traceback (most recent call last):\n  line 1, column 48, in <main>\nNameError: identifier not found: y"},
+	{`val f = func() { var x = 1; try { return x } finally { x = 2 } }; f()`, `1`},
+	{`val f = func() { var x = 1; try { try { return x } finally { x = 2 } } finally { x = 3 } }; f()`, `1`},
+	{`var log = ""; val f = func() { try { try { return 1 } finally { log = log + "a" } } finally { log = log + "b" } }; f(); log`, "Traceback (most recent call last):\n  line 1, column 65, in <main>\nNameError: identifier not found: 'log'"},
+	{`val f = func() { try { throw 1 } catch (e) { return e.value } finally { 5 } }; f()`, `1`},
+	{`val f = func() { try { throw 1 } catch (e) { throw 2 } finally { return 3 } }; f()`, `3`},
+	{`val f = func() { try { 1 } finally { throw 2 } }; try { f() } catch (e) { e.value }`, `2`},
+	{`val f = func() { try { return 1 } finally { 2 } }; 10 + f()`, `11`},
+	{`1 + try { throw 2 } catch (e) { e.value * 10 }`, `21`},
+	{`[1, try { throw 2 } catch (e) { 3 }, 4]`, `[1, 3, 4]`},
+	{`var x = 0; if (1) { x = 1 }; x`, `0`},
+	{`val f = func() {}; f()`, `null`},
+	{`val f = func() { val x = 1 }; f()`, `null`},
+	{`val f = func(n) { n }; f(1, 2)`, "Traceback (most recent call last):\n  line 1, column 25, in <main>\nArgumentError: wrong number of arguments: expected 1, but received 2"},
+	{`val twice = func(f) { f >> f }; val inc = func(x) { x + 1 }; twice(inc)(1)`, `3`},
+	{`val inc = func(x) { throw x }; val f = inc >> inc; try { f(1) } catch (e) { "${e.stack}" }`, `[inc]`},
+	{`val f = func() { g() }; val g = func() { 1 / 0 }; f()`, "Traceback (most recent call last):\n  line 1, column 52, in <main>\n  line 1, column 44, in g\nArithmeticError: division by zero: 1 / 0"},
+	{`val g = func() { return {[1]: 2} }; g()`, `{(1): 2}`},
+	{`val f = func() { missing }; 1`, "Traceback (most recent call last):\n  line 1, column 18, in <main>\nNameError: identifier not found: missing"},
+	{`val a = 1; val a = 2`, "Traceback (most recent call last):\n  line 1, column 16, in <main>\nNameError: given identifier 'a' is already declared"},
+	{`val f = func() { var x = 1; if (true) { val x = 2 } }`, "Traceback (most recent call last):\n  line 1, column 45, in <main>\nNameError: given identifier 'x' is already declared"},
+	{`try { throw 1 } catch (e) { 1 }; e`, "Traceback (most recent call last):\n  line 1, column 34, in <main>\nNameError: identifier not found: e"},
+	{`5(1)`, "Traceback (most recent call last):\n  line 1, column 2, in <main>\nTypeError: failed to invoke INTEGER as a function"},
+	{`val f = func(x) { x }; f`, "fn(x) {\nx;\n}"},
+	{`val f = func(x) { x }; val g = f; g == f`, `true`},
+	{`val a = "x"; a == a`, `true`},
+	{`val h = {"a": 1, "b": [1, 2]}; h`, `{a: 1, b: [1, 2]}`},
+	{`if (true) {}`, `null`},
+	{`val x = 1; x = 2`, "Traceback (most recent call last):\n  line 1, column 12, in <main>\nRuntimeError: can not reassign variables declared with 'val'"},
+	{`return`, `null`},
+}
@@ -0,0 +1,112 @@
+package vm
+
+import (
+	"yail/compiler"
+	"yail/environment"
+	"yail/evaluator"
+	"yail/object"
+	"yail/token"
+)
+
+// Closure is a function created by the virtual machine. It has the type of the functions of the evaluator,
+// so that both can be composed and compared the same way.
+type Closure struct {
+	// Name is the name of the variable the function was first bound to, or empty for anonymous functions.
+	Name     string
+	Function *compiler.CompiledFunction
+	// Scope holds the variables of the function that created the closure.
+	Scope *Scope
+}
+
+func (c *Closure) Type() object.ObjectType {
+	return environment.FUNCTION_OBJ
+}
+
+func (c *Closure) Inspect() string {
+	return c.Function.Inspect()
+}
+
+// Scope holds the variables of a function call or of a catch block. A slot is nil until its variable is declared.
+type Scope struct {
+	slots   []object.Object
+	mutable []bool
+	outer   *Scope
+}
+
+func NewScope(size int, outer *Scope) *Scope {
+	return &Scope{slots: make([]object.Object, size), mutable: make([]bool, size), outer: outer}
+}
+
+// up returns the scope depth levels above this one.
+func (s *Scope) up(depth int) *Scope {
+	scope := s
+	for ; depth > 0; depth-- {
+		scope = scope.outer
+	}
+	return scope
+}
+
+// Globals holds the variables declared outside of any function, by name, so that they outlive a program.
+type Globals struct {
+	values map[string]global
+	// importer is the environment the modules imported by the programs are evaluated from, as modules
+	// are run by the evaluator.
+	importer *environment.Environment
+}
+
+type global struct {
+	value   object.Object
+	mutable bool
+}
+
+func NewGlobals() *Globals {
+	return &Globals{values: make(map[string]global), importer: environment.NewEnvironment()}
+}
+
+func (g *Globals) Get(name string) (object.Object, bool) {
+	variable, ok := g.values[name]
+	return variable.value, ok
+}
+
+// IsDeclared reports whether a program run with the globals declared the variable.
+func (g *Globals) IsDeclared(name string) bool {
+	_, ok := g.values[name]
+	return ok
+}
+
+// IsBuiltin reports whether the name refers to a builtin function, so that Globals can be given to the resolver.
+func (g *Globals) IsBuiltin(name string) bool {
+	return evaluator.IsBuiltin(name)
+}
+
+func (g *Globals) define(name string, value object.Object, mutable bool) *object.Error {
+	if _, ok := g.values[name]; ok {
+		return alreadyDeclared(name)
+	}
+	g.values[name] = global{value: value, mutable: mutable}
+	return nil
+}
+
+func (g *Globals) reassign(name string, value object.Object) *object.Error {
+	variable, ok := g.values[name]
+	if !ok {
+		return notFound(name)
+	}
+	if !variable.mutable {
+		return immutable()
+	}
+	g.values[name] = global{value: value, mutable: true}
+	return nil
+}
+
+func alreadyDeclared(name string) *object.Error {
+	return object.NewErrorWithKind(object.NAME_ERROR, "given identifier '%s' is already declared", name)
+}
+
+func notFound(name string) *object.Error {
+	return object.NewErrorWithKind(object.NAME_ERROR, "identifier not found: '%s'", name)
+}
+
+func immutable() *object.Error {
+	return object.NewError("can not reassign variables declared with '%s'", token.VAL)
+}
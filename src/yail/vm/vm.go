@@ -0,0 +1,520 @@
+package vm
+
+import (
+	"context"
+	"strings"
+	"yail/compiler"
+	"yail/environment"
+	"yail/evaluator"
+	"yail/object"
+	"yail/token"
+)
+
+// VM runs bytecode with the semantics of the evaluator.
+// Values are computed on a stack, while variables are kept in the scopes of the function calls.
+type VM struct {
+	main      *compiler.CompiledFunction
+	constants []object.Object
+	globals   *Globals
+	file      string
+
+	stack    []object.Object
+	frames   []*frame
+	handlers []handler
+	maxDepth int
+	// budget bounds the work done by the program, or is nil when it is unlimited.
+	budget environment.Budget
+}
+
+// frame is a function call in progress. The first frame runs the program itself.
+type frame struct {
+	closure *Closure
+	ip      int
+	// base is the height of the stack when the function was called, below its arguments.
+	base  int
+	scope *Scope
+	// name is the name shown in tracebacks, which changes when a tail call reuses the frame.
+	name     string
+	callSite token.Position
+}
+
+// handler is where to continue when an error is raised inside a try expression.
+type handler struct {
+	frame   int
+	address int
+	stack   int
+	// scope is the scope of the frame when the handler was pushed, which is left by the catch blocks
+	// the error is raised in.
+	scope *Scope
+}
+
+// New creates a virtual machine running the bytecode, with the given global variables.
+func New(bytecode *compiler.Bytecode, globals *Globals) *VM {
+	return &VM{
+		main:      bytecode.Main,
+		constants: bytecode.Constants,
+		globals:   globals,
+		file:      bytecode.File,
+		maxDepth:  environment.DEFAULT_MAX_CALL_DEPTH,
+	}
+}
+
+// SetMaxDepth changes the number of nested calls allowed before a stack overflow error is raised.
+func (vm *VM) SetMaxDepth(depth int) {
+	vm.maxDepth = depth
+}
+
+// Run executes the program, and returns the value of its last statement,
+// nil when the last statement does not produce a value, or the error stopping the program.
+func (vm *VM) Run() object.Object {
+	// the functions of the imported modules call back into the machine for the closures they are given
+	vm.globals.importer.SetForeignCaller(vm.apply)
+	main := &Closure{Function: vm.main, Scope: NewScope(vm.main.NumSlots, nil)}
+	vm.frames = append(vm.frames, &frame{closure: main, scope: main.Scope, name: object.MAIN_FRAME})
+	return vm.run(0)
+}
+
+// RunContext is Run for programs that must be stopped, raising the same fatal errors as evaluator.EvalContext,
+// where a step is an instruction run by the machine.
+func (vm *VM) RunContext(ctx context.Context, limits evaluator.Limits) object.Object {
+	vm.budget = evaluator.NewBudget(ctx, limits)
+	// the functions of the imported modules are run by the evaluator, which shares the budget
+	restore := vm.globals.importer.SetBudget(vm.budget)
+	defer func() {
+		restore()
+		vm.budget = nil
+	}()
+	return vm.Run()
+}
+
+// run executes instructions until the frame at base returns, or until an error is raised that no handler
+// inside that frame catches.
+func (vm *VM) run(base int) object.Object {
+	for {
+		frame := vm.frames[len(vm.frames)-1]
+		function := frame.closure.Function
+		address := frame.ip
+		op := compiler.Opcode(function.Instructions[address])
+		frame.ip += 1
+		if vm.budget != nil {
+			if err := vm.budget.Step(); err != nil {
+				err.Position = function.Positions[address]
+				return vm.raise(err, base)
+			}
+		}
+
+		var err *object.Error
+		switch op {
+		case compiler.OpConstant:
+			constant := vm.constants[vm.readUint16(frame)]
+			if str, ok := constant.(*object.String); ok {
+				constant = object.NewString(str.Value)
+			}
+			err = vm.pushCreated(constant)
+		case compiler.OpNull:
+			vm.push(object.NULL)
+		case compiler.OpTrue:
+			vm.push(object.TRUE)
+		case compiler.OpFalse:
+			vm.push(object.FALSE)
+		case compiler.OpPop:
+			vm.pop()
+		case compiler.OpInfix:
+			operator := compiler.Operators[vm.readUint8(frame)]
+			right := vm.pop()
+			left := vm.pop()
+			err = vm.pushCreated(evaluator.ApplyInfix(operator, left, right))
+		case compiler.OpPrefix:
+			operator := compiler.Operators[vm.readUint8(frame)]
+			err = vm.pushCreated(evaluator.ApplyPrefix(operator, vm.pop()))
+		case compiler.OpIndex:
+			index := vm.pop()
+			left := vm.pop()
+			err = vm.pushResult(evaluator.Index(left, index, vm.applier(function.Positions[address])))
+		case compiler.OpArray:
+			err = vm.pushCreated(object.NewArray(vm.popN(vm.readUint16(frame))))
+		case compiler.OpHash:
+			err = vm.pushCreated(buildHashMap(vm.popN(2*vm.readUint16(frame)), vm.applier(function.Positions[address])))
+		case compiler.OpTemplate:
+			var out strings.Builder
+			for _, part := range vm.popN(vm.readUint16(frame)) {
+				out.WriteString(object.ToString(part))
+			}
+			err = vm.pushCreated(object.NewString(out.String()))
+		case compiler.OpJump:
+			frame.ip = vm.readUint32(frame)
+		case compiler.OpBranch:
+			alternative := vm.readUint32(frame)
+			end := vm.readUint32(frame)
+			switch vm.pop() {
+			case object.TRUE:
+			case object.FALSE:
+				frame.ip = alternative
+			default:
+				vm.push(object.NULL)
+				frame.ip = end
+			}
+		case compiler.OpGetVariable:
+			err = vm.pushResult(vm.getVariable(frame.scope, function.Variables[vm.readUint16(frame)]))
+		case compiler.OpDefineVariable:
+			err = vm.defineVariable(frame.scope, function.Variables[vm.readUint16(frame)], vm.pop())
+		case compiler.OpAssignVariable:
+			err = vm.assignVariable(frame.scope, function.Variables[vm.readUint16(frame)], vm.pop())
+		case compiler.OpBindCatch:
+			frame.scope = NewScope(vm.readUint16(frame), frame.scope)
+			frame.scope.slots[0] = object.NewErrorValue(vm.pop().(*object.Error))
+		case compiler.OpUnbindCatch:
+			frame.scope = frame.scope.outer
+		case compiler.OpClosure:
+			compiled := vm.constants[vm.readUint16(frame)].(*compiler.CompiledFunction)
+			err = vm.pushCreated(&Closure{Function: compiled, Scope: frame.scope})
+		case compiler.OpCall:
+			err = vm.call(vm.readUint8(frame), function.Positions[address])
+		case compiler.OpTailCall:
+			err = vm.tailCall(frame, vm.readUint8(frame), function.Positions[address])
+		case compiler.OpReturnValue:
+			result := vm.pop()
+			index := len(vm.frames) - 1
+			vm.popFrame()
+			if index == base {
+				return result
+			}
+			vm.push(result)
+		case compiler.OpReturnNothing:
+			vm.popFrame()
+			return nil
+		case compiler.OpThrow:
+			value := vm.pop()
+			if errorValue, ok := value.(*object.ErrorValue); ok {
+				err = errorValue.Rethrow()
+			} else {
+				err = object.NewThrownError(value)
+			}
+		case compiler.OpRethrow:
+			err = vm.pop().(*object.Error)
+		case compiler.OpPushHandler:
+			target := vm.readUint32(frame)
+			vm.handlers = append(vm.handlers, handler{frame: len(vm.frames) - 1, address: target, stack: len(vm.stack), scope: frame.scope})
+		case compiler.OpPopHandler:
+			vm.handlers = vm.handlers[:len(vm.handlers)-1]
+		case compiler.OpImport:
+			path := vm.constants[vm.readUint16(frame)].(*object.String).Value
+			err = vm.pushResult(evaluator.Import(vm.globals.importer, vm.file, path))
+		default:
+			err = object.NewError("unknown opcode: %d", op)
+		}
+
+		if err != nil {
+			if err.Position == (token.Position{}) {
+				err.Position = function.Positions[address]
+			}
+			if uncaught := vm.raise(err, base); uncaught != nil {
+				return uncaught
+			}
+		}
+	}
+}
+
+// raise unwinds the stack to the innermost handler of the error, and returns the error
+// when no handler inside the frame at base can catch it.
+func (vm *VM) raise(err *object.Error, base int) *object.Error {
+	if err.Fatal || len(vm.handlers) == 0 || vm.handlers[len(vm.handlers)-1].frame < base {
+		for len(vm.frames) > base {
+			vm.leaveFrame(err)
+		}
+		return err
+	}
+	target := vm.handlers[len(vm.handlers)-1]
+	for len(vm.frames)-1 > target.frame {
+		vm.leaveFrame(err)
+	}
+	vm.handlers = vm.handlers[:len(vm.handlers)-1]
+	vm.stack = vm.stack[:target.stack]
+	vm.push(err)
+	vm.frames[len(vm.frames)-1].ip = target.address
+	vm.frames[len(vm.frames)-1].scope = target.scope
+	return nil
+}
+
+// leaveFrame drops the innermost frame because of an error, which keeps the calls it went through.
+func (vm *VM) leaveFrame(err *object.Error) {
+	if len(vm.frames) > 1 {
+		if err.Stack == nil {
+			err.Stack = vm.callStack()
+		}
+		if err.Position == (token.Position{}) {
+			err.Position = vm.frames[len(vm.frames)-1].callSite
+		}
+	}
+	vm.popFrame()
+}
+
+func (vm *VM) popFrame() {
+	index := len(vm.frames) - 1
+	for len(vm.handlers) > 0 && vm.handlers[len(vm.handlers)-1].frame >= index {
+		vm.handlers = vm.handlers[:len(vm.handlers)-1]
+	}
+	vm.stack = vm.stack[:vm.frames[index].base]
+	vm.frames = vm.frames[:index]
+}
+
+// callStack returns the function calls in progress, from the innermost.
+func (vm *VM) callStack() []object.StackFrame {
+	frames := make([]object.StackFrame, 0, len(vm.frames)-1)
+	for i := len(vm.frames) - 1; i > 0; i-- {
+		frames = append(frames, object.StackFrame{Function: vm.frames[i].name, CallSite: vm.frames[i].callSite})
+	}
+	return frames
+}
+
+// call calls the function below the arguments on the stack. Closures run in a new frame of the current loop,
+// while other functions are applied at once.
+func (vm *VM) call(argc int, callSite token.Position) *object.Error {
+	callee := vm.stack[len(vm.stack)-1-argc]
+	if closure, ok := callee.(*Closure); ok {
+		return vm.pushFrame(closure, argc, callSite)
+	}
+	args := vm.popN(argc)
+	vm.pop()
+	return vm.pushResult(vm.apply(callee, args, callSite))
+}
+
+// tailCall calls a closure in the frame of the caller, which returns the result of the call as it is.
+func (vm *VM) tailCall(frame *frame, argc int, callSite token.Position) *object.Error {
+	closure, ok := vm.stack[len(vm.stack)-1-argc].(*Closure)
+	if !ok {
+		return vm.call(argc, callSite)
+	}
+	if argc != len(closure.Function.Parameters) {
+		return invalidArgumentCount(closure, argc)
+	}
+	if err := vm.allocate(); err != nil {
+		return err
+	}
+	frame.scope = vm.bindArguments(closure, argc)
+	vm.stack = vm.stack[:frame.base]
+	frame.closure = closure
+	frame.ip = 0
+	frame.name = closureName(closure)
+	return nil
+}
+
+func (vm *VM) pushFrame(closure *Closure, argc int, callSite token.Position) *object.Error {
+	if argc != len(closure.Function.Parameters) {
+		return invalidArgumentCount(closure, argc)
+	}
+	if len(vm.frames)-1 >= vm.maxDepth {
+		return object.NewErrorWithKind(object.STACK_OVERFLOW, "stack overflow: maximum call depth of %d exceeded", vm.maxDepth)
+	}
+	if err := vm.allocate(); err != nil {
+		return err
+	}
+	scope := vm.bindArguments(closure, argc)
+	base := len(vm.stack) - 1 - argc
+	vm.stack = vm.stack[:base]
+	vm.frames = append(vm.frames, &frame{closure: closure, base: base, scope: scope, name: closureName(closure), callSite: callSite})
+	return nil
+}
+
+// bindArguments creates the scope of a call, where the arguments on top of the stack are assigned to the parameters.
+func (vm *VM) bindArguments(closure *Closure, argc int) *Scope {
+	scope := NewScope(closure.Function.NumSlots, closure.Scope)
+	args := vm.stack[len(vm.stack)-argc:]
+	for i, slot := range closure.Function.Parameters {
+		if slot >= 0 {
+			scope.slots[slot] = args[i]
+			scope.mutable[slot] = true
+		}
+	}
+	return scope
+}
+
+// apply calls a function outside of the current loop, which is how composed functions call their parts.
+func (vm *VM) apply(fn object.Object, args []object.Object, callSite token.Position) object.Object {
+	switch function := fn.(type) {
+	case *Closure:
+		height := len(vm.stack)
+		vm.push(function)
+		for _, arg := range args {
+			vm.push(arg)
+		}
+		if err := vm.pushFrame(function, len(args), callSite); err != nil {
+			vm.stack = vm.stack[:height]
+			return err
+		}
+		return vm.run(len(vm.frames) - 1)
+	case *environment.ComposedFunction:
+		intermediate := vm.apply(function.First, args, callSite)
+		if _, ok := intermediate.(*object.Error); ok {
+			return intermediate
+		}
+		return vm.apply(function.Second, []object.Object{intermediate}, callSite)
+	case *environment.Function:
+		return evaluator.Apply(function, args, callSite)
+	case *object.Builtin:
+		return function.Fn(vm.applier(callSite), args...)
+	default:
+		return object.NewErrorWithKind(object.TYPE_ERROR, "failed to invoke %s as a function", fn.Type())
+	}
+}
+
+// applier returns the applier through which builtin functions and records call the functions they are given,
+// and charge their work to the budget of the machine.
+func (vm *VM) applier(callSite token.Position) object.Applier {
+	return &vmApplier{vm: vm, callSite: callSite}
+}
+
+type vmApplier struct {
+	vm       *VM
+	callSite token.Position
+}
+
+func (a *vmApplier) Apply(fn object.Object, args ...object.Object) object.Object {
+	return a.vm.apply(fn, args, a.callSite)
+}
+
+func (a *vmApplier) Step() *object.Error {
+	if a.vm.budget == nil {
+		return nil
+	}
+	return a.vm.budget.Step()
+}
+
+func (a *vmApplier) Allocate() *object.Error {
+	return a.vm.allocate()
+}
+
+func (vm *VM) getVariable(scope *Scope, variable *compiler.Variable) object.Object {
+	if !variable.Global {
+		if value := scope.up(variable.Depth).slots[variable.Index]; value != nil {
+			return value
+		}
+		return object.NewErrorWithKind(object.NAME_ERROR, "identifier not found: %s", variable.Name)
+	}
+	if value, ok := vm.globals.Get(variable.Name); ok {
+		return value
+	}
+	return evaluator.LookupBuiltin(variable.Name)
+}
+
+func (vm *VM) defineVariable(scope *Scope, variable *compiler.Variable, value object.Object) *object.Error {
+	if closure, ok := value.(*Closure); ok && closure.Name == "" {
+		closure.Name = variable.Name
+	}
+	if variable.Global {
+		return vm.globals.define(variable.Name, value, variable.Mutable)
+	}
+	slot := variable.Index
+	if scope.slots[slot] != nil {
+		return alreadyDeclared(variable.Name)
+	}
+	scope.slots[slot] = value
+	scope.mutable[slot] = variable.Mutable
+	return nil
+}
+
+func (vm *VM) assignVariable(scope *Scope, variable *compiler.Variable, value object.Object) *object.Error {
+	if variable.Global {
+		return vm.globals.reassign(variable.Name, value)
+	}
+	scope, slot := scope.up(variable.Depth), variable.Index
+	if scope.slots[slot] == nil {
+		return notFound(variable.Name)
+	}
+	if !scope.mutable[slot] {
+		return immutable()
+	}
+	scope.slots[slot] = value
+	return nil
+}
+
+func buildHashMap(keysAndValues []object.Object, apply object.Applier) object.Object {
+	hashMap := object.NewHashMap()
+	for i := 0; i < len(keysAndValues); i += 2 {
+		hashKey, err := object.AsKey(keysAndValues[i], apply)
+		if err != nil {
+			return err
+		}
+		hashMap.Set(hashKey, keysAndValues[i+1])
+		if err := object.KeyError(hashKey); err != nil {
+			return err
+		}
+	}
+	return hashMap
+}
+
+func invalidArgumentCount(closure *Closure, argc int) *object.Error {
+	return object.NewErrorWithKind(object.ARGUMENT_ERROR, object.INVALID_ARGUMENT_COUNT_MESSAGE, len(closure.Function.Parameters), argc)
+}
+
+func closureName(closure *Closure) string {
+	if closure.Name == "" {
+		return "<anonymous>"
+	}
+	return closure.Name
+}
+
+// pushResult pushes the result of an operation, unless it is an error, which is returned instead.
+func (vm *VM) pushResult(result object.Object) *object.Error {
+	if err, ok := result.(*object.Error); ok {
+		return err
+	}
+	vm.push(result)
+	return nil
+}
+
+// pushCreated pushes the result of an instruction creating a value, which counts towards the allocation limit
+// unless it is shared, such as a boolean.
+func (vm *VM) pushCreated(result object.Object) *object.Error {
+	switch result.(type) {
+	case *object.Integer, *object.String, *object.Array, *object.HashMap, *Closure, *environment.ComposedFunction:
+		if err := vm.allocate(); err != nil {
+			return err
+		}
+	}
+	return vm.pushResult(result)
+}
+
+// allocate counts an object created by the program, a value or the scope of a call, towards the allocation limit.
+func (vm *VM) allocate() *object.Error {
+	if vm.budget == nil {
+		return nil
+	}
+	return vm.budget.Allocate()
+}
+
+func (vm *VM) push(obj object.Object) {
+	vm.stack = append(vm.stack, obj)
+}
+
+func (vm *VM) pop() object.Object {
+	obj := vm.stack[len(vm.stack)-1]
+	vm.stack = vm.stack[:len(vm.stack)-1]
+	return obj
+}
+
+// popN pops the n objects on top of the stack, and returns them in the order they were pushed.
+func (vm *VM) popN(n int) []object.Object {
+	objects := make([]object.Object, n)
+	copy(objects, vm.stack[len(vm.stack)-n:])
+	vm.stack = vm.stack[:len(vm.stack)-n]
+	return objects
+}
+
+func (vm *VM) readUint32(frame *frame) int {
+	operand := int(compiler.ReadUint32(frame.closure.Function.Instructions[frame.ip:]))
+	frame.ip += 4
+	return operand
+}
+
+func (vm *VM) readUint16(frame *frame) int {
+	operand := int(compiler.ReadUint16(frame.closure.Function.Instructions[frame.ip:]))
+	frame.ip += 2
+	return operand
+}
+
+func (vm *VM) readUint8(frame *frame) int {
+	operand := int(frame.closure.Function.Instructions[frame.ip])
+	frame.ip += 1
+	return operand
+}
@@ -0,0 +1,177 @@
+package vm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"yail/ast"
+	"yail/compiler"
+	"yail/environment"
+	"yail/evaluator"
+	"yail/lexer"
+	"yail/object"
+	"yail/parser"
+	"yail/resolver"
+	"yail/token"
+	"yail/types"
+	"yail/utils"
+)
+
+func TestPrograms(t *testing.T) {
+	for _, program := range utils.Programs {
+		if output := utils.Output(run(t, program.Input)); output != program.Expected {
+			t.Errorf("%q: expected %q, but the virtual machine returned %q", program.Input, program.Expected, output)
+		}
+	}
+}
+
+func TestLongJumps(t *testing.T) {
+	input := "val x = 1; if (x == 1) { " + strings.Repeat("x + 1; ", 12000) + "x } else { 2 }"
+	utils.ValidateObject(run(t, input), object.NewInteger(1), t)
+	input = "try { " + strings.Repeat("x + 1; ", 12000) + "throw 3 } catch (e) { e.value }"
+	utils.ValidateObject(run(t, "val x = 1; "+input), object.NewInteger(3), t)
+}
+
+func TestGlobalsOutliveProgram(t *testing.T) {
+	c := compiler.New()
+	globals := NewGlobals()
+	var result object.Object
+	for _, input := range []string{"var x = 1", "val inc = func(n) { n + x }", "x = inc(x)", "inc(x)"} {
+		result = New(compile(t, c, globals, input), globals).Run()
+	}
+	utils.ValidateObject(result, object.NewInteger(4), t)
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	module := "export val twice = func(f, x) { f(f(x)) }\nexport val offset = 10"
+	if err := os.WriteFile(filepath.Join(dir, "lib.yail"), []byte(module), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	program := parse(`import "lib.yail" as lib; lib.twice(func(n) { n + lib.offset }, 1)`)
+	program.File = filepath.Join(dir, "main.yail")
+	globals := NewGlobals()
+	if errors := resolver.Resolve(program, globals); len(errors) > 0 {
+		t.Fatalf("failed to resolve: %s", errors[0].Message)
+	}
+	bytecode, err := compiler.New().Compile(program)
+	if err != nil {
+		t.Fatalf("failed to compile: %s", err)
+	}
+	utils.ValidateObject(New(bytecode, globals).Run(), object.NewInteger(21), t)
+}
+
+func TestStackTrace(t *testing.T) {
+	input := `val inner = func(x) {
+  x / 0
+}
+val outer = func() {
+  val result = [1, 2] |> len |> inner
+  result
+}
+outer()`
+	err, ok := run(t, input).(*object.Error)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(err.Position, token.Position{Line: 2, Column: 5}, t)
+	utils.ValidateValue(len(err.Stack), 2, t)
+	utils.ValidateValue(err.Stack[0], object.StackFrame{Function: "inner", CallSite: token.Position{Line: 5, Column: 30}}, t)
+	utils.ValidateValue(err.Stack[1], object.StackFrame{Function: "outer", CallSite: token.Position{Line: 8, Column: 6}}, t)
+}
+
+func TestStackOverflow(t *testing.T) {
+	err, ok := run(t, "val f = func(n) { 1 + f(n + 1) }; f(0)").(*object.Error)
+	utils.ValidateValue(ok, true, t)
+	utils.ValidateValue(err.Kind, object.STACK_OVERFLOW, t)
+	utils.ValidateValue(len(err.Stack), environment.DEFAULT_MAX_CALL_DEPTH, t)
+
+	globals := NewGlobals()
+	machine := New(compile(t, compiler.New(), globals, "val f = func(n) { 1 + f(n + 1) }; try { f(0) } catch (e) { len(e.stack) }"), globals)
+	machine.SetMaxDepth(50)
+	utils.ValidateObject(machine.Run(), object.NewInteger(50), t)
+	utils.ValidateValue(len(machine.frames), 0, t)
+	utils.ValidateValue(len(machine.stack), 0, t)
+}
+
+func TestRunContext(t *testing.T) {
+	loop := "val loop = func(n) { loop(n + 1) }; "
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelExpired()
+
+	tests := []struct {
+		ctx          context.Context
+		input        string
+		limits       evaluator.Limits
+		expectedKind string
+	}{
+		{context.Background(), loop + "loop(0)", evaluator.Limits{MaxSteps: 1000}, object.STEP_LIMIT_EXCEEDED},
+		{context.Background(), loop + "loop(0)", evaluator.Limits{MaxAllocations: 1000}, object.ALLOCATION_LIMIT_EXCEEDED},
+		{context.Background(), "val f = func() { [1, 2, 3] }; f(); f(); f()", evaluator.Limits{MaxAllocations: 10}, object.ALLOCATION_LIMIT_EXCEEDED},
+		{expired, loop + "loop(0)", evaluator.Limits{}, object.DEADLINE_EXCEEDED},
+		{cancelled, "1 + 2", evaluator.Limits{}, object.CANCELLED},
+		{context.Background(), loop + "try { loop(0) } catch (e) { 1 }", evaluator.Limits{MaxSteps: 1000}, object.STEP_LIMIT_EXCEEDED},
+		{context.Background(), loop + "try { loop(0) } finally { 1 }", evaluator.Limits{MaxSteps: 1000}, object.STEP_LIMIT_EXCEEDED},
+		{context.Background(), `import "arrays" as a; a.range(0, 1000000000000)`, evaluator.Limits{MaxSteps: 1000}, object.STEP_LIMIT_EXCEEDED},
+		{context.Background(), `import "arrays" as a; a.range(0, 1000000000000)`, evaluator.Limits{MaxAllocations: 1000}, object.ALLOCATION_LIMIT_EXCEEDED},
+		{expired, `import "arrays" as a; a.range(0, 1000000000000)`, evaluator.Limits{}, object.DEADLINE_EXCEEDED},
+		{context.Background(), `import "strings" as s; s.repeat("a", 100000)`, evaluator.Limits{MaxSteps: 1000}, object.STEP_LIMIT_EXCEEDED},
+		{context.Background(), `import "strings" as s; s.padStart("a", 100000)`, evaluator.Limits{MaxSteps: 1000}, object.STEP_LIMIT_EXCEEDED},
+		{context.Background(), loop + `import "arrays" as a; a.map([1, 2, 3], func(x) { loop(x) })`, evaluator.Limits{MaxSteps: 1000}, object.STEP_LIMIT_EXCEEDED},
+	}
+
+	for _, tt := range tests {
+		globals := NewGlobals()
+		machine := New(compile(t, compiler.New(), globals, tt.input), globals)
+		err, ok := machine.RunContext(tt.ctx, tt.limits).(*object.Error)
+		utils.ValidateValue(ok, true, t)
+		if ok {
+			utils.ValidateValue(err.Kind, tt.expectedKind, t)
+			utils.ValidateValue(err.Fatal, true, t)
+		}
+		utils.ValidateValue(len(machine.frames), 0, t)
+		utils.ValidateValue(globals.importer.Budget() == nil, true, t)
+	}
+
+	globals := NewGlobals()
+	machine := New(compile(t, compiler.New(), globals, "val f = func(n) { if (n == 0) { 0 } else { f(n - 1) } }; f(10)"), globals)
+	limits := evaluator.Limits{MaxSteps: 1000, MaxAllocations: 100}
+	utils.ValidateObject(machine.RunContext(context.Background(), limits), object.NewInteger(0), t)
+}
+
+func parse(input string) *ast.Program {
+	return parser.New(lexer.New(input)).ParseProgram()
+}
+
+// run runs the program like the REPL does, returning the first error of the resolver or of the type checker
+// if there is one.
+func run(t *testing.T, input string) object.Object {
+	globals := NewGlobals()
+	program := parse(input)
+	if errors := resolver.Resolve(program, globals); len(errors) > 0 {
+		return errors[0]
+	}
+	if errors := types.Check(program); len(errors) > 0 {
+		return errors[0]
+	}
+	bytecode, err := compiler.New().Compile(program)
+	if err != nil {
+		t.Fatalf("failed to compile %q: %s", input, err)
+	}
+	return New(bytecode, globals).Run()
+}
+
+func compile(t *testing.T, c *compiler.Compiler, globals *Globals, input string) *compiler.Bytecode {
+	program := parse(input)
+	if errors := resolver.Resolve(program, globals); len(errors) > 0 {
+		t.Fatalf("failed to resolve %q: %s", input, errors[0].Message)
+	}
+	bytecode, err := c.Compile(program)
+	if err != nil {
+		t.Fatalf("failed to compile %q: %s", input, err)
+	}
+	return bytecode
+}